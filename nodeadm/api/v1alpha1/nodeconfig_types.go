@@ -1,8 +1,10 @@
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 func init() {
@@ -63,10 +65,15 @@ type ClusterDetails struct {
 type KubeletOptions struct {
 	// Config is a [`KubeletConfiguration`](https://kubernetes.io/docs/reference/config-api/kubelet-config.v1beta1/)
 	// that will be merged with the defaults.
+	// A map with `$patch: replace` replaces the map from earlier configs instead of being merged into it,
+	// and a map with `$patch: delete` removes it.
 	Config map[string]runtime.RawExtension `json:"config,omitempty"`
 
 	// Flags are [command-line `kubelet` arguments](https://kubernetes.io/docs/reference/command-line-tools-reference/kubelet/).
 	// that will be appended to the defaults.
+	// Each entry is a flag beginning with `-`, or the value of a flag before it that is written without `=`, such as
+	// `k=v` of `["--node-labels", "k=v"]`.
+	// An entry of the form `$delete=--name` removes every earlier flag with that name, along with its value.
 	Flags []string `json:"flags,omitempty"`
 
 	// MaxPodsExpression is a CEL expression used to compute a max pods value for
@@ -74,8 +81,102 @@ type KubeletOptions struct {
 	// over the result of this expression. If the expression is successfully evaluated,
 	// kubeReserved will always be calculated on its result.
 	MaxPodsExpression string `json:"maxPodsExpression,omitempty"`
+
+	// MaxPodsStrategy configures how `nodeadm` calculates the max pods value for the kubelet
+	// configuration, in the same way as the VPC CNI max pods calculator. The result is available
+	// to MaxPodsExpression as `max_pods`.
+	MaxPodsStrategy MaxPodsStrategy `json:"maxPodsStrategy,omitempty"`
+
+	// Labels are added to the `Node` when it registers with the cluster, along with the labels set by `nodeadm`.
+	// Labels are merged by key with those of earlier configs, and take precedence over the labels set by `nodeadm`.
+	// Labels of the `kubernetes.io` and `k8s.io` namespaces are rejected, except those that the kubelet may set on its `Node`, such as the labels of the `node.kubernetes.io` and `kubelet.kubernetes.io` namespaces.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Taints are added to the `Node` when it registers with the cluster.
+	// Taints are merged by key and effect with those of earlier configs.
+	Taints []Taint `json:"taints,omitempty"`
+
+	// AutoLabels enables labels that `nodeadm` detects from the hardware and placement of the instance, and adds to the `Node`:
+	// - `Neuron`: `node.eks.aws/neuron-device-count`, the number of AWS Neuron devices.
+	// - `EFA`: `node.eks.aws/efa-interface-count`, the number of Elastic Fabric Adapter interfaces.
+	// - `InstanceStore`: `node.eks.aws/instance-store-nvme-count`, the number of NVMe instance store volumes.
+	// - `CPU`: `node.eks.aws/cpu-vendor`, along with `node.eks.aws/cpu-feature.avx512` and `node.eks.aws/cpu-feature.amx` when the CPU supports them.
+	// - `Outpost`: `node.eks.aws/outpost-id`, the ID of the Outpost that the instance runs on.
+	// - `InstanceType`: `node.eks.aws/gpu-count`, `node.eks.aws/efa-supported` and `node.eks.aws/trunking-supported`,
+	//   from the instance info of the instance type.
+	AutoLabels []AutoLabel `json:"autoLabels,omitempty"`
+
+	// KubeReservedExpressions are [CEL](https://cel.dev/overview/cel-overview) expressions for the resources reserved
+	// for Kubernetes daemons, which replace the default `kubeReserved` of `nodeadm`.
+	KubeReservedExpressions ReservedResourceExpressions `json:"kubeReservedExpressions,omitempty"`
+
+	// SystemReservedExpressions are [CEL](https://cel.dev/overview/cel-overview) expressions for the resources reserved
+	// for system daemons. By default, `nodeadm` does not set `systemReserved`.
+	SystemReservedExpressions ReservedResourceExpressions `json:"systemReservedExpressions,omitempty"`
+}
+
+// MaxPodsStrategy configures the max pods calculation for the networking of the VPC CNI. By default, the
+// max pods value is the number of secondary IPv4 addresses on the network interfaces of the default network card,
+// plus two for host networking pods.
+type MaxPodsStrategy struct {
+	// PrefixDelegation calculates the max pods value for VPC CNI prefix delegation, where each secondary
+	// IPv4 address is replaced by a `/28` prefix of 16 addresses. Instances on the Xen hypervisor do not
+	// support prefix delegation, and use the default calculation.
+	PrefixDelegation bool `json:"prefixDelegation,omitempty"`
+	// CustomNetworking calculates the max pods value for VPC CNI custom networking, where the primary
+	// network interface is not used for pods.
+	CustomNetworking bool `json:"customNetworking,omitempty"`
+	// CapByVCPU limits the max pods value to 110 on instances with 30 vCPUs or fewer, and to 250 on larger
+	// instances.
+	CapByVCPU bool `json:"capByVCPU,omitempty"`
 }
 
+// ReservedResourceExpressions are [CEL](https://cel.dev/overview/cel-overview) expressions for the amount of each
+// resource to reserve. Each expression must evaluate to a non-negative integer, and can use the variables:
+// - `vcpu_millicores`: the millicores of the online vCPUs.
+// - `memory_mebibytes`: the mebibytes of online memory, less the pre-allocated huge pages.
+// - `max_pods`: the `maxPods` of the kubelet.
+// - `instance_type`: the instance type, e.g. `m5.large`.
+// - `default_reserved`: the amount that `nodeadm` reserves by default, in the unit of the expression.
+type ReservedResourceExpressions struct {
+	// CPU is the millicores of CPU to reserve.
+	CPU string `json:"cpu,omitempty"`
+	// Memory is the mebibytes of memory to reserve.
+	Memory string `json:"memory,omitempty"`
+	// EphemeralStorage is the mebibytes of ephemeral storage to reserve.
+	EphemeralStorage string `json:"ephemeralStorage,omitempty"`
+}
+
+// AutoLabel is a set of labels that `nodeadm` detects on the instance.
+// +kubebuilder:validation:Enum={Neuron,EFA,InstanceStore,CPU,Outpost,InstanceType}
+type AutoLabel string
+
+const (
+	AutoLabelNeuron        AutoLabel = "Neuron"
+	AutoLabelEFA           AutoLabel = "EFA"
+	AutoLabelInstanceStore AutoLabel = "InstanceStore"
+	AutoLabelCPU           AutoLabel = "CPU"
+	AutoLabelOutpost       AutoLabel = "Outpost"
+	AutoLabelInstanceType  AutoLabel = "InstanceType"
+)
+
+// Taint is a [taint](https://kubernetes.io/docs/concepts/scheduling-eviction/taint-and-toleration/) of the `Node`.
+type Taint struct {
+	Key    string      `json:"key"`
+	Value  string      `json:"value,omitempty"`
+	Effect TaintEffect `json:"effect"`
+}
+
+// TaintEffect is the effect of a taint on pods that do not tolerate it.
+// +kubebuilder:validation:Enum={NoSchedule,PreferNoSchedule,NoExecute}
+type TaintEffect string
+
+const (
+	TaintEffectNoSchedule       TaintEffect = "NoSchedule"
+	TaintEffectPreferNoSchedule TaintEffect = "PreferNoSchedule"
+	TaintEffectNoExecute        TaintEffect = "NoExecute"
+)
+
 // ContainerdOptions are additional parameters passed to `containerd`.
 type ContainerdOptions struct {
 	// Config is an inline [`containerd` configuration TOML](https://github.com/containerd/containerd/blob/main/docs/man/containerd-config.toml.5.md)
@@ -84,15 +185,191 @@ type ContainerdOptions struct {
 
 	// BaseRuntimeSpec is the OCI runtime specification upon which all containers will be based.
 	// The provided spec will be merged with the default spec; so that a partial spec may be provided.
+	// Maps support the same `$patch` directives as the kubelet config.
 	// For more information, see: https://github.com/opencontainers/runtime-spec
 	BaseRuntimeSpec map[string]runtime.RawExtension `json:"baseRuntimeSpec,omitempty"`
+
+	// Registries configure how `containerd` pulls images from each registry, keyed by the registry host, e.g. `docker.io`
+	// or `registry.example.com:5000`. The key `_default` applies to every registry without its own entry. Each registry
+	// is written to `/etc/containerd/certs.d/<host>/hosts.toml`.
+	// For more information, see: https://github.com/containerd/containerd/blob/main/docs/hosts.md
+	Registries map[string]RegistryOptions `json:"registries,omitempty"`
+
+	// Runtimes are additional runtime handlers of `containerd`, which pods select with a
+	// [RuntimeClass](https://kubernetes.io/docs/concepts/containers/runtime-class/) of the same handler name.
+	// A runtime with the name of a runtime configured by `nodeadm`, such as `runc`, replaces it.
+	Runtimes []ContainerdRuntime `json:"runtimes,omitempty"`
+
+	// PrefetchImages are pulled through the CRI plugin of `containerd` once it is running and before `kubelet` is
+	// started, so that the pods of critical DaemonSets don't wait for their images. The images are pulled for the
+	// platform of the node, with the mirrors of `registries` and the snapshotter of `containerd`. Images of registries
+	// that `kubelet` uses the ECR credential provider for are pulled with its credentials, or without credentials if
+	// the provider fails. A pull that fails or times out does not fail `nodeadm init`, as `kubelet` pulls the image
+	// again when it's needed.
+	PrefetchImages []PrefetchImage `json:"prefetchImages,omitempty"`
+
+	// PrefetchImagesTimeout is how long `nodeadm init` waits for the `prefetchImages` to be pulled, which delays the
+	// start of `kubelet`, e.g. `90s`. By default, it is `5m`.
+	PrefetchImagesTimeout *metav1.Duration `json:"prefetchImagesTimeout,omitempty"`
+
+	// SandboxImage is the image of the pause container that holds the namespaces of each pod, e.g.
+	// `registry.example.com/eks/pause:3.10`. By default, it is the pause image cached on the AMI. If the image does not
+	// exist once `containerd` is running and the `imageArchives` are imported, it is pulled, and `nodeadm init` fails
+	// when it cannot be pulled.
+	SandboxImage string `json:"sandboxImage,omitempty"`
+
+	// ImageArchives are the absolute paths of image archives, such as those written by `ctr images export`, which are
+	// imported into the snapshotter of `containerd` once it is running, e.g. to provide the sandbox image on a node
+	// without access to its registry.
+	ImageArchives []string `json:"imageArchives,omitempty"`
+
+	// SOCI configures the [SOCI snapshotter](https://github.com/awslabs/soci-snapshotter), which `containerd` pulls
+	// images with when the `FastImagePull` feature is enabled and the instance is large enough.
+	SOCI SOCIOptions `json:"soci,omitempty"`
+}
+
+// SOCIOptions configure when the SOCI snapshotter is used, and how it pulls the layers of images in parallel.
+type SOCIOptions struct {
+	// MinMemory is the online memory that the instance must have for the SOCI snapshotter to be used, e.g. `16Gi`.
+	// By default, it is `7Gi`.
+	MinMemory *resource.Quantity `json:"minMemory,omitempty"`
+
+	// MinCPU is the number of CPUs that the instance must have for the SOCI snapshotter to be used, e.g. `8` or
+	// `3500m`. By default, it is `4`.
+	MinCPU *resource.Quantity `json:"minCPU,omitempty"`
+
+	// MaxConcurrentDownloads is the maximum number of layers that are downloaded at once, or `-1` for no limit.
+	// By default, there is no limit.
+	// +kubebuilder:validation:Minimum=-1
+	MaxConcurrentDownloads int32 `json:"maxConcurrentDownloads,omitempty"`
+
+	// MaxConcurrentDownloadsPerImage is the maximum number of layers of an image that are downloaded at once, or `-1`
+	// for no limit. By default, it is `20`.
+	// +kubebuilder:validation:Minimum=-1
+	MaxConcurrentDownloadsPerImage int32 `json:"maxConcurrentDownloadsPerImage,omitempty"`
+
+	// MaxConcurrentUnpacks is the maximum number of layers that are unpacked at once, or `-1` for no limit.
+	// By default, there is no limit.
+	// +kubebuilder:validation:Minimum=-1
+	MaxConcurrentUnpacks int32 `json:"maxConcurrentUnpacks,omitempty"`
+
+	// MaxConcurrentUnpacksPerImage is the maximum number of layers of an image that are unpacked at once, or `-1` for
+	// no limit. By default, it is `12`.
+	// +kubebuilder:validation:Minimum=-1
+	MaxConcurrentUnpacksPerImage int32 `json:"maxConcurrentUnpacksPerImage,omitempty"`
+
+	// Config is inline TOML that is merged into the config of the SOCI snapshotter,
+	// `/etc/soci-snapshotter-grpc/config.toml`, and takes precedence over the settings above.
+	// For more information, see: https://github.com/awslabs/soci-snapshotter/blob/main/docs/config.md
+	Config string `json:"config,omitempty"`
+}
+
+// ContainerdRuntime is a runtime handler of `containerd`.
+type ContainerdRuntime struct {
+	// Name is the name of the runtime handler.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// BinaryPath is the absolute path of the runtime binary. For the `io.containerd.runc.v2` runtime type, it is the
+	// `BinaryName` option of the OCI runtime, e.g. `/usr/bin/nvidia-container-runtime`. For other runtime types, it is
+	// the path of the shim binary.
+	BinaryPath string `json:"binaryPath,omitempty"`
+
+	// RuntimeType is the type of the runtime, e.g. `io.containerd.runsc.v1` for gVisor or `io.containerd.kata.v2` for
+	// Kata Containers. By default, it is `io.containerd.runc.v2`.
+	RuntimeType string `json:"runtimeType,omitempty"`
+
+	// Options are the options of the runtime, which depend on the runtime type. For the `io.containerd.runc.v2`
+	// runtime type, they are merged with the default options, which set `BinaryName` and `SystemdCgroup`.
+	Options map[string]runtime.RawExtension `json:"options,omitempty"`
+
+	// Default makes the runtime the default runtime of `containerd`, which is used by pods without a RuntimeClass.
+	Default bool `json:"default,omitempty"`
 }
 
+// PrefetchImage is an image that is pulled when the node is initialized.
+type PrefetchImage struct {
+	// Reference is the reference of the image, e.g. `public.ecr.aws/eks/aws-load-balancer-controller:v2.7.1`.
+	Reference string `json:"reference"`
+
+	// Pin adds the image to the pinned images of `containerd`, so that it's never removed by image garbage collection.
+	Pin bool `json:"pin,omitempty"`
+}
+
+// RegistryOptions configure the hosts that `containerd` pulls the images of a registry from.
+type RegistryOptions struct {
+	// Server overrides the URL of the registry, which is tried after the mirrors. By default, it is `https://<host>`.
+	Server string `json:"server,omitempty"`
+
+	// Mirrors are tried in order before the server of the registry.
+	Mirrors []RegistryMirror `json:"mirrors,omitempty"`
+
+	// CA is a PEM-encoded bundle of certificate authorities to verify the server of the registry.
+	CA string `json:"ca,omitempty"`
+
+	// SkipVerify disables TLS verification of the server of the registry.
+	SkipVerify bool `json:"skipVerify,omitempty"`
+}
+
+// RegistryMirror is a host that serves the images of a registry.
+type RegistryMirror struct {
+	// URL is the URL of the mirror, e.g. `https://mirror.example.com`.
+	URL string `json:"url"`
+
+	// Capabilities are the operations the mirror is used for. By default, the mirror is used to `pull` and `resolve`.
+	Capabilities []RegistryCapability `json:"capabilities,omitempty"`
+
+	// CA is a PEM-encoded bundle of certificate authorities to verify the mirror.
+	CA string `json:"ca,omitempty"`
+
+	// SkipVerify disables TLS verification of the mirror.
+	SkipVerify bool `json:"skipVerify,omitempty"`
+
+	// OverridePath indicates that the URL includes the API root of the mirror, so `/v2` is not appended to it.
+	OverridePath bool `json:"overridePath,omitempty"`
+}
+
+// RegistryCapability is an operation that `containerd` uses a registry host for.
+// +kubebuilder:validation:Enum={pull,resolve,push}
+type RegistryCapability string
+
+const (
+	RegistryCapabilityPull    RegistryCapability = "pull"
+	RegistryCapabilityResolve RegistryCapability = "resolve"
+	RegistryCapabilityPush    RegistryCapability = "push"
+)
+
 // InstanceOptions determines how the node's operating system and devices are configured.
 type InstanceOptions struct {
 	LocalStorage LocalStorageOptions `json:"localStorage,omitempty"`
 	Environment  EnvironmentOptions  `json:"environment,omitempty"`
 	Network      NetworkOptions      `json:"network,omitempty"`
+
+	// Sysctls are kernel parameters, keyed by name, e.g. `net.core.somaxconn`. They are written to a drop-in file under
+	// `/etc/sysctl.d/` so that they persist across reboots, and applied before the kubelet starts. The drop-in file is
+	// removed when there are no sysctls.
+	// Since the kubelet is configured with `protectKernelDefaults`, the kernel parameters that it requires cannot be changed.
+	Sysctls map[string]string `json:"sysctls,omitempty"`
+
+	Kernel KernelOptions `json:"kernel,omitempty"`
+
+	// Hugepages are the [huge pages](https://www.kernel.org/doc/html/latest/admin-guide/mm/hugetlbpage.html) to
+	// pre-allocate, keyed by page size, e.g. `2Mi` or `1Gi`. Each value is either a number of pages, or a percentage
+	// of the online memory, e.g. `10%`. The pages are spread evenly across the NUMA nodes of the instance.
+	// Pre-allocated huge pages are not allocatable as memory by pods, but as the `hugepages-<size>` resource.
+	Hugepages map[string]intstr.IntOrString `json:"hugepages,omitempty"`
+}
+
+// KernelOptions configure the kernel modules and the kernel command-line of the instance.
+type KernelOptions struct {
+	// Modules are kernel modules, e.g. `ip_vs`, that are loaded before the kubelet starts and the sysctls are applied,
+	// and on every boot through `/etc/modules-load.d/`.
+	Modules []string `json:"modules,omitempty"`
+
+	// Args are kernel command-line arguments, e.g. `transparent_hugepage=never`, that are added to the boot entries.
+	// When the running kernel was not booted with all of them, the instance is rebooted so that they take effect.
+	// The instance is rebooted at most once, which is recorded in the config cache.
+	Args []string `json:"args,omitempty"`
 }
 
 // NetworkOptions are parameters used to configure networking on the host OS.
@@ -5,7 +5,9 @@
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -43,6 +45,36 @@ func (in *ContainerdOptions) DeepCopyInto(out *ContainerdOptions) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.Registries != nil {
+		in, out := &in.Registries, &out.Registries
+		*out = make(map[string]RegistryOptions, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Runtimes != nil {
+		in, out := &in.Runtimes, &out.Runtimes
+		*out = make([]ContainerdRuntime, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PrefetchImages != nil {
+		in, out := &in.PrefetchImages, &out.PrefetchImages
+		*out = make([]PrefetchImage, len(*in))
+		copy(*out, *in)
+	}
+	if in.PrefetchImagesTimeout != nil {
+		in, out := &in.PrefetchImagesTimeout, &out.PrefetchImagesTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ImageArchives != nil {
+		in, out := &in.ImageArchives, &out.ImageArchives
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.SOCI.DeepCopyInto(&out.SOCI)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerdOptions.
@@ -55,6 +87,28 @@ func (in *ContainerdOptions) DeepCopy() *ContainerdOptions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerdRuntime) DeepCopyInto(out *ContainerdRuntime) {
+	*out = *in
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make(map[string]runtime.RawExtension, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerdRuntime.
+func (in *ContainerdRuntime) DeepCopy() *ContainerdRuntime {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerdRuntime)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in EnvironmentOptions) DeepCopyInto(out *EnvironmentOptions) {
 	{
@@ -110,6 +164,21 @@ func (in *InstanceOptions) DeepCopyInto(out *InstanceOptions) {
 		}
 	}
 	in.Network.DeepCopyInto(&out.Network)
+	if in.Sysctls != nil {
+		in, out := &in.Sysctls, &out.Sysctls
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Kernel.DeepCopyInto(&out.Kernel)
+	if in.Hugepages != nil {
+		in, out := &in.Hugepages, &out.Hugepages
+		*out = make(map[string]intstr.IntOrString, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceOptions.
@@ -122,6 +191,31 @@ func (in *InstanceOptions) DeepCopy() *InstanceOptions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KernelOptions) DeepCopyInto(out *KernelOptions) {
+	*out = *in
+	if in.Modules != nil {
+		in, out := &in.Modules, &out.Modules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KernelOptions.
+func (in *KernelOptions) DeepCopy() *KernelOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(KernelOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KubeletOptions) DeepCopyInto(out *KubeletOptions) {
 	*out = *in
@@ -137,6 +231,26 @@ func (in *KubeletOptions) DeepCopyInto(out *KubeletOptions) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	out.MaxPodsStrategy = in.MaxPodsStrategy
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Taints != nil {
+		in, out := &in.Taints, &out.Taints
+		*out = make([]Taint, len(*in))
+		copy(*out, *in)
+	}
+	if in.AutoLabels != nil {
+		in, out := &in.AutoLabels, &out.AutoLabels
+		*out = make([]AutoLabel, len(*in))
+		copy(*out, *in)
+	}
+	out.KubeReservedExpressions = in.KubeReservedExpressions
+	out.SystemReservedExpressions = in.SystemReservedExpressions
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeletOptions.
@@ -169,6 +283,21 @@ func (in *LocalStorageOptions) DeepCopy() *LocalStorageOptions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaxPodsStrategy) DeepCopyInto(out *MaxPodsStrategy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaxPodsStrategy.
+func (in *MaxPodsStrategy) DeepCopy() *MaxPodsStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(MaxPodsStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkOptions) DeepCopyInto(out *NetworkOptions) {
 	*out = *in
@@ -277,3 +406,115 @@ func (in *NodeConfigSpec) DeepCopy() *NodeConfigSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrefetchImage) DeepCopyInto(out *PrefetchImage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrefetchImage.
+func (in *PrefetchImage) DeepCopy() *PrefetchImage {
+	if in == nil {
+		return nil
+	}
+	out := new(PrefetchImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryMirror) DeepCopyInto(out *RegistryMirror) {
+	*out = *in
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = make([]RegistryCapability, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryMirror.
+func (in *RegistryMirror) DeepCopy() *RegistryMirror {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryMirror)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryOptions) DeepCopyInto(out *RegistryOptions) {
+	*out = *in
+	if in.Mirrors != nil {
+		in, out := &in.Mirrors, &out.Mirrors
+		*out = make([]RegistryMirror, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryOptions.
+func (in *RegistryOptions) DeepCopy() *RegistryOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReservedResourceExpressions) DeepCopyInto(out *ReservedResourceExpressions) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReservedResourceExpressions.
+func (in *ReservedResourceExpressions) DeepCopy() *ReservedResourceExpressions {
+	if in == nil {
+		return nil
+	}
+	out := new(ReservedResourceExpressions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SOCIOptions) DeepCopyInto(out *SOCIOptions) {
+	*out = *in
+	if in.MinMemory != nil {
+		in, out := &in.MinMemory, &out.MinMemory
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MinCPU != nil {
+		in, out := &in.MinCPU, &out.MinCPU
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SOCIOptions.
+func (in *SOCIOptions) DeepCopy() *SOCIOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(SOCIOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Taint) DeepCopyInto(out *Taint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Taint.
+func (in *Taint) DeepCopy() *Taint {
+	if in == nil {
+		return nil
+	}
+	out := new(Taint)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,520 @@
+//go:build !ignore_autogenerated
+
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterDetails) DeepCopyInto(out *ClusterDetails) {
+	*out = *in
+	if in.CertificateAuthority != nil {
+		in, out := &in.CertificateAuthority, &out.CertificateAuthority
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+	if in.EnableOutpost != nil {
+		in, out := &in.EnableOutpost, &out.EnableOutpost
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDetails.
+func (in *ClusterDetails) DeepCopy() *ClusterDetails {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterDetails)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerdOptions) DeepCopyInto(out *ContainerdOptions) {
+	*out = *in
+	if in.BaseRuntimeSpec != nil {
+		in, out := &in.BaseRuntimeSpec, &out.BaseRuntimeSpec
+		*out = make(map[string]runtime.RawExtension, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Registries != nil {
+		in, out := &in.Registries, &out.Registries
+		*out = make(map[string]RegistryOptions, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Runtimes != nil {
+		in, out := &in.Runtimes, &out.Runtimes
+		*out = make([]ContainerdRuntime, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PrefetchImages != nil {
+		in, out := &in.PrefetchImages, &out.PrefetchImages
+		*out = make([]PrefetchImage, len(*in))
+		copy(*out, *in)
+	}
+	if in.PrefetchImagesTimeout != nil {
+		in, out := &in.PrefetchImagesTimeout, &out.PrefetchImagesTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ImageArchives != nil {
+		in, out := &in.ImageArchives, &out.ImageArchives
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.SOCI.DeepCopyInto(&out.SOCI)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerdOptions.
+func (in *ContainerdOptions) DeepCopy() *ContainerdOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerdOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerdRuntime) DeepCopyInto(out *ContainerdRuntime) {
+	*out = *in
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = make(map[string]runtime.RawExtension, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerdRuntime.
+func (in *ContainerdRuntime) DeepCopy() *ContainerdRuntime {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerdRuntime)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in EnvironmentOptions) DeepCopyInto(out *EnvironmentOptions) {
+	{
+		in := &in
+		*out = make(EnvironmentOptions, len(*in))
+		for key, val := range *in {
+			var outVal map[string]string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make(map[string]string, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentOptions.
+func (in EnvironmentOptions) DeepCopy() EnvironmentOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(EnvironmentOptions)
+	in.DeepCopyInto(out)
+	return *out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceOptions) DeepCopyInto(out *InstanceOptions) {
+	*out = *in
+	in.LocalStorage.DeepCopyInto(&out.LocalStorage)
+	if in.Environment != nil {
+		in, out := &in.Environment, &out.Environment
+		*out = make(EnvironmentOptions, len(*in))
+		for key, val := range *in {
+			var outVal map[string]string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make(map[string]string, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
+	in.Network.DeepCopyInto(&out.Network)
+	if in.Sysctls != nil {
+		in, out := &in.Sysctls, &out.Sysctls
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Kernel.DeepCopyInto(&out.Kernel)
+	if in.Hugepages != nil {
+		in, out := &in.Hugepages, &out.Hugepages
+		*out = make(map[string]intstr.IntOrString, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceOptions.
+func (in *InstanceOptions) DeepCopy() *InstanceOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KernelOptions) DeepCopyInto(out *KernelOptions) {
+	*out = *in
+	if in.Modules != nil {
+		in, out := &in.Modules, &out.Modules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KernelOptions.
+func (in *KernelOptions) DeepCopy() *KernelOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(KernelOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeletOptions) DeepCopyInto(out *KubeletOptions) {
+	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]runtime.RawExtension, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Flags != nil {
+		in, out := &in.Flags, &out.Flags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.MaxPodsStrategy = in.MaxPodsStrategy
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Taints != nil {
+		in, out := &in.Taints, &out.Taints
+		*out = make([]Taint, len(*in))
+		copy(*out, *in)
+	}
+	if in.AutoLabels != nil {
+		in, out := &in.AutoLabels, &out.AutoLabels
+		*out = make([]AutoLabel, len(*in))
+		copy(*out, *in)
+	}
+	out.KubeReservedExpressions = in.KubeReservedExpressions
+	out.SystemReservedExpressions = in.SystemReservedExpressions
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeletOptions.
+func (in *KubeletOptions) DeepCopy() *KubeletOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeletOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalStorageOptions) DeepCopyInto(out *LocalStorageOptions) {
+	*out = *in
+	if in.DisabledMounts != nil {
+		in, out := &in.DisabledMounts, &out.DisabledMounts
+		*out = make([]DisabledMount, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalStorageOptions.
+func (in *LocalStorageOptions) DeepCopy() *LocalStorageOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalStorageOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaxPodsStrategy) DeepCopyInto(out *MaxPodsStrategy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaxPodsStrategy.
+func (in *MaxPodsStrategy) DeepCopy() *MaxPodsStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(MaxPodsStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkOptions) DeepCopyInto(out *NetworkOptions) {
+	*out = *in
+	if in.Nameservers != nil {
+		in, out := &in.Nameservers, &out.Nameservers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Domains != nil {
+		in, out := &in.Domains, &out.Domains
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkOptions.
+func (in *NetworkOptions) DeepCopy() *NetworkOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeConfig) DeepCopyInto(out *NodeConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeConfig.
+func (in *NodeConfig) DeepCopy() *NodeConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeConfigList) DeepCopyInto(out *NodeConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NodeConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeConfigList.
+func (in *NodeConfigList) DeepCopy() *NodeConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeConfigSpec) DeepCopyInto(out *NodeConfigSpec) {
+	*out = *in
+	in.Cluster.DeepCopyInto(&out.Cluster)
+	in.Containerd.DeepCopyInto(&out.Containerd)
+	in.Instance.DeepCopyInto(&out.Instance)
+	in.Kubelet.DeepCopyInto(&out.Kubelet)
+	if in.FeatureGates != nil {
+		in, out := &in.FeatureGates, &out.FeatureGates
+		*out = make(map[Feature]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeConfigSpec.
+func (in *NodeConfigSpec) DeepCopy() *NodeConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrefetchImage) DeepCopyInto(out *PrefetchImage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrefetchImage.
+func (in *PrefetchImage) DeepCopy() *PrefetchImage {
+	if in == nil {
+		return nil
+	}
+	out := new(PrefetchImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryMirror) DeepCopyInto(out *RegistryMirror) {
+	*out = *in
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = make([]RegistryCapability, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryMirror.
+func (in *RegistryMirror) DeepCopy() *RegistryMirror {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryMirror)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegistryOptions) DeepCopyInto(out *RegistryOptions) {
+	*out = *in
+	if in.Mirrors != nil {
+		in, out := &in.Mirrors, &out.Mirrors
+		*out = make([]RegistryMirror, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryOptions.
+func (in *RegistryOptions) DeepCopy() *RegistryOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReservedResourceExpressions) DeepCopyInto(out *ReservedResourceExpressions) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReservedResourceExpressions.
+func (in *ReservedResourceExpressions) DeepCopy() *ReservedResourceExpressions {
+	if in == nil {
+		return nil
+	}
+	out := new(ReservedResourceExpressions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SOCIOptions) DeepCopyInto(out *SOCIOptions) {
+	*out = *in
+	if in.MinMemory != nil {
+		in, out := &in.MinMemory, &out.MinMemory
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MinCPU != nil {
+		in, out := &in.MinCPU, &out.MinCPU
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SOCIOptions.
+func (in *SOCIOptions) DeepCopy() *SOCIOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(SOCIOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Taint) DeepCopyInto(out *Taint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Taint.
+func (in *Taint) DeepCopy() *Taint {
+	if in == nil {
+		return nil
+	}
+	out := new(Taint)
+	in.DeepCopyInto(out)
+	return out
+}
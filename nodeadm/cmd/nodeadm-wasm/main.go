@@ -4,6 +4,7 @@ package main
 
 import (
 	"fmt"
+	"strings"
 	"syscall/js"
 
 	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
@@ -51,8 +52,15 @@ var nodeadmCheckFunc = func(this js.Value, args []js.Value) any {
 	if err != nil {
 		return js.ValueOf(err.Error())
 	}
-	if err := api.ValidateNodeConfig(nodeConfig); err != nil {
-		return js.ValueOf(fmt.Errorf("validating NodeConfig: %w", err).Error())
+	if err := nodeConfig.RemovePatchDirectives(); err != nil {
+		return js.ValueOf(err.Error())
+	}
+	if errs := api.ValidateNodeConfig(nodeConfig); len(errs) > 0 {
+		messages := make([]string, 0, len(errs))
+		for _, err := range errs {
+			messages = append(messages, err.Error())
+		}
+		return js.ValueOf(fmt.Sprintf("validating NodeConfig:\n%s", strings.Join(messages, "\n")))
 	}
 	return js.ValueOf("Looks Good! 👍")
 }
@@ -1,9 +1,12 @@
 package config
 
 import (
+	"fmt"
+
 	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
 	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/cli"
 	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/configprovider"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/containerd"
 	"github.com/integrii/flaggy"
 	"go.uber.org/zap"
 )
@@ -12,6 +15,7 @@ type checkCmd struct {
 	cmd *flaggy.Subcommand
 
 	configSources []string
+	strict        bool
 }
 
 func NewCheckCommand() cli.Command {
@@ -19,6 +23,7 @@ func NewCheckCommand() cli.Command {
 	c.cmd = flaggy.NewSubcommand("check")
 	c.cmd.Description = "Verify configuration"
 	cli.RegisterFlagConfigSources(c.cmd, &c.configSources)
+	c.cmd.Bool(&c.strict, "", "strict", "Treat warnings, such as unknown keys of the containerd config, as errors.")
 	return &c
 }
 
@@ -38,8 +43,21 @@ func (c *checkCmd) Run(log *zap.Logger, opts *cli.GlobalOptions) error {
 	if err != nil {
 		return err
 	}
-	if err := api.ValidateNodeConfig(nodeConfig); err != nil {
-		return err
+	errs := api.ValidateNodeConfig(nodeConfig)
+	containerdErrs, warnings := containerd.ValidateConfig(nodeConfig)
+	errs = append(errs, containerdErrs...)
+	if c.strict {
+		errs = append(errs, warnings...)
+	} else {
+		for _, warning := range warnings {
+			log.Warn("Questionable configuration", zap.String("field", warning.Field), zap.String("warning", warning.ErrorBody()))
+		}
+	}
+	if len(errs) > 0 {
+		for _, err := range errs {
+			log.Error("Invalid configuration", zap.String("field", err.Field), zap.String("error", err.ErrorBody()))
+		}
+		return fmt.Errorf("configuration is invalid: found %d error(s)", len(errs))
 	}
 	log.Info("Configuration is valid")
 	return nil
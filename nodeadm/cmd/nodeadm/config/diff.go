@@ -0,0 +1,252 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/integrii/flaggy"
+	"github.com/pmezard/go-difflib/difflib"
+	"go.uber.org/zap"
+	"sigs.k8s.io/yaml"
+
+	"github.com/awslabs/amazon-eks-ami/nodeadm/api/v1alpha1"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api/bridge"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/aws/imds"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/cli"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/containerd"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/daemon"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/kubelet"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/system"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/util"
+)
+
+type diffCmd struct {
+	cmd *flaggy.Subcommand
+
+	configSources     []string
+	baseConfigSources []string
+	configCache       string
+	specOnly          bool
+}
+
+func NewDiffCommand() cli.Command {
+	c := diffCmd{}
+	c.cmd = flaggy.NewSubcommand("diff")
+	c.cmd.Description = "Compare two configurations and the files they generate"
+	cli.RegisterFlagConfigSources(c.cmd, &c.configSources)
+	c.cmd.StringSlice(&c.baseConfigSources, "b", "base-config-source", "Source(s) of the node configuration to compare against. Uses the same format as --config-source. When omitted, the config at --config-cache is used.")
+	cli.RegisterFlagConfigCache(c.cmd, &c.configCache)
+	c.cmd.Bool(&c.specOnly, "", "spec-only", "Only compare the NodeConfig specs, without rendering the kubelet and containerd files.")
+	return &c
+}
+
+func (c *diffCmd) Flaggy() *flaggy.Subcommand {
+	return c.cmd
+}
+
+func (c *diffCmd) Run(log *zap.Logger, opts *cli.GlobalOptions) error {
+	c.configSources = cli.ResolveConfigSources(c.configSources)
+
+	var baseConfig *api.NodeConfig
+	if len(c.baseConfigSources) > 0 {
+		log.Info("Loading base configuration..", zap.Strings("source", c.baseConfigSources))
+		config, _, _, err := cli.ResolveConfig(log, c.baseConfigSources, "")
+		if err != nil {
+			return fmt.Errorf("failed to resolve base config: %w", err)
+		}
+		baseConfig = config
+	} else if len(c.configCache) > 0 {
+		log.Info("Loading base configuration..", zap.String("configCache", c.configCache))
+		config, err := cli.LoadCachedConfig(c.configCache)
+		if err != nil {
+			return fmt.Errorf("failed to load cached config: %w", err)
+		}
+		baseConfig = config
+	} else {
+		return fmt.Errorf("one of --base-config-source or --config-cache must be specified")
+	}
+
+	log.Info("Loading configuration..", zap.Strings("source", c.configSources))
+	nodeConfig, _, _, err := cli.ResolveConfig(log, c.configSources, "")
+	if err != nil {
+		return fmt.Errorf("failed to resolve config: %w", err)
+	}
+
+	var out bytes.Buffer
+	if err := diffSpecs(&out, baseConfig, nodeConfig); err != nil {
+		return err
+	}
+
+	if !c.specOnly {
+		// both configs are rendered against the same instance details so that
+		// only differences caused by the specs show up in the output. a cached
+		// config already carries its status, otherwise it is fetched once.
+		if len(baseConfig.Status.KubeletVersion) > 0 {
+			copyEnrichedStatus(nodeConfig, baseConfig)
+		} else {
+			log.Info("Enriching configuration..")
+			if err := cli.EnrichConfig(log, nodeConfig, opts); err != nil {
+				return err
+			}
+			copyEnrichedStatus(baseConfig, nodeConfig)
+		}
+		if err := diffRenderedFiles(log, &out, baseConfig, nodeConfig, imds.DefaultClient()); err != nil {
+			return err
+		}
+	}
+
+	if out.Len() == 0 {
+		log.Info("Configurations are equivalent")
+		return nil
+	}
+	if _, err := io.Copy(os.Stdout, &out); err != nil {
+		return fmt.Errorf("failed to write diff to stdout: %w", err)
+	}
+	return nil
+}
+
+// copyEnrichedStatus copies the status that EnrichConfig fetches from src to
+// dst. The rest of the status, such as the kubelet config overrides, is
+// derived from the spec of each config, so it is kept.
+func copyEnrichedStatus(dst, src *api.NodeConfig) {
+	dst.Status.Instance = src.Status.Instance
+	dst.Status.Defaults = src.Status.Defaults
+	dst.Status.KubeletVersion = src.Status.KubeletVersion
+}
+
+// diffSpecs writes a unified diff of the two NodeConfigs, in their external
+// representation, to the writer.
+func diffSpecs(w io.Writer, base, target *api.NodeConfig) error {
+	baseData, err := encodeNodeConfigYAML(base)
+	if err != nil {
+		return fmt.Errorf("failed to encode base config: %w", err)
+	}
+	targetData, err := encodeNodeConfigYAML(target)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	return writeUnifiedDiff(w, "a/NodeConfig", "b/NodeConfig", baseData, targetData)
+}
+
+func encodeNodeConfigYAML(cfg *api.NodeConfig) ([]byte, error) {
+	data, err := bridge.EncodeNodeConfig(cfg, v1alpha1.GroupVersion)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.JSONToYAML(data)
+}
+
+// diffRenderedFiles configures the daemons for both NodeConfigs beneath
+// separate temporary roots and writes a unified diff of every file that
+// differs between the two to the writer.
+func diffRenderedFiles(log *zap.Logger, w io.Writer, base, target *api.NodeConfig, imdsClient imds.IMDSClient) error {
+	baseFiles, err := renderFiles(log, base, imdsClient)
+	if err != nil {
+		return fmt.Errorf("failed to render files for base config: %w", err)
+	}
+	targetFiles, err := renderFiles(log, target, imdsClient)
+	if err != nil {
+		return fmt.Errorf("failed to render files for config: %w", err)
+	}
+
+	var paths []string
+	for path := range baseFiles {
+		paths = append(paths, path)
+	}
+	for path := range targetFiles {
+		if _, ok := baseFiles[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	slices.Sort(paths)
+
+	for _, path := range paths {
+		fromFile, toFile := "a"+path, "b"+path
+		baseData, ok := baseFiles[path]
+		if !ok {
+			fromFile = "/dev/null"
+		}
+		targetData, ok := targetFiles[path]
+		if !ok {
+			toFile = "/dev/null"
+		}
+		if err := writeUnifiedDiff(w, fromFile, toFile, baseData, targetData); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderFiles runs the config phase of the daemons for the NodeConfig beneath
+// a temporary root, and returns the generated files keyed by their path on the
+// host.
+func renderFiles(log *zap.Logger, cfg *api.NodeConfig, imdsClient imds.IMDSClient) (map[string][]byte, error) {
+	rootDir, err := os.MkdirTemp("", "nodeadm-diff-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(rootDir)
+	util.SetRootDir(rootDir)
+	defer util.SetRootDir("/")
+
+	daemonManager := daemon.NewDryRunDaemonManager()
+	resources := system.NewResources(system.RealFileSystem{})
+	daemons := []daemon.Daemon{
+		containerd.NewContainerdDaemon(daemonManager, resources),
+		kubelet.NewKubeletDaemon(daemonManager, resources, imdsClient),
+	}
+	for _, daemon := range daemons {
+		log.Info("Rendering daemon configuration..", zap.String("name", daemon.Name()))
+		if err := daemon.Configure(cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	files := make(map[string][]byte)
+	err = filepath.WalkDir(rootDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		relPath, err := filepath.Rel(rootDir, path)
+		if err != nil {
+			return err
+		}
+		// #nosec G304 // reading back files rendered in a temporary directory
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		files["/"+relPath] = data
+		return nil
+	})
+	return files, err
+}
+
+func writeUnifiedDiff(w io.Writer, fromFile, toFile string, a, b []byte) error {
+	if bytes.Equal(a, b) {
+		return nil
+	}
+	return difflib.WriteUnifiedDiff(w, difflib.UnifiedDiff{
+		A:        splitLines(a),
+		B:        splitLines(b),
+		FromFile: fromFile,
+		ToFile:   toFile,
+		Context:  3,
+	})
+}
+
+// splitLines splits the data into lines that keep their trailing newline, with
+// no extra empty line for a terminating newline.
+func splitLines(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	return difflib.SplitLines(strings.TrimSuffix(string(data), "\n"))
+}
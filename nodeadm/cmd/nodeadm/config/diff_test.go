@@ -0,0 +1,62 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/aws/imds"
+)
+
+func TestDiffRenderedFilesPatchOverrides(t *testing.T) {
+	binDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(binDir, "ecr-credential-provider"), nil, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(binDir, "containerd"), []byte("#!/bin/sh\necho containerd github.com/containerd/containerd/v2 2.1.0\n"), 0755))
+	t.Setenv("ECR_CREDENTIAL_PROVIDER_BIN_PATH", filepath.Join(binDir, "ecr-credential-provider"))
+	t.Setenv("PATH", binDir+":"+os.Getenv("PATH"))
+
+	newConfig := func(kubeletConfig string) *api.NodeConfig {
+		cfg := &api.NodeConfig{
+			Spec: api.NodeConfigSpec{
+				Cluster: api.ClusterDetails{
+					Name:                 "my-cluster",
+					APIServerEndpoint:    "https://example.com",
+					CertificateAuthority: []byte("certificateAuthority"),
+					CIDR:                 "10.100.0.0/16",
+				},
+				Kubelet: api.KubeletOptions{
+					Config: api.InlineDocument{"evictionHard": runtime.RawExtension{Raw: []byte(kubeletConfig)}},
+				},
+			},
+		}
+		assert.NoError(t, cfg.RemovePatchDirectives())
+		return cfg
+	}
+	base := newConfig(`{"memory.available":"200Mi"}`)
+	base.Status.KubeletVersion = "v1.32.0"
+	base.Status.Instance = api.InstanceDetails{Type: "m5.large", Region: "us-west-2", PrivateDNSName: "ip-10-0-0-1.us-west-2.compute.internal"}
+	target := newConfig(`{"$patch":"replace","memory.available":"200Mi"}`)
+	copyEnrichedStatus(target, base)
+
+	assert.Empty(t, base.Status.KubeletConfigOverrides)
+	assert.Equal(t, [][]string{{"evictionHard"}}, target.Status.KubeletConfigOverrides)
+
+	var out bytes.Buffer
+	imdsClient := &imds.FakeIMDSClient{
+		GetPropertyFunc: func(ctx context.Context, prop imds.IMDSProperty) (string, error) {
+			return "10.0.0.1", nil
+		},
+	}
+	assert.NoError(t, diffRenderedFiles(zap.NewNop(), &out, base, target, imdsClient))
+	// the drop-in configs are the same, but the map that target replaces is
+	// removed from the base kubelet config of target only.
+	assert.NotContains(t, out.String(), "40-nodeadm.conf")
+	assert.Contains(t, out.String(), "-    \"evictionHard\": {")
+}
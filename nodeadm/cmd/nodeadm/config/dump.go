@@ -1,10 +1,14 @@
 package config
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 
 	"github.com/awslabs/amazon-eks-ami/nodeadm/api/v1alpha1"
+	internalapi "github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
 	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api/bridge"
 	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/cli"
 	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/util"
@@ -18,6 +22,8 @@ type dumpCmd struct {
 	configSources []string
 	configCache   string
 	configOutput  string
+	outputVersion string
+	showOrigin    bool
 }
 
 func NewDumpCommand() cli.Command {
@@ -27,6 +33,9 @@ func NewDumpCommand() cli.Command {
 	cli.RegisterFlagConfigSources(c.cmd, &c.configSources)
 	cli.RegisterFlagConfigCache(c.cmd, &c.configCache)
 	cli.RegisterFlagConfigOutput(c.cmd, &c.configOutput)
+	c.outputVersion = v1alpha1.GroupVersion.Version
+	c.cmd.String(&c.outputVersion, "", "output-version", "API version in which to encode the config, e.g. v1beta1.")
+	c.cmd.Bool(&c.showOrigin, "", "show-origin", "List each field that is set in the config spec with its value and the config source that set it, instead of dumping the config.")
 	return &c
 }
 
@@ -41,12 +50,22 @@ func (c *dumpCmd) Run(log *zap.Logger, opts *cli.GlobalOptions) error {
 		log.Info("Dumping configuration", zap.Strings("source", c.configSources), zap.String("output", c.configOutput))
 	}
 
+	outputGroupVersion, err := bridge.ParseExternalGroupVersion(c.outputVersion)
+	if err != nil {
+		return err
+	}
+
 	nodeConfig, _, _, err := cli.ResolveConfig(log, c.configSources, c.configCache)
 	if err != nil {
 		return err
 	}
 
-	data, err := bridge.EncodeNodeConfig(nodeConfig, v1alpha1.GroupVersion)
+	var data []byte
+	if c.showOrigin {
+		data, err = formatOrigins(nodeConfig)
+	} else {
+		data, err = bridge.EncodeNodeConfig(nodeConfig, outputGroupVersion)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to encode config: %w", err)
 	}
@@ -64,3 +83,26 @@ func (c *dumpCmd) Run(log *zap.Logger, opts *cli.GlobalOptions) error {
 	}
 	return nil
 }
+
+// formatOrigins lists every field set in the spec, sorted by path, in the
+// form `<origin>\t<path>=<value>` with the value encoded as JSON.
+func formatOrigins(nodeConfig *internalapi.NodeConfig) ([]byte, error) {
+	fields, err := internalapi.SpecFields(&nodeConfig.Spec)
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, 0, len(fields))
+	for path := range fields {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	var buf bytes.Buffer
+	for _, path := range paths {
+		value, err := json.Marshal(fields[path])
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&buf, "%s\t%s=%s\n", nodeConfig.Origins[path], path, value)
+	}
+	return buf.Bytes(), nil
+}
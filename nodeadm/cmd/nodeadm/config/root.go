@@ -8,5 +8,6 @@ func NewConfigCommand() cli.Command {
 	container := cli.NewCommandContainer("config", "Manage configuration")
 	container.AddCommand(NewCheckCommand())
 	container.AddCommand(NewDumpCommand())
+	container.AddCommand(NewDiffCommand())
 	return container.AsCommand()
 }
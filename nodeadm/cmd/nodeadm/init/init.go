@@ -1,23 +1,23 @@
 package init
 
 import (
-	"context"
+	"errors"
+	"os"
 	"slices"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/integrii/flaggy"
 	"go.uber.org/zap"
 
 	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
 	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/aws/imds"
 	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/cli"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/configprovider"
 	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/containerd"
 	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/daemon"
 	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/kubelet"
 	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/system"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/util"
 )
 
 const (
@@ -32,6 +32,8 @@ func NewInitCommand() cli.Command {
 	c.cmd.Description = "Initialize this instance as a node in an EKS cluster"
 	c.cmd.StringSlice(&c.daemons, "d", "daemon", "specify one or more of `containerd` and `kubelet`. This is intended for testing and should not be used in a production environment.")
 	c.cmd.StringSlice(&c.skipPhases, "s", "skip", "phases of the bootstrap you want to skip")
+	c.cmd.Bool(&c.dryRun, "", "dry-run", "Render the files that would be written without modifying the host. Daemon actions are recorded instead of being performed. The configuration is still enriched with calls to IMDS and EC2.")
+	c.cmd.String(&c.rootDir, "", "root", "Directory beneath which all files are written. Defaults to a temporary directory when --dry-run is set, and to / otherwise.")
 	cli.RegisterFlagConfigCache(c.cmd, &c.configCache)
 	cli.RegisterFlagConfigSources(c.cmd, &c.configSources)
 	return &c
@@ -43,6 +45,8 @@ type initCmd struct {
 	configCache   string
 	skipPhases    []string
 	daemons       []string
+	dryRun        bool
+	rootDir       string
 }
 
 func (c *initCmd) Flaggy() *flaggy.Subcommand {
@@ -52,28 +56,44 @@ func (c *initCmd) Flaggy() *flaggy.Subcommand {
 func (c *initCmd) Run(log *zap.Logger, opts *cli.GlobalOptions) error {
 	start := time.Now()
 
-	log.Info("Checking user is root..")
-	root, err := cli.IsRunningAsRoot()
-	if err != nil {
-		return err
-	} else if !root {
-		return cli.ErrMustRunAsRoot
+	if c.dryRun {
+		if len(c.rootDir) == 0 {
+			rootDir, err := os.MkdirTemp("", "nodeadm-dry-run-")
+			if err != nil {
+				return err
+			}
+			c.rootDir = rootDir
+		}
+		log.Info("Running in dry-run mode", zap.String("root", c.rootDir))
+	} else {
+		log.Info("Checking user is root..")
+		root, err := cli.IsRunningAsRoot()
+		if err != nil {
+			return err
+		} else if !root {
+			return cli.ErrMustRunAsRoot
+		}
+	}
+	if len(c.rootDir) > 0 {
+		util.SetRootDir(c.rootDir)
 	}
 
 	c.configSources = cli.ResolveConfigSources(c.configSources)
 
 	log.Info("Loading configuration..", zap.Strings("configSource", c.configSources), zap.String("configCache", c.configCache))
 
-	nodeConfig, isChanged, shouldEnrichConfig, err := cli.ResolveConfig(log, c.configSources, c.configCache)
+	// nodeadmEnvAspect setups nodeadm envrionment that could be vital for bootstrapping.
+	// For example, to enrich node config we might need to make EC2 API calls, which may
+	// need to pass through an HTTP(s) proxy. It is applied to the config of each source
+	// as it is provided, so that later sources are fetched through the proxy too, and
+	// then to the resolved config, which may come from the config cache instead.
+	nodeadmEnvAspect := system.NewNodeadmEnvironmentAspect()
+	nodeConfig, isChanged, shouldEnrichConfig, err := cli.ResolveConfig(log, c.configSources, c.configCache, configprovider.WithConfigHook(nodeadmEnvAspect.Setup))
 	if err != nil {
 		return err
 	}
 
-	// nodeadmEnvAspect setups nodeadm envrionment that could be vital for bootstrapping.
-	// For example, to enrich node config we might need to make EC2 API calls, which may
-	// need to pass through an HTTP(s) proxy.
 	log.Info("Setting up nodeadm environment aspect...")
-	nodeadmEnvAspect := system.NewNodeadmEnvironmentAspect()
 	if err := nodeadmEnvAspect.Setup(nodeConfig); err != nil {
 		return err
 	}
@@ -82,21 +102,32 @@ func (c *initCmd) Run(log *zap.Logger, opts *cli.GlobalOptions) error {
 		// we don't need to enrich config when defaulting to a cache, since that is
 		// the only time we already have the NodeConfig .status details populated.
 		log.Info("Enriching configuration..")
-		if err := c.enrichConfig(log, nodeConfig, opts); err != nil {
+		if err := cli.EnrichConfig(log, nodeConfig, opts); err != nil {
 			return err
 		}
 	}
 	log.Info("Loaded configuration", zap.Reflect("config", nodeConfig))
 
 	log.Info("Validating configuration..")
-	if err := api.ValidateNodeConfig(nodeConfig); err != nil {
-		return err
+	errs := api.ValidateNodeConfig(nodeConfig)
+	containerdErrs, warnings := containerd.ValidateConfig(nodeConfig)
+	if errs = append(errs, containerdErrs...); len(errs) > 0 {
+		return errs.ToAggregate()
+	}
+	for _, warning := range warnings {
+		log.Warn("Questionable configuration", zap.String("field", warning.Field), zap.String("warning", warning.ErrorBody()))
 	}
 
 	log.Info("Creating daemon manager..")
-	daemonManager, err := daemon.NewDaemonManager()
-	if err != nil {
-		return err
+	var daemonManager daemon.DaemonManager
+	dryRunDaemonManager := daemon.NewDryRunDaemonManager()
+	if c.dryRun {
+		daemonManager = dryRunDaemonManager
+	} else {
+		daemonManager, err = daemon.NewDaemonManager()
+		if err != nil {
+			return err
+		}
 	}
 	defer daemonManager.Close()
 
@@ -119,8 +150,10 @@ func (c *initCmd) Run(log *zap.Logger, opts *cli.GlobalOptions) error {
 	if needsRecache || !slices.Contains(c.skipPhases, configPhase) {
 		log.Info("Setting up system config aspects...")
 		configAspects := []system.SystemAspect{
-			system.NewInstanceEnvironmentAspect(),
-			system.NewResolveAspect(),
+			system.NewInstanceEnvironmentAspect(daemonManager),
+			system.NewResolveAspect(daemonManager),
+			system.NewSysctlDropInAspect(),
+			system.NewKernelModulesDropInAspect(),
 		}
 		if err := c.setupAspects(log, nodeConfig, configAspects); err != nil {
 			return err
@@ -138,13 +171,25 @@ func (c *initCmd) Run(log *zap.Logger, opts *cli.GlobalOptions) error {
 	}
 
 	if !slices.Contains(c.skipPhases, runPhase) {
-		log.Info("Setting up system run aspects...")
-		runAspects := []system.SystemAspect{
-			system.NewMarkerAspect(),
-			system.NewLocalDiskAspect(),
-		}
-		if err := c.setupAspects(log, nodeConfig, runAspects); err != nil {
-			return err
+		// run aspects and post-launch tasks act directly on the host, so a dry
+		// run only records the daemons that would be started.
+		if c.dryRun {
+			log.Info("Skipping system run aspects in dry-run mode")
+		} else {
+			log.Info("Setting up system run aspects...")
+			var saveConfig func(*api.NodeConfig) error
+			if len(c.configCache) > 0 {
+				saveConfig = func(cfg *api.NodeConfig) error {
+					return cli.SaveCachedConfig(cfg, c.configCache)
+				}
+			}
+			if err := c.setupAspects(log, nodeConfig, runAspects(resources, saveConfig)); errors.Is(err, system.ErrRebooting) {
+				// the daemons are started once the instance has rebooted.
+				log.Info("Instance is rebooting, skipping the rest of init")
+				return nil
+			} else if err != nil {
+				return err
+			}
 		}
 
 		log.Info("Running daemons...")
@@ -153,58 +198,27 @@ func (c *initCmd) Run(log *zap.Logger, opts *cli.GlobalOptions) error {
 		}
 	}
 
+	if c.dryRun {
+		log.Info("Dry run complete", zap.String("root", c.rootDir), zap.Strings("daemonActions", dryRunDaemonManager.Actions()))
+	}
+
 	log.Info("done!", zap.Duration("duration", time.Since(start)))
 
 	return nil
 }
 
-// enrichConfig populates the internal .status portion of the NodeConfig, used
-// only for internal implementation details.
-func (*initCmd) enrichConfig(log *zap.Logger, cfg *api.NodeConfig, opts *cli.GlobalOptions) error {
-	log.Info("Fetching kubelet version..")
-	kubeletVersion, err := kubelet.GetKubeletVersion()
-	if err != nil {
-		return err
-	}
-	cfg.Status.KubeletVersion = kubeletVersion
-	log.Info("Fetched kubelet version", zap.String("version", kubeletVersion))
-	log.Info("Fetching instance details..")
-	awsClientLogMode := aws.LogRetries
-	if opts.DevelopmentMode {
-		// SDK v2 log modes are just bitwise operations, toggle all bits for maximum verbosity
-		// https://github.com/aws/aws-sdk-go-v2/blob/838fb872e9701fc62b7b86164389791f5313bfcb/aws/logging.go#L18
-		awsClientLogMode = aws.ClientLogMode((1 << 64) - 1)
-	}
-	awsConfig, err := config.LoadDefaultConfig(context.TODO(),
-		config.WithClientLogMode(awsClientLogMode),
-		config.WithEC2IMDSRegion(func(o *config.UseEC2IMDSRegion) {
-			o.Client = imds.New(true /* treat 404's as retryable to make credential chain more resilient */)
-		}),
-	)
-	if err != nil {
-		return err
-	}
-	if awsConfig.RetryMaxAttempts == 0 {
-		// use a very generous retry policy to accomodate delays in network readiness
-		// we only specify the max attempts if it is unset by the user
-		// so it's possible to override with the AWS_MAX_ATTEMPTS environment variable.
-		// NOTE that this is the number of attempts that will be made in a blocking fashion
-		// i.e. an SDK client.ExampleAPICall() will not return until these attempts are exhausted
-		// we'll give up after approximately 10 minutes
-		awsConfig.RetryMaxAttempts = 30
-	}
-	instanceDetails, err := api.GetInstanceDetails(context.TODO(), cfg.Spec.FeatureGates, ec2.NewFromConfig(awsConfig), imds.DefaultClient())
-	if err != nil {
-		return err
+// runAspects returns the system aspects of the run phase in the order they are
+// set up. The kernel modules are loaded before the sysctls are applied, as the
+// sysctls of a module, such as net.bridge.* of br_netfilter, only exist once
+// it is loaded.
+func runAspects(resources system.Resources, saveConfig func(*api.NodeConfig) error) []system.SystemAspect {
+	return []system.SystemAspect{
+		system.NewMarkerAspect(),
+		system.NewKernelAspect(saveConfig),
+		system.NewSysctlAspect(),
+		system.NewHugepagesAspect(resources),
+		system.NewLocalDiskAspect(),
 	}
-	cfg.Status.Instance = *instanceDetails
-	log.Info("Instance details populated", zap.Reflect("details", instanceDetails))
-	log.Info("Fetching default options...")
-	cfg.Status.Defaults = api.DefaultOptions{
-		SandboxImage: "localhost/kubernetes/pause:latest",
-	}
-	log.Info("Default options populated", zap.Reflect("defaults", cfg.Status.Defaults))
-	return nil
 }
 
 func (c *initCmd) configureDaemons(log *zap.Logger, cfg *api.NodeConfig, daemons []daemon.Daemon) error {
@@ -236,6 +250,10 @@ func (c *initCmd) runDaemons(log *zap.Logger, cfg *api.NodeConfig, daemons []dae
 		}
 		log.Info("Daemon is running")
 
+		if c.dryRun {
+			log.Info("Skipping post-launch tasks in dry-run mode")
+			continue
+		}
 		log.Info("Running post-launch tasks..")
 		if err := daemon.PostLaunch(cfg); err != nil {
 			return err
@@ -0,0 +1,18 @@
+package init
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/system"
+)
+
+func TestRunAspectsOrder(t *testing.T) {
+	var names []string
+	for _, aspect := range runAspects(system.NewResources(system.FakeFileSystem{}), nil) {
+		names = append(names, aspect.Name())
+	}
+	// the kernel modules are loaded before their sysctls are applied.
+	assert.Equal(t, []string{"marker", "kernel", "sysctl", "hugepages", "local-disk"}, names)
+}
@@ -0,0 +1,47 @@
+package bridge
+
+import (
+	"encoding/json"
+	"testing"
+
+	internalapi "github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/randfill"
+)
+
+const roundTripIterations = 200
+
+func TestRoundTrip(t *testing.T) {
+	filler := randfill.New().NilChance(0.2).NumElements(0, 3).Funcs(
+		// the content of an inline document is opaque to conversion, but it
+		// must be valid JSON to survive encoding.
+		func(raw *runtime.RawExtension, c randfill.Continue) {
+			data, err := json.Marshal(map[string]string{c.String(0): c.String(0)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			raw.Raw = data
+		},
+	)
+	for _, groupVersion := range ExternalGroupVersions {
+		t.Run(groupVersion.Version, func(t *testing.T) {
+			for i := 0; i < roundTripIterations; i++ {
+				original := &internalapi.NodeConfig{}
+				filler.Fill(&original.Spec)
+
+				data, err := EncodeNodeConfig(original, groupVersion)
+				if err != nil {
+					t.Fatalf("failed to encode: %v", err)
+				}
+				decoded, err := DecodeNodeConfig(data, nil)
+				if err != nil {
+					t.Fatalf("failed to decode %s: %v", string(data), err)
+				}
+				if !apiequality.Semantic.DeepEqual(original.Spec, decoded.Spec) {
+					t.Fatalf("round trip through %s changed the spec:\n%s", groupVersion, string(data))
+				}
+			}
+		})
+	}
+}
@@ -1,9 +1,13 @@
 package bridge
 
 import (
+	"fmt"
+
 	"github.com/awslabs/amazon-eks-ami/nodeadm/api"
 	"github.com/awslabs/amazon-eks-ami/nodeadm/api/v1alpha1"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/api/v1beta1"
 	internalapi "github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+	bridgev1beta1 "github.com/awslabs/amazon-eks-ami/nodeadm/internal/api/bridge/v1beta1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
@@ -11,12 +15,33 @@ import (
 var (
 	localSchemeBuilder = runtime.NewSchemeBuilder(
 		v1alpha1.AddToScheme,
+		v1beta1.AddToScheme,
+		bridgev1beta1.AddToScheme,
 		addInternalTypes,
 	)
 
 	InternalGroupVersion = schema.GroupVersion{Group: api.GroupName, Version: runtime.APIVersionInternal}
+
+	// ExternalGroupVersions are the versions of the API in which a NodeConfig
+	// can be provided or encoded.
+	ExternalGroupVersions = []schema.GroupVersion{
+		v1alpha1.GroupVersion,
+		v1beta1.GroupVersion,
+	}
 )
 
+// ParseExternalGroupVersion returns the external GroupVersion matching the
+// given version, which may be given with or without the API group, e.g.
+// `v1beta1` or `node.eks.aws/v1beta1`.
+func ParseExternalGroupVersion(version string) (schema.GroupVersion, error) {
+	for _, groupVersion := range ExternalGroupVersions {
+		if version == groupVersion.Version || version == groupVersion.String() {
+			return groupVersion, nil
+		}
+	}
+	return schema.GroupVersion{}, fmt.Errorf("unsupported API version %q, must be one of %v", version, ExternalGroupVersions)
+}
+
 func addInternalTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(InternalGroupVersion,
 		&internalapi.NodeConfig{},
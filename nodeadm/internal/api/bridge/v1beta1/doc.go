@@ -0,0 +1,4 @@
+// Package v1beta1 translates between internal and v1beta1 API types.
+// +k8s:conversion-gen=github.com/awslabs/amazon-eks-ami/nodeadm/internal/api
+// +k8s:conversion-gen-external-types=github.com/awslabs/amazon-eks-ami/nodeadm/api/v1beta1
+package v1beta1
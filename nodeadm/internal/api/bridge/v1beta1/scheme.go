@@ -0,0 +1,12 @@
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var (
+	localSchemeBuilder runtime.SchemeBuilder
+	// AddToScheme registers the conversions between the internal and v1beta1
+	// API types.
+	AddToScheme = localSchemeBuilder.AddToScheme
+)
@@ -0,0 +1,758 @@
+//go:build !ignore_autogenerated
+// +build !ignore_autogenerated
+
+// Code generated by conversion-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	unsafe "unsafe"
+
+	apiv1beta1 "github.com/awslabs/amazon-eks-ami/nodeadm/api/v1beta1"
+	api "github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+	resource "k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	conversion "k8s.io/apimachinery/pkg/conversion"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	intstr "k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func init() {
+	localSchemeBuilder.Register(RegisterConversions)
+}
+
+// RegisterConversions adds conversion functions to the given scheme.
+// Public to allow building arbitrary schemes.
+func RegisterConversions(s *runtime.Scheme) error {
+	if err := s.AddGeneratedConversionFunc((*apiv1beta1.ClusterDetails)(nil), (*api.ClusterDetails)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ClusterDetails_To_api_ClusterDetails(a.(*apiv1beta1.ClusterDetails), b.(*api.ClusterDetails), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*api.ClusterDetails)(nil), (*apiv1beta1.ClusterDetails)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_api_ClusterDetails_To_v1beta1_ClusterDetails(a.(*api.ClusterDetails), b.(*apiv1beta1.ClusterDetails), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*apiv1beta1.ContainerdOptions)(nil), (*api.ContainerdOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ContainerdOptions_To_api_ContainerdOptions(a.(*apiv1beta1.ContainerdOptions), b.(*api.ContainerdOptions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*api.ContainerdOptions)(nil), (*apiv1beta1.ContainerdOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_api_ContainerdOptions_To_v1beta1_ContainerdOptions(a.(*api.ContainerdOptions), b.(*apiv1beta1.ContainerdOptions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*apiv1beta1.ContainerdRuntime)(nil), (*api.ContainerdRuntime)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ContainerdRuntime_To_api_ContainerdRuntime(a.(*apiv1beta1.ContainerdRuntime), b.(*api.ContainerdRuntime), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*api.ContainerdRuntime)(nil), (*apiv1beta1.ContainerdRuntime)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_api_ContainerdRuntime_To_v1beta1_ContainerdRuntime(a.(*api.ContainerdRuntime), b.(*apiv1beta1.ContainerdRuntime), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*apiv1beta1.InstanceOptions)(nil), (*api.InstanceOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_InstanceOptions_To_api_InstanceOptions(a.(*apiv1beta1.InstanceOptions), b.(*api.InstanceOptions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*api.InstanceOptions)(nil), (*apiv1beta1.InstanceOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_api_InstanceOptions_To_v1beta1_InstanceOptions(a.(*api.InstanceOptions), b.(*apiv1beta1.InstanceOptions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*apiv1beta1.KernelOptions)(nil), (*api.KernelOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_KernelOptions_To_api_KernelOptions(a.(*apiv1beta1.KernelOptions), b.(*api.KernelOptions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*api.KernelOptions)(nil), (*apiv1beta1.KernelOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_api_KernelOptions_To_v1beta1_KernelOptions(a.(*api.KernelOptions), b.(*apiv1beta1.KernelOptions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*apiv1beta1.KubeletOptions)(nil), (*api.KubeletOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_KubeletOptions_To_api_KubeletOptions(a.(*apiv1beta1.KubeletOptions), b.(*api.KubeletOptions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*api.KubeletOptions)(nil), (*apiv1beta1.KubeletOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_api_KubeletOptions_To_v1beta1_KubeletOptions(a.(*api.KubeletOptions), b.(*apiv1beta1.KubeletOptions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*apiv1beta1.LocalStorageOptions)(nil), (*api.LocalStorageOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_LocalStorageOptions_To_api_LocalStorageOptions(a.(*apiv1beta1.LocalStorageOptions), b.(*api.LocalStorageOptions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*api.LocalStorageOptions)(nil), (*apiv1beta1.LocalStorageOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_api_LocalStorageOptions_To_v1beta1_LocalStorageOptions(a.(*api.LocalStorageOptions), b.(*apiv1beta1.LocalStorageOptions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*apiv1beta1.MaxPodsStrategy)(nil), (*api.MaxPodsStrategy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_MaxPodsStrategy_To_api_MaxPodsStrategy(a.(*apiv1beta1.MaxPodsStrategy), b.(*api.MaxPodsStrategy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*api.MaxPodsStrategy)(nil), (*apiv1beta1.MaxPodsStrategy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_api_MaxPodsStrategy_To_v1beta1_MaxPodsStrategy(a.(*api.MaxPodsStrategy), b.(*apiv1beta1.MaxPodsStrategy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*apiv1beta1.NetworkOptions)(nil), (*api.NetworkOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NetworkOptions_To_api_NetworkOptions(a.(*apiv1beta1.NetworkOptions), b.(*api.NetworkOptions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*api.NetworkOptions)(nil), (*apiv1beta1.NetworkOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_api_NetworkOptions_To_v1beta1_NetworkOptions(a.(*api.NetworkOptions), b.(*apiv1beta1.NetworkOptions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*apiv1beta1.NodeConfig)(nil), (*api.NodeConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NodeConfig_To_api_NodeConfig(a.(*apiv1beta1.NodeConfig), b.(*api.NodeConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*api.NodeConfig)(nil), (*apiv1beta1.NodeConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_api_NodeConfig_To_v1beta1_NodeConfig(a.(*api.NodeConfig), b.(*apiv1beta1.NodeConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*apiv1beta1.NodeConfigList)(nil), (*api.NodeConfigList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NodeConfigList_To_api_NodeConfigList(a.(*apiv1beta1.NodeConfigList), b.(*api.NodeConfigList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*api.NodeConfigList)(nil), (*apiv1beta1.NodeConfigList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_api_NodeConfigList_To_v1beta1_NodeConfigList(a.(*api.NodeConfigList), b.(*apiv1beta1.NodeConfigList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*apiv1beta1.NodeConfigSpec)(nil), (*api.NodeConfigSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_NodeConfigSpec_To_api_NodeConfigSpec(a.(*apiv1beta1.NodeConfigSpec), b.(*api.NodeConfigSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*api.NodeConfigSpec)(nil), (*apiv1beta1.NodeConfigSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_api_NodeConfigSpec_To_v1beta1_NodeConfigSpec(a.(*api.NodeConfigSpec), b.(*apiv1beta1.NodeConfigSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*apiv1beta1.PrefetchImage)(nil), (*api.PrefetchImage)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_PrefetchImage_To_api_PrefetchImage(a.(*apiv1beta1.PrefetchImage), b.(*api.PrefetchImage), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*api.PrefetchImage)(nil), (*apiv1beta1.PrefetchImage)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_api_PrefetchImage_To_v1beta1_PrefetchImage(a.(*api.PrefetchImage), b.(*apiv1beta1.PrefetchImage), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*apiv1beta1.RegistryMirror)(nil), (*api.RegistryMirror)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_RegistryMirror_To_api_RegistryMirror(a.(*apiv1beta1.RegistryMirror), b.(*api.RegistryMirror), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*api.RegistryMirror)(nil), (*apiv1beta1.RegistryMirror)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_api_RegistryMirror_To_v1beta1_RegistryMirror(a.(*api.RegistryMirror), b.(*apiv1beta1.RegistryMirror), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*apiv1beta1.RegistryOptions)(nil), (*api.RegistryOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_RegistryOptions_To_api_RegistryOptions(a.(*apiv1beta1.RegistryOptions), b.(*api.RegistryOptions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*api.RegistryOptions)(nil), (*apiv1beta1.RegistryOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_api_RegistryOptions_To_v1beta1_RegistryOptions(a.(*api.RegistryOptions), b.(*apiv1beta1.RegistryOptions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*apiv1beta1.ReservedResourceExpressions)(nil), (*api.ReservedResourceExpressions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ReservedResourceExpressions_To_api_ReservedResourceExpressions(a.(*apiv1beta1.ReservedResourceExpressions), b.(*api.ReservedResourceExpressions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*api.ReservedResourceExpressions)(nil), (*apiv1beta1.ReservedResourceExpressions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_api_ReservedResourceExpressions_To_v1beta1_ReservedResourceExpressions(a.(*api.ReservedResourceExpressions), b.(*apiv1beta1.ReservedResourceExpressions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*apiv1beta1.SOCIOptions)(nil), (*api.SOCIOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_SOCIOptions_To_api_SOCIOptions(a.(*apiv1beta1.SOCIOptions), b.(*api.SOCIOptions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*api.SOCIOptions)(nil), (*apiv1beta1.SOCIOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_api_SOCIOptions_To_v1beta1_SOCIOptions(a.(*api.SOCIOptions), b.(*apiv1beta1.SOCIOptions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*apiv1beta1.Taint)(nil), (*api.Taint)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_Taint_To_api_Taint(a.(*apiv1beta1.Taint), b.(*api.Taint), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*api.Taint)(nil), (*apiv1beta1.Taint)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_api_Taint_To_v1beta1_Taint(a.(*api.Taint), b.(*apiv1beta1.Taint), scope)
+	}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func autoConvert_v1beta1_ClusterDetails_To_api_ClusterDetails(in *apiv1beta1.ClusterDetails, out *api.ClusterDetails, s conversion.Scope) error {
+	out.Name = in.Name
+	out.APIServerEndpoint = in.APIServerEndpoint
+	out.CertificateAuthority = *(*[]byte)(unsafe.Pointer(&in.CertificateAuthority))
+	out.CIDR = in.CIDR
+	out.EnableOutpost = (*bool)(unsafe.Pointer(in.EnableOutpost))
+	out.ID = in.ID
+	return nil
+}
+
+// Convert_v1beta1_ClusterDetails_To_api_ClusterDetails is an autogenerated conversion function.
+func Convert_v1beta1_ClusterDetails_To_api_ClusterDetails(in *apiv1beta1.ClusterDetails, out *api.ClusterDetails, s conversion.Scope) error {
+	return autoConvert_v1beta1_ClusterDetails_To_api_ClusterDetails(in, out, s)
+}
+
+func autoConvert_api_ClusterDetails_To_v1beta1_ClusterDetails(in *api.ClusterDetails, out *apiv1beta1.ClusterDetails, s conversion.Scope) error {
+	out.Name = in.Name
+	out.APIServerEndpoint = in.APIServerEndpoint
+	out.CertificateAuthority = *(*[]byte)(unsafe.Pointer(&in.CertificateAuthority))
+	out.CIDR = in.CIDR
+	out.EnableOutpost = (*bool)(unsafe.Pointer(in.EnableOutpost))
+	out.ID = in.ID
+	return nil
+}
+
+// Convert_api_ClusterDetails_To_v1beta1_ClusterDetails is an autogenerated conversion function.
+func Convert_api_ClusterDetails_To_v1beta1_ClusterDetails(in *api.ClusterDetails, out *apiv1beta1.ClusterDetails, s conversion.Scope) error {
+	return autoConvert_api_ClusterDetails_To_v1beta1_ClusterDetails(in, out, s)
+}
+
+func autoConvert_v1beta1_ContainerdOptions_To_api_ContainerdOptions(in *apiv1beta1.ContainerdOptions, out *api.ContainerdOptions, s conversion.Scope) error {
+	out.Config = api.ContainerdConfig(in.Config)
+	out.BaseRuntimeSpec = *(*api.InlineDocument)(unsafe.Pointer(&in.BaseRuntimeSpec))
+	out.Registries = *(*map[string]api.RegistryOptions)(unsafe.Pointer(&in.Registries))
+	out.Runtimes = *(*[]api.ContainerdRuntime)(unsafe.Pointer(&in.Runtimes))
+	out.PrefetchImages = *(*[]api.PrefetchImage)(unsafe.Pointer(&in.PrefetchImages))
+	out.PrefetchImagesTimeout = (*v1.Duration)(unsafe.Pointer(in.PrefetchImagesTimeout))
+	out.SandboxImage = in.SandboxImage
+	out.ImageArchives = *(*[]string)(unsafe.Pointer(&in.ImageArchives))
+	if err := Convert_v1beta1_SOCIOptions_To_api_SOCIOptions(&in.SOCI, &out.SOCI, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta1_ContainerdOptions_To_api_ContainerdOptions is an autogenerated conversion function.
+func Convert_v1beta1_ContainerdOptions_To_api_ContainerdOptions(in *apiv1beta1.ContainerdOptions, out *api.ContainerdOptions, s conversion.Scope) error {
+	return autoConvert_v1beta1_ContainerdOptions_To_api_ContainerdOptions(in, out, s)
+}
+
+func autoConvert_api_ContainerdOptions_To_v1beta1_ContainerdOptions(in *api.ContainerdOptions, out *apiv1beta1.ContainerdOptions, s conversion.Scope) error {
+	out.Config = string(in.Config)
+	out.BaseRuntimeSpec = *(*map[string]runtime.RawExtension)(unsafe.Pointer(&in.BaseRuntimeSpec))
+	out.Registries = *(*map[string]apiv1beta1.RegistryOptions)(unsafe.Pointer(&in.Registries))
+	out.Runtimes = *(*[]apiv1beta1.ContainerdRuntime)(unsafe.Pointer(&in.Runtimes))
+	out.PrefetchImages = *(*[]apiv1beta1.PrefetchImage)(unsafe.Pointer(&in.PrefetchImages))
+	out.PrefetchImagesTimeout = (*v1.Duration)(unsafe.Pointer(in.PrefetchImagesTimeout))
+	out.SandboxImage = in.SandboxImage
+	out.ImageArchives = *(*[]string)(unsafe.Pointer(&in.ImageArchives))
+	if err := Convert_api_SOCIOptions_To_v1beta1_SOCIOptions(&in.SOCI, &out.SOCI, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_api_ContainerdOptions_To_v1beta1_ContainerdOptions is an autogenerated conversion function.
+func Convert_api_ContainerdOptions_To_v1beta1_ContainerdOptions(in *api.ContainerdOptions, out *apiv1beta1.ContainerdOptions, s conversion.Scope) error {
+	return autoConvert_api_ContainerdOptions_To_v1beta1_ContainerdOptions(in, out, s)
+}
+
+func autoConvert_v1beta1_ContainerdRuntime_To_api_ContainerdRuntime(in *apiv1beta1.ContainerdRuntime, out *api.ContainerdRuntime, s conversion.Scope) error {
+	out.Name = in.Name
+	out.BinaryPath = in.BinaryPath
+	out.RuntimeType = in.RuntimeType
+	out.Options = *(*api.InlineDocument)(unsafe.Pointer(&in.Options))
+	out.Default = in.Default
+	return nil
+}
+
+// Convert_v1beta1_ContainerdRuntime_To_api_ContainerdRuntime is an autogenerated conversion function.
+func Convert_v1beta1_ContainerdRuntime_To_api_ContainerdRuntime(in *apiv1beta1.ContainerdRuntime, out *api.ContainerdRuntime, s conversion.Scope) error {
+	return autoConvert_v1beta1_ContainerdRuntime_To_api_ContainerdRuntime(in, out, s)
+}
+
+func autoConvert_api_ContainerdRuntime_To_v1beta1_ContainerdRuntime(in *api.ContainerdRuntime, out *apiv1beta1.ContainerdRuntime, s conversion.Scope) error {
+	out.Name = in.Name
+	out.BinaryPath = in.BinaryPath
+	out.RuntimeType = in.RuntimeType
+	out.Options = *(*map[string]runtime.RawExtension)(unsafe.Pointer(&in.Options))
+	out.Default = in.Default
+	return nil
+}
+
+// Convert_api_ContainerdRuntime_To_v1beta1_ContainerdRuntime is an autogenerated conversion function.
+func Convert_api_ContainerdRuntime_To_v1beta1_ContainerdRuntime(in *api.ContainerdRuntime, out *apiv1beta1.ContainerdRuntime, s conversion.Scope) error {
+	return autoConvert_api_ContainerdRuntime_To_v1beta1_ContainerdRuntime(in, out, s)
+}
+
+func autoConvert_v1beta1_InstanceOptions_To_api_InstanceOptions(in *apiv1beta1.InstanceOptions, out *api.InstanceOptions, s conversion.Scope) error {
+	if err := Convert_v1beta1_LocalStorageOptions_To_api_LocalStorageOptions(&in.LocalStorage, &out.LocalStorage, s); err != nil {
+		return err
+	}
+	out.Environment = *(*api.EnvironmentOptions)(unsafe.Pointer(&in.Environment))
+	if err := Convert_v1beta1_NetworkOptions_To_api_NetworkOptions(&in.Network, &out.Network, s); err != nil {
+		return err
+	}
+	out.Sysctls = *(*map[string]string)(unsafe.Pointer(&in.Sysctls))
+	if err := Convert_v1beta1_KernelOptions_To_api_KernelOptions(&in.Kernel, &out.Kernel, s); err != nil {
+		return err
+	}
+	out.Hugepages = *(*map[string]intstr.IntOrString)(unsafe.Pointer(&in.Hugepages))
+	return nil
+}
+
+// Convert_v1beta1_InstanceOptions_To_api_InstanceOptions is an autogenerated conversion function.
+func Convert_v1beta1_InstanceOptions_To_api_InstanceOptions(in *apiv1beta1.InstanceOptions, out *api.InstanceOptions, s conversion.Scope) error {
+	return autoConvert_v1beta1_InstanceOptions_To_api_InstanceOptions(in, out, s)
+}
+
+func autoConvert_api_InstanceOptions_To_v1beta1_InstanceOptions(in *api.InstanceOptions, out *apiv1beta1.InstanceOptions, s conversion.Scope) error {
+	if err := Convert_api_LocalStorageOptions_To_v1beta1_LocalStorageOptions(&in.LocalStorage, &out.LocalStorage, s); err != nil {
+		return err
+	}
+	out.Environment = *(*apiv1beta1.EnvironmentOptions)(unsafe.Pointer(&in.Environment))
+	if err := Convert_api_NetworkOptions_To_v1beta1_NetworkOptions(&in.Network, &out.Network, s); err != nil {
+		return err
+	}
+	out.Sysctls = *(*map[string]string)(unsafe.Pointer(&in.Sysctls))
+	if err := Convert_api_KernelOptions_To_v1beta1_KernelOptions(&in.Kernel, &out.Kernel, s); err != nil {
+		return err
+	}
+	out.Hugepages = *(*map[string]intstr.IntOrString)(unsafe.Pointer(&in.Hugepages))
+	return nil
+}
+
+// Convert_api_InstanceOptions_To_v1beta1_InstanceOptions is an autogenerated conversion function.
+func Convert_api_InstanceOptions_To_v1beta1_InstanceOptions(in *api.InstanceOptions, out *apiv1beta1.InstanceOptions, s conversion.Scope) error {
+	return autoConvert_api_InstanceOptions_To_v1beta1_InstanceOptions(in, out, s)
+}
+
+func autoConvert_v1beta1_KernelOptions_To_api_KernelOptions(in *apiv1beta1.KernelOptions, out *api.KernelOptions, s conversion.Scope) error {
+	out.Modules = *(*[]string)(unsafe.Pointer(&in.Modules))
+	out.Args = *(*[]string)(unsafe.Pointer(&in.Args))
+	return nil
+}
+
+// Convert_v1beta1_KernelOptions_To_api_KernelOptions is an autogenerated conversion function.
+func Convert_v1beta1_KernelOptions_To_api_KernelOptions(in *apiv1beta1.KernelOptions, out *api.KernelOptions, s conversion.Scope) error {
+	return autoConvert_v1beta1_KernelOptions_To_api_KernelOptions(in, out, s)
+}
+
+func autoConvert_api_KernelOptions_To_v1beta1_KernelOptions(in *api.KernelOptions, out *apiv1beta1.KernelOptions, s conversion.Scope) error {
+	out.Modules = *(*[]string)(unsafe.Pointer(&in.Modules))
+	out.Args = *(*[]string)(unsafe.Pointer(&in.Args))
+	return nil
+}
+
+// Convert_api_KernelOptions_To_v1beta1_KernelOptions is an autogenerated conversion function.
+func Convert_api_KernelOptions_To_v1beta1_KernelOptions(in *api.KernelOptions, out *apiv1beta1.KernelOptions, s conversion.Scope) error {
+	return autoConvert_api_KernelOptions_To_v1beta1_KernelOptions(in, out, s)
+}
+
+func autoConvert_v1beta1_KubeletOptions_To_api_KubeletOptions(in *apiv1beta1.KubeletOptions, out *api.KubeletOptions, s conversion.Scope) error {
+	out.Config = *(*api.InlineDocument)(unsafe.Pointer(&in.Config))
+	out.Flags = *(*api.KubeletFlags)(unsafe.Pointer(&in.Flags))
+	out.MaxPodsExpression = in.MaxPodsExpression
+	if err := Convert_v1beta1_MaxPodsStrategy_To_api_MaxPodsStrategy(&in.MaxPodsStrategy, &out.MaxPodsStrategy, s); err != nil {
+		return err
+	}
+	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
+	out.Taints = *(*[]api.Taint)(unsafe.Pointer(&in.Taints))
+	out.AutoLabels = *(*[]api.AutoLabel)(unsafe.Pointer(&in.AutoLabels))
+	if err := Convert_v1beta1_ReservedResourceExpressions_To_api_ReservedResourceExpressions(&in.KubeReservedExpressions, &out.KubeReservedExpressions, s); err != nil {
+		return err
+	}
+	if err := Convert_v1beta1_ReservedResourceExpressions_To_api_ReservedResourceExpressions(&in.SystemReservedExpressions, &out.SystemReservedExpressions, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta1_KubeletOptions_To_api_KubeletOptions is an autogenerated conversion function.
+func Convert_v1beta1_KubeletOptions_To_api_KubeletOptions(in *apiv1beta1.KubeletOptions, out *api.KubeletOptions, s conversion.Scope) error {
+	return autoConvert_v1beta1_KubeletOptions_To_api_KubeletOptions(in, out, s)
+}
+
+func autoConvert_api_KubeletOptions_To_v1beta1_KubeletOptions(in *api.KubeletOptions, out *apiv1beta1.KubeletOptions, s conversion.Scope) error {
+	out.Config = *(*map[string]runtime.RawExtension)(unsafe.Pointer(&in.Config))
+	out.Flags = *(*[]string)(unsafe.Pointer(&in.Flags))
+	out.MaxPodsExpression = in.MaxPodsExpression
+	if err := Convert_api_MaxPodsStrategy_To_v1beta1_MaxPodsStrategy(&in.MaxPodsStrategy, &out.MaxPodsStrategy, s); err != nil {
+		return err
+	}
+	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
+	out.Taints = *(*[]apiv1beta1.Taint)(unsafe.Pointer(&in.Taints))
+	out.AutoLabels = *(*[]apiv1beta1.AutoLabel)(unsafe.Pointer(&in.AutoLabels))
+	if err := Convert_api_ReservedResourceExpressions_To_v1beta1_ReservedResourceExpressions(&in.KubeReservedExpressions, &out.KubeReservedExpressions, s); err != nil {
+		return err
+	}
+	if err := Convert_api_ReservedResourceExpressions_To_v1beta1_ReservedResourceExpressions(&in.SystemReservedExpressions, &out.SystemReservedExpressions, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_api_KubeletOptions_To_v1beta1_KubeletOptions is an autogenerated conversion function.
+func Convert_api_KubeletOptions_To_v1beta1_KubeletOptions(in *api.KubeletOptions, out *apiv1beta1.KubeletOptions, s conversion.Scope) error {
+	return autoConvert_api_KubeletOptions_To_v1beta1_KubeletOptions(in, out, s)
+}
+
+func autoConvert_v1beta1_LocalStorageOptions_To_api_LocalStorageOptions(in *apiv1beta1.LocalStorageOptions, out *api.LocalStorageOptions, s conversion.Scope) error {
+	out.Strategy = api.LocalStorageStrategy(in.Strategy)
+	out.MountPath = in.MountPath
+	out.DisabledMounts = *(*[]api.DisabledMount)(unsafe.Pointer(&in.DisabledMounts))
+	return nil
+}
+
+// Convert_v1beta1_LocalStorageOptions_To_api_LocalStorageOptions is an autogenerated conversion function.
+func Convert_v1beta1_LocalStorageOptions_To_api_LocalStorageOptions(in *apiv1beta1.LocalStorageOptions, out *api.LocalStorageOptions, s conversion.Scope) error {
+	return autoConvert_v1beta1_LocalStorageOptions_To_api_LocalStorageOptions(in, out, s)
+}
+
+func autoConvert_api_LocalStorageOptions_To_v1beta1_LocalStorageOptions(in *api.LocalStorageOptions, out *apiv1beta1.LocalStorageOptions, s conversion.Scope) error {
+	out.Strategy = apiv1beta1.LocalStorageStrategy(in.Strategy)
+	out.MountPath = in.MountPath
+	out.DisabledMounts = *(*[]apiv1beta1.DisabledMount)(unsafe.Pointer(&in.DisabledMounts))
+	return nil
+}
+
+// Convert_api_LocalStorageOptions_To_v1beta1_LocalStorageOptions is an autogenerated conversion function.
+func Convert_api_LocalStorageOptions_To_v1beta1_LocalStorageOptions(in *api.LocalStorageOptions, out *apiv1beta1.LocalStorageOptions, s conversion.Scope) error {
+	return autoConvert_api_LocalStorageOptions_To_v1beta1_LocalStorageOptions(in, out, s)
+}
+
+func autoConvert_v1beta1_MaxPodsStrategy_To_api_MaxPodsStrategy(in *apiv1beta1.MaxPodsStrategy, out *api.MaxPodsStrategy, s conversion.Scope) error {
+	out.PrefixDelegation = in.PrefixDelegation
+	out.CustomNetworking = in.CustomNetworking
+	out.CapByVCPU = in.CapByVCPU
+	return nil
+}
+
+// Convert_v1beta1_MaxPodsStrategy_To_api_MaxPodsStrategy is an autogenerated conversion function.
+func Convert_v1beta1_MaxPodsStrategy_To_api_MaxPodsStrategy(in *apiv1beta1.MaxPodsStrategy, out *api.MaxPodsStrategy, s conversion.Scope) error {
+	return autoConvert_v1beta1_MaxPodsStrategy_To_api_MaxPodsStrategy(in, out, s)
+}
+
+func autoConvert_api_MaxPodsStrategy_To_v1beta1_MaxPodsStrategy(in *api.MaxPodsStrategy, out *apiv1beta1.MaxPodsStrategy, s conversion.Scope) error {
+	out.PrefixDelegation = in.PrefixDelegation
+	out.CustomNetworking = in.CustomNetworking
+	out.CapByVCPU = in.CapByVCPU
+	return nil
+}
+
+// Convert_api_MaxPodsStrategy_To_v1beta1_MaxPodsStrategy is an autogenerated conversion function.
+func Convert_api_MaxPodsStrategy_To_v1beta1_MaxPodsStrategy(in *api.MaxPodsStrategy, out *apiv1beta1.MaxPodsStrategy, s conversion.Scope) error {
+	return autoConvert_api_MaxPodsStrategy_To_v1beta1_MaxPodsStrategy(in, out, s)
+}
+
+func autoConvert_v1beta1_NetworkOptions_To_api_NetworkOptions(in *apiv1beta1.NetworkOptions, out *api.NetworkOptions, s conversion.Scope) error {
+	out.Nameservers = *(*[]string)(unsafe.Pointer(&in.Nameservers))
+	out.Domains = *(*[]string)(unsafe.Pointer(&in.Domains))
+	return nil
+}
+
+// Convert_v1beta1_NetworkOptions_To_api_NetworkOptions is an autogenerated conversion function.
+func Convert_v1beta1_NetworkOptions_To_api_NetworkOptions(in *apiv1beta1.NetworkOptions, out *api.NetworkOptions, s conversion.Scope) error {
+	return autoConvert_v1beta1_NetworkOptions_To_api_NetworkOptions(in, out, s)
+}
+
+func autoConvert_api_NetworkOptions_To_v1beta1_NetworkOptions(in *api.NetworkOptions, out *apiv1beta1.NetworkOptions, s conversion.Scope) error {
+	out.Nameservers = *(*[]string)(unsafe.Pointer(&in.Nameservers))
+	out.Domains = *(*[]string)(unsafe.Pointer(&in.Domains))
+	return nil
+}
+
+// Convert_api_NetworkOptions_To_v1beta1_NetworkOptions is an autogenerated conversion function.
+func Convert_api_NetworkOptions_To_v1beta1_NetworkOptions(in *api.NetworkOptions, out *apiv1beta1.NetworkOptions, s conversion.Scope) error {
+	return autoConvert_api_NetworkOptions_To_v1beta1_NetworkOptions(in, out, s)
+}
+
+func autoConvert_v1beta1_NodeConfig_To_api_NodeConfig(in *apiv1beta1.NodeConfig, out *api.NodeConfig, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1beta1_NodeConfigSpec_To_api_NodeConfigSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1beta1_NodeConfig_To_api_NodeConfig is an autogenerated conversion function.
+func Convert_v1beta1_NodeConfig_To_api_NodeConfig(in *apiv1beta1.NodeConfig, out *api.NodeConfig, s conversion.Scope) error {
+	return autoConvert_v1beta1_NodeConfig_To_api_NodeConfig(in, out, s)
+}
+
+func autoConvert_api_NodeConfig_To_v1beta1_NodeConfig(in *api.NodeConfig, out *apiv1beta1.NodeConfig, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_api_NodeConfigSpec_To_v1beta1_NodeConfigSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
+	// INFO: in.Status opted out of conversion generation
+	// INFO: in.Origins opted out of conversion generation
+	return nil
+}
+
+// Convert_api_NodeConfig_To_v1beta1_NodeConfig is an autogenerated conversion function.
+func Convert_api_NodeConfig_To_v1beta1_NodeConfig(in *api.NodeConfig, out *apiv1beta1.NodeConfig, s conversion.Scope) error {
+	return autoConvert_api_NodeConfig_To_v1beta1_NodeConfig(in, out, s)
+}
+
+func autoConvert_v1beta1_NodeConfigList_To_api_NodeConfigList(in *apiv1beta1.NodeConfigList, out *api.NodeConfigList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]api.NodeConfig, len(*in))
+		for i := range *in {
+			if err := Convert_v1beta1_NodeConfig_To_api_NodeConfig(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
+	return nil
+}
+
+// Convert_v1beta1_NodeConfigList_To_api_NodeConfigList is an autogenerated conversion function.
+func Convert_v1beta1_NodeConfigList_To_api_NodeConfigList(in *apiv1beta1.NodeConfigList, out *api.NodeConfigList, s conversion.Scope) error {
+	return autoConvert_v1beta1_NodeConfigList_To_api_NodeConfigList(in, out, s)
+}
+
+func autoConvert_api_NodeConfigList_To_v1beta1_NodeConfigList(in *api.NodeConfigList, out *apiv1beta1.NodeConfigList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]apiv1beta1.NodeConfig, len(*in))
+		for i := range *in {
+			if err := Convert_api_NodeConfig_To_v1beta1_NodeConfig(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
+	return nil
+}
+
+// Convert_api_NodeConfigList_To_v1beta1_NodeConfigList is an autogenerated conversion function.
+func Convert_api_NodeConfigList_To_v1beta1_NodeConfigList(in *api.NodeConfigList, out *apiv1beta1.NodeConfigList, s conversion.Scope) error {
+	return autoConvert_api_NodeConfigList_To_v1beta1_NodeConfigList(in, out, s)
+}
+
+func autoConvert_v1beta1_NodeConfigSpec_To_api_NodeConfigSpec(in *apiv1beta1.NodeConfigSpec, out *api.NodeConfigSpec, s conversion.Scope) error {
+	if err := Convert_v1beta1_ClusterDetails_To_api_ClusterDetails(&in.Cluster, &out.Cluster, s); err != nil {
+		return err
+	}
+	if err := Convert_v1beta1_ContainerdOptions_To_api_ContainerdOptions(&in.Containerd, &out.Containerd, s); err != nil {
+		return err
+	}
+	if err := Convert_v1beta1_InstanceOptions_To_api_InstanceOptions(&in.Instance, &out.Instance, s); err != nil {
+		return err
+	}
+	if err := Convert_v1beta1_KubeletOptions_To_api_KubeletOptions(&in.Kubelet, &out.Kubelet, s); err != nil {
+		return err
+	}
+	out.FeatureGates = *(*map[api.Feature]bool)(unsafe.Pointer(&in.FeatureGates))
+	return nil
+}
+
+// Convert_v1beta1_NodeConfigSpec_To_api_NodeConfigSpec is an autogenerated conversion function.
+func Convert_v1beta1_NodeConfigSpec_To_api_NodeConfigSpec(in *apiv1beta1.NodeConfigSpec, out *api.NodeConfigSpec, s conversion.Scope) error {
+	return autoConvert_v1beta1_NodeConfigSpec_To_api_NodeConfigSpec(in, out, s)
+}
+
+func autoConvert_api_NodeConfigSpec_To_v1beta1_NodeConfigSpec(in *api.NodeConfigSpec, out *apiv1beta1.NodeConfigSpec, s conversion.Scope) error {
+	if err := Convert_api_ClusterDetails_To_v1beta1_ClusterDetails(&in.Cluster, &out.Cluster, s); err != nil {
+		return err
+	}
+	if err := Convert_api_ContainerdOptions_To_v1beta1_ContainerdOptions(&in.Containerd, &out.Containerd, s); err != nil {
+		return err
+	}
+	if err := Convert_api_InstanceOptions_To_v1beta1_InstanceOptions(&in.Instance, &out.Instance, s); err != nil {
+		return err
+	}
+	if err := Convert_api_KubeletOptions_To_v1beta1_KubeletOptions(&in.Kubelet, &out.Kubelet, s); err != nil {
+		return err
+	}
+	out.FeatureGates = *(*map[apiv1beta1.Feature]bool)(unsafe.Pointer(&in.FeatureGates))
+	return nil
+}
+
+// Convert_api_NodeConfigSpec_To_v1beta1_NodeConfigSpec is an autogenerated conversion function.
+func Convert_api_NodeConfigSpec_To_v1beta1_NodeConfigSpec(in *api.NodeConfigSpec, out *apiv1beta1.NodeConfigSpec, s conversion.Scope) error {
+	return autoConvert_api_NodeConfigSpec_To_v1beta1_NodeConfigSpec(in, out, s)
+}
+
+func autoConvert_v1beta1_PrefetchImage_To_api_PrefetchImage(in *apiv1beta1.PrefetchImage, out *api.PrefetchImage, s conversion.Scope) error {
+	out.Reference = in.Reference
+	out.Pin = in.Pin
+	return nil
+}
+
+// Convert_v1beta1_PrefetchImage_To_api_PrefetchImage is an autogenerated conversion function.
+func Convert_v1beta1_PrefetchImage_To_api_PrefetchImage(in *apiv1beta1.PrefetchImage, out *api.PrefetchImage, s conversion.Scope) error {
+	return autoConvert_v1beta1_PrefetchImage_To_api_PrefetchImage(in, out, s)
+}
+
+func autoConvert_api_PrefetchImage_To_v1beta1_PrefetchImage(in *api.PrefetchImage, out *apiv1beta1.PrefetchImage, s conversion.Scope) error {
+	out.Reference = in.Reference
+	out.Pin = in.Pin
+	return nil
+}
+
+// Convert_api_PrefetchImage_To_v1beta1_PrefetchImage is an autogenerated conversion function.
+func Convert_api_PrefetchImage_To_v1beta1_PrefetchImage(in *api.PrefetchImage, out *apiv1beta1.PrefetchImage, s conversion.Scope) error {
+	return autoConvert_api_PrefetchImage_To_v1beta1_PrefetchImage(in, out, s)
+}
+
+func autoConvert_v1beta1_RegistryMirror_To_api_RegistryMirror(in *apiv1beta1.RegistryMirror, out *api.RegistryMirror, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Capabilities = *(*[]api.RegistryCapability)(unsafe.Pointer(&in.Capabilities))
+	out.CA = in.CA
+	out.SkipVerify = in.SkipVerify
+	out.OverridePath = in.OverridePath
+	return nil
+}
+
+// Convert_v1beta1_RegistryMirror_To_api_RegistryMirror is an autogenerated conversion function.
+func Convert_v1beta1_RegistryMirror_To_api_RegistryMirror(in *apiv1beta1.RegistryMirror, out *api.RegistryMirror, s conversion.Scope) error {
+	return autoConvert_v1beta1_RegistryMirror_To_api_RegistryMirror(in, out, s)
+}
+
+func autoConvert_api_RegistryMirror_To_v1beta1_RegistryMirror(in *api.RegistryMirror, out *apiv1beta1.RegistryMirror, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Capabilities = *(*[]apiv1beta1.RegistryCapability)(unsafe.Pointer(&in.Capabilities))
+	out.CA = in.CA
+	out.SkipVerify = in.SkipVerify
+	out.OverridePath = in.OverridePath
+	return nil
+}
+
+// Convert_api_RegistryMirror_To_v1beta1_RegistryMirror is an autogenerated conversion function.
+func Convert_api_RegistryMirror_To_v1beta1_RegistryMirror(in *api.RegistryMirror, out *apiv1beta1.RegistryMirror, s conversion.Scope) error {
+	return autoConvert_api_RegistryMirror_To_v1beta1_RegistryMirror(in, out, s)
+}
+
+func autoConvert_v1beta1_RegistryOptions_To_api_RegistryOptions(in *apiv1beta1.RegistryOptions, out *api.RegistryOptions, s conversion.Scope) error {
+	out.Server = in.Server
+	out.Mirrors = *(*[]api.RegistryMirror)(unsafe.Pointer(&in.Mirrors))
+	out.CA = in.CA
+	out.SkipVerify = in.SkipVerify
+	return nil
+}
+
+// Convert_v1beta1_RegistryOptions_To_api_RegistryOptions is an autogenerated conversion function.
+func Convert_v1beta1_RegistryOptions_To_api_RegistryOptions(in *apiv1beta1.RegistryOptions, out *api.RegistryOptions, s conversion.Scope) error {
+	return autoConvert_v1beta1_RegistryOptions_To_api_RegistryOptions(in, out, s)
+}
+
+func autoConvert_api_RegistryOptions_To_v1beta1_RegistryOptions(in *api.RegistryOptions, out *apiv1beta1.RegistryOptions, s conversion.Scope) error {
+	out.Server = in.Server
+	out.Mirrors = *(*[]apiv1beta1.RegistryMirror)(unsafe.Pointer(&in.Mirrors))
+	out.CA = in.CA
+	out.SkipVerify = in.SkipVerify
+	return nil
+}
+
+// Convert_api_RegistryOptions_To_v1beta1_RegistryOptions is an autogenerated conversion function.
+func Convert_api_RegistryOptions_To_v1beta1_RegistryOptions(in *api.RegistryOptions, out *apiv1beta1.RegistryOptions, s conversion.Scope) error {
+	return autoConvert_api_RegistryOptions_To_v1beta1_RegistryOptions(in, out, s)
+}
+
+func autoConvert_v1beta1_ReservedResourceExpressions_To_api_ReservedResourceExpressions(in *apiv1beta1.ReservedResourceExpressions, out *api.ReservedResourceExpressions, s conversion.Scope) error {
+	out.CPU = in.CPU
+	out.Memory = in.Memory
+	out.EphemeralStorage = in.EphemeralStorage
+	return nil
+}
+
+// Convert_v1beta1_ReservedResourceExpressions_To_api_ReservedResourceExpressions is an autogenerated conversion function.
+func Convert_v1beta1_ReservedResourceExpressions_To_api_ReservedResourceExpressions(in *apiv1beta1.ReservedResourceExpressions, out *api.ReservedResourceExpressions, s conversion.Scope) error {
+	return autoConvert_v1beta1_ReservedResourceExpressions_To_api_ReservedResourceExpressions(in, out, s)
+}
+
+func autoConvert_api_ReservedResourceExpressions_To_v1beta1_ReservedResourceExpressions(in *api.ReservedResourceExpressions, out *apiv1beta1.ReservedResourceExpressions, s conversion.Scope) error {
+	out.CPU = in.CPU
+	out.Memory = in.Memory
+	out.EphemeralStorage = in.EphemeralStorage
+	return nil
+}
+
+// Convert_api_ReservedResourceExpressions_To_v1beta1_ReservedResourceExpressions is an autogenerated conversion function.
+func Convert_api_ReservedResourceExpressions_To_v1beta1_ReservedResourceExpressions(in *api.ReservedResourceExpressions, out *apiv1beta1.ReservedResourceExpressions, s conversion.Scope) error {
+	return autoConvert_api_ReservedResourceExpressions_To_v1beta1_ReservedResourceExpressions(in, out, s)
+}
+
+func autoConvert_v1beta1_SOCIOptions_To_api_SOCIOptions(in *apiv1beta1.SOCIOptions, out *api.SOCIOptions, s conversion.Scope) error {
+	out.MinMemory = (*resource.Quantity)(unsafe.Pointer(in.MinMemory))
+	out.MinCPU = (*resource.Quantity)(unsafe.Pointer(in.MinCPU))
+	out.MaxConcurrentDownloads = in.MaxConcurrentDownloads
+	out.MaxConcurrentDownloadsPerImage = in.MaxConcurrentDownloadsPerImage
+	out.MaxConcurrentUnpacks = in.MaxConcurrentUnpacks
+	out.MaxConcurrentUnpacksPerImage = in.MaxConcurrentUnpacksPerImage
+	out.Config = api.SOCIConfig(in.Config)
+	return nil
+}
+
+// Convert_v1beta1_SOCIOptions_To_api_SOCIOptions is an autogenerated conversion function.
+func Convert_v1beta1_SOCIOptions_To_api_SOCIOptions(in *apiv1beta1.SOCIOptions, out *api.SOCIOptions, s conversion.Scope) error {
+	return autoConvert_v1beta1_SOCIOptions_To_api_SOCIOptions(in, out, s)
+}
+
+func autoConvert_api_SOCIOptions_To_v1beta1_SOCIOptions(in *api.SOCIOptions, out *apiv1beta1.SOCIOptions, s conversion.Scope) error {
+	out.MinMemory = (*resource.Quantity)(unsafe.Pointer(in.MinMemory))
+	out.MinCPU = (*resource.Quantity)(unsafe.Pointer(in.MinCPU))
+	out.MaxConcurrentDownloads = in.MaxConcurrentDownloads
+	out.MaxConcurrentDownloadsPerImage = in.MaxConcurrentDownloadsPerImage
+	out.MaxConcurrentUnpacks = in.MaxConcurrentUnpacks
+	out.MaxConcurrentUnpacksPerImage = in.MaxConcurrentUnpacksPerImage
+	out.Config = string(in.Config)
+	return nil
+}
+
+// Convert_api_SOCIOptions_To_v1beta1_SOCIOptions is an autogenerated conversion function.
+func Convert_api_SOCIOptions_To_v1beta1_SOCIOptions(in *api.SOCIOptions, out *apiv1beta1.SOCIOptions, s conversion.Scope) error {
+	return autoConvert_api_SOCIOptions_To_v1beta1_SOCIOptions(in, out, s)
+}
+
+func autoConvert_v1beta1_Taint_To_api_Taint(in *apiv1beta1.Taint, out *api.Taint, s conversion.Scope) error {
+	out.Key = in.Key
+	out.Value = in.Value
+	out.Effect = api.TaintEffect(in.Effect)
+	return nil
+}
+
+// Convert_v1beta1_Taint_To_api_Taint is an autogenerated conversion function.
+func Convert_v1beta1_Taint_To_api_Taint(in *apiv1beta1.Taint, out *api.Taint, s conversion.Scope) error {
+	return autoConvert_v1beta1_Taint_To_api_Taint(in, out, s)
+}
+
+func autoConvert_api_Taint_To_v1beta1_Taint(in *api.Taint, out *apiv1beta1.Taint, s conversion.Scope) error {
+	out.Key = in.Key
+	out.Value = in.Value
+	out.Effect = apiv1beta1.TaintEffect(in.Effect)
+	return nil
+}
+
+// Convert_api_Taint_To_v1beta1_Taint is an autogenerated conversion function.
+func Convert_api_Taint_To_v1beta1_Taint(in *api.Taint, out *apiv1beta1.Taint, s conversion.Scope) error {
+	return autoConvert_api_Taint_To_v1beta1_Taint(in, out, s)
+}
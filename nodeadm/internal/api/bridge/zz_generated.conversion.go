@@ -10,8 +10,11 @@ import (
 
 	v1alpha1 "github.com/awslabs/amazon-eks-ami/nodeadm/api/v1alpha1"
 	api "github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+	resource "k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	conversion "k8s.io/apimachinery/pkg/conversion"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	intstr "k8s.io/apimachinery/pkg/util/intstr"
 )
 
 func init() {
@@ -41,6 +44,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha1.ContainerdRuntime)(nil), (*api.ContainerdRuntime)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ContainerdRuntime_To_api_ContainerdRuntime(a.(*v1alpha1.ContainerdRuntime), b.(*api.ContainerdRuntime), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*api.ContainerdRuntime)(nil), (*v1alpha1.ContainerdRuntime)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_api_ContainerdRuntime_To_v1alpha1_ContainerdRuntime(a.(*api.ContainerdRuntime), b.(*v1alpha1.ContainerdRuntime), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha1.InstanceOptions)(nil), (*api.InstanceOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_InstanceOptions_To_api_InstanceOptions(a.(*v1alpha1.InstanceOptions), b.(*api.InstanceOptions), scope)
 	}); err != nil {
@@ -51,6 +64,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha1.KernelOptions)(nil), (*api.KernelOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_KernelOptions_To_api_KernelOptions(a.(*v1alpha1.KernelOptions), b.(*api.KernelOptions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*api.KernelOptions)(nil), (*v1alpha1.KernelOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_api_KernelOptions_To_v1alpha1_KernelOptions(a.(*api.KernelOptions), b.(*v1alpha1.KernelOptions), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha1.KubeletOptions)(nil), (*api.KubeletOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_KubeletOptions_To_api_KubeletOptions(a.(*v1alpha1.KubeletOptions), b.(*api.KubeletOptions), scope)
 	}); err != nil {
@@ -71,6 +94,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha1.MaxPodsStrategy)(nil), (*api.MaxPodsStrategy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_MaxPodsStrategy_To_api_MaxPodsStrategy(a.(*v1alpha1.MaxPodsStrategy), b.(*api.MaxPodsStrategy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*api.MaxPodsStrategy)(nil), (*v1alpha1.MaxPodsStrategy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_api_MaxPodsStrategy_To_v1alpha1_MaxPodsStrategy(a.(*api.MaxPodsStrategy), b.(*v1alpha1.MaxPodsStrategy), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha1.NetworkOptions)(nil), (*api.NetworkOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_NetworkOptions_To_api_NetworkOptions(a.(*v1alpha1.NetworkOptions), b.(*api.NetworkOptions), scope)
 	}); err != nil {
@@ -111,6 +144,66 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha1.PrefetchImage)(nil), (*api.PrefetchImage)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_PrefetchImage_To_api_PrefetchImage(a.(*v1alpha1.PrefetchImage), b.(*api.PrefetchImage), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*api.PrefetchImage)(nil), (*v1alpha1.PrefetchImage)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_api_PrefetchImage_To_v1alpha1_PrefetchImage(a.(*api.PrefetchImage), b.(*v1alpha1.PrefetchImage), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha1.RegistryMirror)(nil), (*api.RegistryMirror)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_RegistryMirror_To_api_RegistryMirror(a.(*v1alpha1.RegistryMirror), b.(*api.RegistryMirror), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*api.RegistryMirror)(nil), (*v1alpha1.RegistryMirror)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_api_RegistryMirror_To_v1alpha1_RegistryMirror(a.(*api.RegistryMirror), b.(*v1alpha1.RegistryMirror), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha1.RegistryOptions)(nil), (*api.RegistryOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_RegistryOptions_To_api_RegistryOptions(a.(*v1alpha1.RegistryOptions), b.(*api.RegistryOptions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*api.RegistryOptions)(nil), (*v1alpha1.RegistryOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_api_RegistryOptions_To_v1alpha1_RegistryOptions(a.(*api.RegistryOptions), b.(*v1alpha1.RegistryOptions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha1.ReservedResourceExpressions)(nil), (*api.ReservedResourceExpressions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ReservedResourceExpressions_To_api_ReservedResourceExpressions(a.(*v1alpha1.ReservedResourceExpressions), b.(*api.ReservedResourceExpressions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*api.ReservedResourceExpressions)(nil), (*v1alpha1.ReservedResourceExpressions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_api_ReservedResourceExpressions_To_v1alpha1_ReservedResourceExpressions(a.(*api.ReservedResourceExpressions), b.(*v1alpha1.ReservedResourceExpressions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha1.SOCIOptions)(nil), (*api.SOCIOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_SOCIOptions_To_api_SOCIOptions(a.(*v1alpha1.SOCIOptions), b.(*api.SOCIOptions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*api.SOCIOptions)(nil), (*v1alpha1.SOCIOptions)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_api_SOCIOptions_To_v1alpha1_SOCIOptions(a.(*api.SOCIOptions), b.(*v1alpha1.SOCIOptions), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha1.Taint)(nil), (*api.Taint)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_Taint_To_api_Taint(a.(*v1alpha1.Taint), b.(*api.Taint), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*api.Taint)(nil), (*v1alpha1.Taint)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_api_Taint_To_v1alpha1_Taint(a.(*api.Taint), b.(*v1alpha1.Taint), scope)
+	}); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -147,6 +240,15 @@ func Convert_api_ClusterDetails_To_v1alpha1_ClusterDetails(in *api.ClusterDetail
 func autoConvert_v1alpha1_ContainerdOptions_To_api_ContainerdOptions(in *v1alpha1.ContainerdOptions, out *api.ContainerdOptions, s conversion.Scope) error {
 	out.Config = api.ContainerdConfig(in.Config)
 	out.BaseRuntimeSpec = *(*api.InlineDocument)(unsafe.Pointer(&in.BaseRuntimeSpec))
+	out.Registries = *(*map[string]api.RegistryOptions)(unsafe.Pointer(&in.Registries))
+	out.Runtimes = *(*[]api.ContainerdRuntime)(unsafe.Pointer(&in.Runtimes))
+	out.PrefetchImages = *(*[]api.PrefetchImage)(unsafe.Pointer(&in.PrefetchImages))
+	out.PrefetchImagesTimeout = (*v1.Duration)(unsafe.Pointer(in.PrefetchImagesTimeout))
+	out.SandboxImage = in.SandboxImage
+	out.ImageArchives = *(*[]string)(unsafe.Pointer(&in.ImageArchives))
+	if err := Convert_v1alpha1_SOCIOptions_To_api_SOCIOptions(&in.SOCI, &out.SOCI, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -158,6 +260,15 @@ func Convert_v1alpha1_ContainerdOptions_To_api_ContainerdOptions(in *v1alpha1.Co
 func autoConvert_api_ContainerdOptions_To_v1alpha1_ContainerdOptions(in *api.ContainerdOptions, out *v1alpha1.ContainerdOptions, s conversion.Scope) error {
 	out.Config = string(in.Config)
 	out.BaseRuntimeSpec = *(*map[string]runtime.RawExtension)(unsafe.Pointer(&in.BaseRuntimeSpec))
+	out.Registries = *(*map[string]v1alpha1.RegistryOptions)(unsafe.Pointer(&in.Registries))
+	out.Runtimes = *(*[]v1alpha1.ContainerdRuntime)(unsafe.Pointer(&in.Runtimes))
+	out.PrefetchImages = *(*[]v1alpha1.PrefetchImage)(unsafe.Pointer(&in.PrefetchImages))
+	out.PrefetchImagesTimeout = (*v1.Duration)(unsafe.Pointer(in.PrefetchImagesTimeout))
+	out.SandboxImage = in.SandboxImage
+	out.ImageArchives = *(*[]string)(unsafe.Pointer(&in.ImageArchives))
+	if err := Convert_api_SOCIOptions_To_v1alpha1_SOCIOptions(&in.SOCI, &out.SOCI, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -166,6 +277,34 @@ func Convert_api_ContainerdOptions_To_v1alpha1_ContainerdOptions(in *api.Contain
 	return autoConvert_api_ContainerdOptions_To_v1alpha1_ContainerdOptions(in, out, s)
 }
 
+func autoConvert_v1alpha1_ContainerdRuntime_To_api_ContainerdRuntime(in *v1alpha1.ContainerdRuntime, out *api.ContainerdRuntime, s conversion.Scope) error {
+	out.Name = in.Name
+	out.BinaryPath = in.BinaryPath
+	out.RuntimeType = in.RuntimeType
+	out.Options = *(*api.InlineDocument)(unsafe.Pointer(&in.Options))
+	out.Default = in.Default
+	return nil
+}
+
+// Convert_v1alpha1_ContainerdRuntime_To_api_ContainerdRuntime is an autogenerated conversion function.
+func Convert_v1alpha1_ContainerdRuntime_To_api_ContainerdRuntime(in *v1alpha1.ContainerdRuntime, out *api.ContainerdRuntime, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ContainerdRuntime_To_api_ContainerdRuntime(in, out, s)
+}
+
+func autoConvert_api_ContainerdRuntime_To_v1alpha1_ContainerdRuntime(in *api.ContainerdRuntime, out *v1alpha1.ContainerdRuntime, s conversion.Scope) error {
+	out.Name = in.Name
+	out.BinaryPath = in.BinaryPath
+	out.RuntimeType = in.RuntimeType
+	out.Options = *(*map[string]runtime.RawExtension)(unsafe.Pointer(&in.Options))
+	out.Default = in.Default
+	return nil
+}
+
+// Convert_api_ContainerdRuntime_To_v1alpha1_ContainerdRuntime is an autogenerated conversion function.
+func Convert_api_ContainerdRuntime_To_v1alpha1_ContainerdRuntime(in *api.ContainerdRuntime, out *v1alpha1.ContainerdRuntime, s conversion.Scope) error {
+	return autoConvert_api_ContainerdRuntime_To_v1alpha1_ContainerdRuntime(in, out, s)
+}
+
 func autoConvert_v1alpha1_InstanceOptions_To_api_InstanceOptions(in *v1alpha1.InstanceOptions, out *api.InstanceOptions, s conversion.Scope) error {
 	if err := Convert_v1alpha1_LocalStorageOptions_To_api_LocalStorageOptions(&in.LocalStorage, &out.LocalStorage, s); err != nil {
 		return err
@@ -174,6 +313,11 @@ func autoConvert_v1alpha1_InstanceOptions_To_api_InstanceOptions(in *v1alpha1.In
 	if err := Convert_v1alpha1_NetworkOptions_To_api_NetworkOptions(&in.Network, &out.Network, s); err != nil {
 		return err
 	}
+	out.Sysctls = *(*map[string]string)(unsafe.Pointer(&in.Sysctls))
+	if err := Convert_v1alpha1_KernelOptions_To_api_KernelOptions(&in.Kernel, &out.Kernel, s); err != nil {
+		return err
+	}
+	out.Hugepages = *(*map[string]intstr.IntOrString)(unsafe.Pointer(&in.Hugepages))
 	return nil
 }
 
@@ -190,6 +334,11 @@ func autoConvert_api_InstanceOptions_To_v1alpha1_InstanceOptions(in *api.Instanc
 	if err := Convert_api_NetworkOptions_To_v1alpha1_NetworkOptions(&in.Network, &out.Network, s); err != nil {
 		return err
 	}
+	out.Sysctls = *(*map[string]string)(unsafe.Pointer(&in.Sysctls))
+	if err := Convert_api_KernelOptions_To_v1alpha1_KernelOptions(&in.Kernel, &out.Kernel, s); err != nil {
+		return err
+	}
+	out.Hugepages = *(*map[string]intstr.IntOrString)(unsafe.Pointer(&in.Hugepages))
 	return nil
 }
 
@@ -198,10 +347,44 @@ func Convert_api_InstanceOptions_To_v1alpha1_InstanceOptions(in *api.InstanceOpt
 	return autoConvert_api_InstanceOptions_To_v1alpha1_InstanceOptions(in, out, s)
 }
 
+func autoConvert_v1alpha1_KernelOptions_To_api_KernelOptions(in *v1alpha1.KernelOptions, out *api.KernelOptions, s conversion.Scope) error {
+	out.Modules = *(*[]string)(unsafe.Pointer(&in.Modules))
+	out.Args = *(*[]string)(unsafe.Pointer(&in.Args))
+	return nil
+}
+
+// Convert_v1alpha1_KernelOptions_To_api_KernelOptions is an autogenerated conversion function.
+func Convert_v1alpha1_KernelOptions_To_api_KernelOptions(in *v1alpha1.KernelOptions, out *api.KernelOptions, s conversion.Scope) error {
+	return autoConvert_v1alpha1_KernelOptions_To_api_KernelOptions(in, out, s)
+}
+
+func autoConvert_api_KernelOptions_To_v1alpha1_KernelOptions(in *api.KernelOptions, out *v1alpha1.KernelOptions, s conversion.Scope) error {
+	out.Modules = *(*[]string)(unsafe.Pointer(&in.Modules))
+	out.Args = *(*[]string)(unsafe.Pointer(&in.Args))
+	return nil
+}
+
+// Convert_api_KernelOptions_To_v1alpha1_KernelOptions is an autogenerated conversion function.
+func Convert_api_KernelOptions_To_v1alpha1_KernelOptions(in *api.KernelOptions, out *v1alpha1.KernelOptions, s conversion.Scope) error {
+	return autoConvert_api_KernelOptions_To_v1alpha1_KernelOptions(in, out, s)
+}
+
 func autoConvert_v1alpha1_KubeletOptions_To_api_KubeletOptions(in *v1alpha1.KubeletOptions, out *api.KubeletOptions, s conversion.Scope) error {
 	out.Config = *(*api.InlineDocument)(unsafe.Pointer(&in.Config))
 	out.Flags = *(*api.KubeletFlags)(unsafe.Pointer(&in.Flags))
 	out.MaxPodsExpression = in.MaxPodsExpression
+	if err := Convert_v1alpha1_MaxPodsStrategy_To_api_MaxPodsStrategy(&in.MaxPodsStrategy, &out.MaxPodsStrategy, s); err != nil {
+		return err
+	}
+	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
+	out.Taints = *(*[]api.Taint)(unsafe.Pointer(&in.Taints))
+	out.AutoLabels = *(*[]api.AutoLabel)(unsafe.Pointer(&in.AutoLabels))
+	if err := Convert_v1alpha1_ReservedResourceExpressions_To_api_ReservedResourceExpressions(&in.KubeReservedExpressions, &out.KubeReservedExpressions, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha1_ReservedResourceExpressions_To_api_ReservedResourceExpressions(&in.SystemReservedExpressions, &out.SystemReservedExpressions, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -214,6 +397,18 @@ func autoConvert_api_KubeletOptions_To_v1alpha1_KubeletOptions(in *api.KubeletOp
 	out.Config = *(*map[string]runtime.RawExtension)(unsafe.Pointer(&in.Config))
 	out.Flags = *(*[]string)(unsafe.Pointer(&in.Flags))
 	out.MaxPodsExpression = in.MaxPodsExpression
+	if err := Convert_api_MaxPodsStrategy_To_v1alpha1_MaxPodsStrategy(&in.MaxPodsStrategy, &out.MaxPodsStrategy, s); err != nil {
+		return err
+	}
+	out.Labels = *(*map[string]string)(unsafe.Pointer(&in.Labels))
+	out.Taints = *(*[]v1alpha1.Taint)(unsafe.Pointer(&in.Taints))
+	out.AutoLabels = *(*[]v1alpha1.AutoLabel)(unsafe.Pointer(&in.AutoLabels))
+	if err := Convert_api_ReservedResourceExpressions_To_v1alpha1_ReservedResourceExpressions(&in.KubeReservedExpressions, &out.KubeReservedExpressions, s); err != nil {
+		return err
+	}
+	if err := Convert_api_ReservedResourceExpressions_To_v1alpha1_ReservedResourceExpressions(&in.SystemReservedExpressions, &out.SystemReservedExpressions, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -246,6 +441,30 @@ func Convert_api_LocalStorageOptions_To_v1alpha1_LocalStorageOptions(in *api.Loc
 	return autoConvert_api_LocalStorageOptions_To_v1alpha1_LocalStorageOptions(in, out, s)
 }
 
+func autoConvert_v1alpha1_MaxPodsStrategy_To_api_MaxPodsStrategy(in *v1alpha1.MaxPodsStrategy, out *api.MaxPodsStrategy, s conversion.Scope) error {
+	out.PrefixDelegation = in.PrefixDelegation
+	out.CustomNetworking = in.CustomNetworking
+	out.CapByVCPU = in.CapByVCPU
+	return nil
+}
+
+// Convert_v1alpha1_MaxPodsStrategy_To_api_MaxPodsStrategy is an autogenerated conversion function.
+func Convert_v1alpha1_MaxPodsStrategy_To_api_MaxPodsStrategy(in *v1alpha1.MaxPodsStrategy, out *api.MaxPodsStrategy, s conversion.Scope) error {
+	return autoConvert_v1alpha1_MaxPodsStrategy_To_api_MaxPodsStrategy(in, out, s)
+}
+
+func autoConvert_api_MaxPodsStrategy_To_v1alpha1_MaxPodsStrategy(in *api.MaxPodsStrategy, out *v1alpha1.MaxPodsStrategy, s conversion.Scope) error {
+	out.PrefixDelegation = in.PrefixDelegation
+	out.CustomNetworking = in.CustomNetworking
+	out.CapByVCPU = in.CapByVCPU
+	return nil
+}
+
+// Convert_api_MaxPodsStrategy_To_v1alpha1_MaxPodsStrategy is an autogenerated conversion function.
+func Convert_api_MaxPodsStrategy_To_v1alpha1_MaxPodsStrategy(in *api.MaxPodsStrategy, out *v1alpha1.MaxPodsStrategy, s conversion.Scope) error {
+	return autoConvert_api_MaxPodsStrategy_To_v1alpha1_MaxPodsStrategy(in, out, s)
+}
+
 func autoConvert_v1alpha1_NetworkOptions_To_api_NetworkOptions(in *v1alpha1.NetworkOptions, out *api.NetworkOptions, s conversion.Scope) error {
 	out.Nameservers = *(*[]string)(unsafe.Pointer(&in.Nameservers))
 	out.Domains = *(*[]string)(unsafe.Pointer(&in.Domains))
@@ -287,6 +506,7 @@ func autoConvert_api_NodeConfig_To_v1alpha1_NodeConfig(in *api.NodeConfig, out *
 		return err
 	}
 	// INFO: in.Status opted out of conversion generation
+	// INFO: in.Origins opted out of conversion generation
 	return nil
 }
 
@@ -380,3 +600,159 @@ func autoConvert_api_NodeConfigSpec_To_v1alpha1_NodeConfigSpec(in *api.NodeConfi
 func Convert_api_NodeConfigSpec_To_v1alpha1_NodeConfigSpec(in *api.NodeConfigSpec, out *v1alpha1.NodeConfigSpec, s conversion.Scope) error {
 	return autoConvert_api_NodeConfigSpec_To_v1alpha1_NodeConfigSpec(in, out, s)
 }
+
+func autoConvert_v1alpha1_PrefetchImage_To_api_PrefetchImage(in *v1alpha1.PrefetchImage, out *api.PrefetchImage, s conversion.Scope) error {
+	out.Reference = in.Reference
+	out.Pin = in.Pin
+	return nil
+}
+
+// Convert_v1alpha1_PrefetchImage_To_api_PrefetchImage is an autogenerated conversion function.
+func Convert_v1alpha1_PrefetchImage_To_api_PrefetchImage(in *v1alpha1.PrefetchImage, out *api.PrefetchImage, s conversion.Scope) error {
+	return autoConvert_v1alpha1_PrefetchImage_To_api_PrefetchImage(in, out, s)
+}
+
+func autoConvert_api_PrefetchImage_To_v1alpha1_PrefetchImage(in *api.PrefetchImage, out *v1alpha1.PrefetchImage, s conversion.Scope) error {
+	out.Reference = in.Reference
+	out.Pin = in.Pin
+	return nil
+}
+
+// Convert_api_PrefetchImage_To_v1alpha1_PrefetchImage is an autogenerated conversion function.
+func Convert_api_PrefetchImage_To_v1alpha1_PrefetchImage(in *api.PrefetchImage, out *v1alpha1.PrefetchImage, s conversion.Scope) error {
+	return autoConvert_api_PrefetchImage_To_v1alpha1_PrefetchImage(in, out, s)
+}
+
+func autoConvert_v1alpha1_RegistryMirror_To_api_RegistryMirror(in *v1alpha1.RegistryMirror, out *api.RegistryMirror, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Capabilities = *(*[]api.RegistryCapability)(unsafe.Pointer(&in.Capabilities))
+	out.CA = in.CA
+	out.SkipVerify = in.SkipVerify
+	out.OverridePath = in.OverridePath
+	return nil
+}
+
+// Convert_v1alpha1_RegistryMirror_To_api_RegistryMirror is an autogenerated conversion function.
+func Convert_v1alpha1_RegistryMirror_To_api_RegistryMirror(in *v1alpha1.RegistryMirror, out *api.RegistryMirror, s conversion.Scope) error {
+	return autoConvert_v1alpha1_RegistryMirror_To_api_RegistryMirror(in, out, s)
+}
+
+func autoConvert_api_RegistryMirror_To_v1alpha1_RegistryMirror(in *api.RegistryMirror, out *v1alpha1.RegistryMirror, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Capabilities = *(*[]v1alpha1.RegistryCapability)(unsafe.Pointer(&in.Capabilities))
+	out.CA = in.CA
+	out.SkipVerify = in.SkipVerify
+	out.OverridePath = in.OverridePath
+	return nil
+}
+
+// Convert_api_RegistryMirror_To_v1alpha1_RegistryMirror is an autogenerated conversion function.
+func Convert_api_RegistryMirror_To_v1alpha1_RegistryMirror(in *api.RegistryMirror, out *v1alpha1.RegistryMirror, s conversion.Scope) error {
+	return autoConvert_api_RegistryMirror_To_v1alpha1_RegistryMirror(in, out, s)
+}
+
+func autoConvert_v1alpha1_RegistryOptions_To_api_RegistryOptions(in *v1alpha1.RegistryOptions, out *api.RegistryOptions, s conversion.Scope) error {
+	out.Server = in.Server
+	out.Mirrors = *(*[]api.RegistryMirror)(unsafe.Pointer(&in.Mirrors))
+	out.CA = in.CA
+	out.SkipVerify = in.SkipVerify
+	return nil
+}
+
+// Convert_v1alpha1_RegistryOptions_To_api_RegistryOptions is an autogenerated conversion function.
+func Convert_v1alpha1_RegistryOptions_To_api_RegistryOptions(in *v1alpha1.RegistryOptions, out *api.RegistryOptions, s conversion.Scope) error {
+	return autoConvert_v1alpha1_RegistryOptions_To_api_RegistryOptions(in, out, s)
+}
+
+func autoConvert_api_RegistryOptions_To_v1alpha1_RegistryOptions(in *api.RegistryOptions, out *v1alpha1.RegistryOptions, s conversion.Scope) error {
+	out.Server = in.Server
+	out.Mirrors = *(*[]v1alpha1.RegistryMirror)(unsafe.Pointer(&in.Mirrors))
+	out.CA = in.CA
+	out.SkipVerify = in.SkipVerify
+	return nil
+}
+
+// Convert_api_RegistryOptions_To_v1alpha1_RegistryOptions is an autogenerated conversion function.
+func Convert_api_RegistryOptions_To_v1alpha1_RegistryOptions(in *api.RegistryOptions, out *v1alpha1.RegistryOptions, s conversion.Scope) error {
+	return autoConvert_api_RegistryOptions_To_v1alpha1_RegistryOptions(in, out, s)
+}
+
+func autoConvert_v1alpha1_ReservedResourceExpressions_To_api_ReservedResourceExpressions(in *v1alpha1.ReservedResourceExpressions, out *api.ReservedResourceExpressions, s conversion.Scope) error {
+	out.CPU = in.CPU
+	out.Memory = in.Memory
+	out.EphemeralStorage = in.EphemeralStorage
+	return nil
+}
+
+// Convert_v1alpha1_ReservedResourceExpressions_To_api_ReservedResourceExpressions is an autogenerated conversion function.
+func Convert_v1alpha1_ReservedResourceExpressions_To_api_ReservedResourceExpressions(in *v1alpha1.ReservedResourceExpressions, out *api.ReservedResourceExpressions, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ReservedResourceExpressions_To_api_ReservedResourceExpressions(in, out, s)
+}
+
+func autoConvert_api_ReservedResourceExpressions_To_v1alpha1_ReservedResourceExpressions(in *api.ReservedResourceExpressions, out *v1alpha1.ReservedResourceExpressions, s conversion.Scope) error {
+	out.CPU = in.CPU
+	out.Memory = in.Memory
+	out.EphemeralStorage = in.EphemeralStorage
+	return nil
+}
+
+// Convert_api_ReservedResourceExpressions_To_v1alpha1_ReservedResourceExpressions is an autogenerated conversion function.
+func Convert_api_ReservedResourceExpressions_To_v1alpha1_ReservedResourceExpressions(in *api.ReservedResourceExpressions, out *v1alpha1.ReservedResourceExpressions, s conversion.Scope) error {
+	return autoConvert_api_ReservedResourceExpressions_To_v1alpha1_ReservedResourceExpressions(in, out, s)
+}
+
+func autoConvert_v1alpha1_SOCIOptions_To_api_SOCIOptions(in *v1alpha1.SOCIOptions, out *api.SOCIOptions, s conversion.Scope) error {
+	out.MinMemory = (*resource.Quantity)(unsafe.Pointer(in.MinMemory))
+	out.MinCPU = (*resource.Quantity)(unsafe.Pointer(in.MinCPU))
+	out.MaxConcurrentDownloads = in.MaxConcurrentDownloads
+	out.MaxConcurrentDownloadsPerImage = in.MaxConcurrentDownloadsPerImage
+	out.MaxConcurrentUnpacks = in.MaxConcurrentUnpacks
+	out.MaxConcurrentUnpacksPerImage = in.MaxConcurrentUnpacksPerImage
+	out.Config = api.SOCIConfig(in.Config)
+	return nil
+}
+
+// Convert_v1alpha1_SOCIOptions_To_api_SOCIOptions is an autogenerated conversion function.
+func Convert_v1alpha1_SOCIOptions_To_api_SOCIOptions(in *v1alpha1.SOCIOptions, out *api.SOCIOptions, s conversion.Scope) error {
+	return autoConvert_v1alpha1_SOCIOptions_To_api_SOCIOptions(in, out, s)
+}
+
+func autoConvert_api_SOCIOptions_To_v1alpha1_SOCIOptions(in *api.SOCIOptions, out *v1alpha1.SOCIOptions, s conversion.Scope) error {
+	out.MinMemory = (*resource.Quantity)(unsafe.Pointer(in.MinMemory))
+	out.MinCPU = (*resource.Quantity)(unsafe.Pointer(in.MinCPU))
+	out.MaxConcurrentDownloads = in.MaxConcurrentDownloads
+	out.MaxConcurrentDownloadsPerImage = in.MaxConcurrentDownloadsPerImage
+	out.MaxConcurrentUnpacks = in.MaxConcurrentUnpacks
+	out.MaxConcurrentUnpacksPerImage = in.MaxConcurrentUnpacksPerImage
+	out.Config = string(in.Config)
+	return nil
+}
+
+// Convert_api_SOCIOptions_To_v1alpha1_SOCIOptions is an autogenerated conversion function.
+func Convert_api_SOCIOptions_To_v1alpha1_SOCIOptions(in *api.SOCIOptions, out *v1alpha1.SOCIOptions, s conversion.Scope) error {
+	return autoConvert_api_SOCIOptions_To_v1alpha1_SOCIOptions(in, out, s)
+}
+
+func autoConvert_v1alpha1_Taint_To_api_Taint(in *v1alpha1.Taint, out *api.Taint, s conversion.Scope) error {
+	out.Key = in.Key
+	out.Value = in.Value
+	out.Effect = api.TaintEffect(in.Effect)
+	return nil
+}
+
+// Convert_v1alpha1_Taint_To_api_Taint is an autogenerated conversion function.
+func Convert_v1alpha1_Taint_To_api_Taint(in *v1alpha1.Taint, out *api.Taint, s conversion.Scope) error {
+	return autoConvert_v1alpha1_Taint_To_api_Taint(in, out, s)
+}
+
+func autoConvert_api_Taint_To_v1alpha1_Taint(in *api.Taint, out *v1alpha1.Taint, s conversion.Scope) error {
+	out.Key = in.Key
+	out.Value = in.Value
+	out.Effect = v1alpha1.TaintEffect(in.Effect)
+	return nil
+}
+
+// Convert_api_Taint_To_v1alpha1_Taint is an autogenerated conversion function.
+func Convert_api_Taint_To_v1alpha1_Taint(in *api.Taint, out *v1alpha1.Taint, s conversion.Scope) error {
+	return autoConvert_api_Taint_To_v1alpha1_Taint(in, out, s)
+}
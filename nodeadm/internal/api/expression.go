@@ -0,0 +1,100 @@
+package api
+
+import (
+	"github.com/google/cel-go/cel"
+)
+
+// Variables available to a KubeletOptions.MaxPodsExpression, along with the
+// instance type variables.
+const (
+	MaxPodsExpressionDefaultENIsVar     = "default_enis"
+	MaxPodsExpressionIPsPerENIVar       = "ips_per_eni"
+	MaxPodsExpressionMaxPodsVar         = "max_pods"
+	MaxPodsExpressionStandardMaxPodsVar = "standard_max_pods"
+	MaxPodsExpressionVCPUsVar           = "vcpus"
+)
+
+// NewMaxPodsExpressionEnv creates the CEL environment used to compile and
+// evaluate a KubeletOptions.MaxPodsExpression.
+func NewMaxPodsExpressionEnv() (*cel.Env, error) {
+	return cel.NewEnv(append([]cel.EnvOption{
+		cel.Variable(MaxPodsExpressionDefaultENIsVar, cel.IntType),
+		cel.Variable(MaxPodsExpressionIPsPerENIVar, cel.IntType),
+		cel.Variable(MaxPodsExpressionMaxPodsVar, cel.IntType),
+		cel.Variable(MaxPodsExpressionStandardMaxPodsVar, cel.IntType),
+		cel.Variable(MaxPodsExpressionVCPUsVar, cel.IntType),
+	}, instanceTypeVariables()...)...)
+}
+
+// Variables available to the expressions of KubeletOptions.KubeReservedExpressions
+// and KubeletOptions.SystemReservedExpressions, along with the instance type
+// variables.
+const (
+	ReservedExpressionVCPUMillicoresVar  = "vcpu_millicores"
+	ReservedExpressionMemoryMebibytesVar = "memory_mebibytes"
+	ReservedExpressionMaxPodsVar         = "max_pods"
+	ReservedExpressionInstanceTypeVar    = "instance_type"
+	ReservedExpressionDefaultVar         = "default_reserved"
+)
+
+// NewReservedExpressionEnv creates the CEL environment used to compile and
+// evaluate the expressions of ReservedResourceExpressions.
+func NewReservedExpressionEnv() (*cel.Env, error) {
+	return cel.NewEnv(append([]cel.EnvOption{
+		cel.Variable(ReservedExpressionVCPUMillicoresVar, cel.IntType),
+		cel.Variable(ReservedExpressionMemoryMebibytesVar, cel.IntType),
+		cel.Variable(ReservedExpressionMaxPodsVar, cel.IntType),
+		cel.Variable(ReservedExpressionInstanceTypeVar, cel.StringType),
+		cel.Variable(ReservedExpressionDefaultVar, cel.IntType),
+	}, instanceTypeVariables()...)...)
+}
+
+// Variables from the instance info of the instance type, available to every
+// expression.
+const (
+	InstanceTypeMaxENIsVar           = "max_enis"
+	InstanceTypeHypervisorVar        = "hypervisor"
+	InstanceTypeIPv6SupportedVar     = "ipv6_supported"
+	InstanceTypeGPUCountVar          = "gpu_count"
+	InstanceTypeNeuronDeviceCountVar = "neuron_device_count"
+	InstanceTypeEFASupportedVar      = "efa_supported"
+	InstanceTypeMaxEFAInterfacesVar  = "max_efa_interfaces"
+	InstanceTypeTrunkingSupportedVar = "trunking_supported"
+	InstanceTypeNetworkCardsVar      = "network_cards"
+)
+
+var instanceTypeVariableTypes = map[string]*cel.Type{
+	InstanceTypeMaxENIsVar:           cel.IntType,
+	InstanceTypeHypervisorVar:        cel.StringType,
+	InstanceTypeIPv6SupportedVar:     cel.BoolType,
+	InstanceTypeGPUCountVar:          cel.IntType,
+	InstanceTypeNeuronDeviceCountVar: cel.IntType,
+	InstanceTypeEFASupportedVar:      cel.BoolType,
+	InstanceTypeMaxEFAInterfacesVar:  cel.IntType,
+	InstanceTypeTrunkingSupportedVar: cel.BoolType,
+	InstanceTypeNetworkCardsVar:      cel.IntType,
+}
+
+func instanceTypeVariables() []cel.EnvOption {
+	var options []cel.EnvOption
+	for name, variableType := range instanceTypeVariableTypes {
+		options = append(options, cel.Variable(name, variableType))
+	}
+	return options
+}
+
+// ReferencesInstanceTypeVariables returns true if the expression, type-checked
+// in env, references any of the instance type variables. An expression that
+// does not compile references none of them.
+func ReferencesInstanceTypeVariables(env *cel.Env, expression string) bool {
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return false
+	}
+	for _, reference := range ast.NativeRep().ReferenceMap() {
+		if _, ok := instanceTypeVariableTypes[reference.Name]; ok {
+			return true
+		}
+	}
+	return false
+}
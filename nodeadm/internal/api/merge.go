@@ -3,12 +3,15 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"reflect"
+	"slices"
 
 	"dario.cat/mergo"
 	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/util"
 	"github.com/pelletier/go-toml/v2"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 func MergeNodeConfigs(nodeConfigs []*NodeConfig) (*NodeConfig, error) {
@@ -26,6 +29,14 @@ func MergeNodeConfigs(nodeConfigs []*NodeConfig) (*NodeConfig, error) {
 
 // Merges two NodeConfigs with custom collision handling
 func (dst *NodeConfig) Merge(src *NodeConfig) error {
+	origins := mergeOrigins(dst, src)
+	srcOrigins := src.Origins
+	// origins are merged separately, because kubelet flags shift the paths
+	// of the fields from src.
+	dst.Origins, src.Origins = nil, nil
+	defer func() {
+		dst.Origins, src.Origins = origins, srcOrigins
+	}()
 	return mergo.Merge(dst, src, mergo.WithOverride, mergo.WithTransformers(nodeConfigTransformer{}))
 }
 
@@ -33,12 +44,20 @@ type nodeConfigTransformer struct{}
 
 func (t nodeConfigTransformer) Transformer(typ reflect.Type) func(dst, src reflect.Value) error {
 	switch typ {
-	case reflect.TypeOf(ContainerdConfig("")):
-		return t.mergeContainerdConfig
+	case reflect.TypeOf(ContainerdConfig("")), reflect.TypeOf(SOCIConfig("")):
+		return t.mergeTOMLConfig
 	case reflect.TypeOf(KubeletFlags{}):
 		return t.mergeKubeletFlags
 	case reflect.TypeOf(InlineDocument{}):
 		return t.mergeInlineDocument
+	case reflect.TypeOf([]Taint{}):
+		return t.mergeTaints
+	case reflect.TypeOf(map[string]intstr.IntOrString{}):
+		return t.mergeHugepages
+	case reflect.TypeOf([]ContainerdRuntime{}):
+		return t.mergeContainerdRuntimes
+	case reflect.TypeOf([]PrefetchImage{}):
+		return t.mergePrefetchImages
 	}
 	return nil
 }
@@ -58,13 +77,94 @@ func (t nodeConfigTransformer) mergeKubeletFlags(dst, src reflect.Value) error {
 	return nil
 }
 
-func (t nodeConfigTransformer) mergeContainerdConfig(dst, src reflect.Value) error {
+func (t nodeConfigTransformer) mergeTaints(dst, src reflect.Value) error {
+	if dst.CanSet() {
+		merged, _ := mergeTaints(dst.Interface().([]Taint), src.Interface().([]Taint))
+		dst.Set(reflect.ValueOf(merged))
+	}
+	return nil
+}
+
+func (t nodeConfigTransformer) mergeHugepages(dst, src reflect.Value) error {
+	if dst.CanSet() {
+		// a number of zero pages is kept, so that it can override the pages of
+		// an earlier config.
+		merged := maps.Clone(dst.Interface().(map[string]intstr.IntOrString))
+		if merged == nil {
+			merged = make(map[string]intstr.IntOrString)
+		}
+		maps.Copy(merged, src.Interface().(map[string]intstr.IntOrString))
+		dst.Set(reflect.ValueOf(merged))
+	}
+	return nil
+}
+
+// mergeTaints merges the taints by key and effect, because the node can only
+// have one taint with each. A taint from src replaces the taint from dst in
+// place, and the other taints from src are appended. The index of each taint
+// from src in the merged taints is also returned.
+func mergeTaints(dst, src []Taint) ([]Taint, []int) {
+	merged := slices.Clone(dst)
+	indexes := make([]int, len(src))
+	for i, taint := range src {
+		index := slices.IndexFunc(merged, func(existing Taint) bool {
+			return existing.Key == taint.Key && existing.Effect == taint.Effect
+		})
+		if index < 0 {
+			index = len(merged)
+			merged = append(merged, taint)
+		} else {
+			merged[index] = taint
+		}
+		indexes[i] = index
+	}
+	return merged, indexes
+}
+
+func (t nodeConfigTransformer) mergeContainerdRuntimes(dst, src reflect.Value) error {
+	if dst.CanSet() {
+		// runtimes are merged by name, because containerd can only have one
+		// runtime handler with each. A runtime from src replaces the runtime
+		// from dst in place, and the other runtimes from src are appended.
+		merged := slices.Clone(dst.Interface().([]ContainerdRuntime))
+		for _, srcRuntime := range src.Interface().([]ContainerdRuntime) {
+			if index := slices.IndexFunc(merged, func(existing ContainerdRuntime) bool { return existing.Name == srcRuntime.Name }); index < 0 {
+				merged = append(merged, srcRuntime)
+			} else {
+				merged[index] = srcRuntime
+			}
+		}
+		dst.Set(reflect.ValueOf(merged))
+	}
+	return nil
+}
+
+func (t nodeConfigTransformer) mergePrefetchImages(dst, src reflect.Value) error {
+	if dst.CanSet() {
+		// images are merged by reference, so that a later config
+		// can change whether an image is pinned without pulling it twice.
+		merged := slices.Clone(dst.Interface().([]PrefetchImage))
+		for _, srcImage := range src.Interface().([]PrefetchImage) {
+			if index := slices.IndexFunc(merged, func(existing PrefetchImage) bool {
+				return existing.Reference == srcImage.Reference
+			}); index < 0 {
+				merged = append(merged, srcImage)
+			} else {
+				merged[index] = srcImage
+			}
+		}
+		dst.Set(reflect.ValueOf(merged))
+	}
+	return nil
+}
+
+func (t nodeConfigTransformer) mergeTOMLConfig(dst, src reflect.Value) error {
 	if dst.CanSet() {
 		if dst.Len() <= 0 {
 			// if the destination is empty just use the source data
 			dst.Set(src)
 		} else if src.Len() > 0 {
-			// containerd config is an inline string in TOML format
+			// containerd and SOCI configs are inline strings in TOML format
 			configBytes, err := util.Merge(
 				[]byte(dst.String()), []byte(src.String()),
 				toml.Marshal, toml.Unmarshal,
@@ -84,9 +184,14 @@ func (t nodeConfigTransformer) mergeContainerdConfig(dst, src reflect.Value) err
 
 func (t nodeConfigTransformer) mergeInlineDocument(dst, src reflect.Value) error {
 	if dst.CanSet() {
-		if dst.Len() <= 0 {
-			// if the destination is empty just use the source data
+		srcDoc, dstDoc := src.Interface().(InlineDocument), dst.Interface().(InlineDocument)
+		if dst.Len() <= 0 || srcDoc.patchDirective() != "" {
+			// if the destination is empty, or the source replaces or deletes
+			// the whole document, just use the source data
 			dst.Set(src)
+		} else if src.Len() > 0 && dstDoc.patchDirective() == PatchDirectiveDelete {
+			// the document was deleted before the source set it again
+			dst.Set(reflect.ValueOf(srcDoc.withPatchDirective(PatchDirectiveReplace)))
 		} else if src.Len() > 0 {
 			mergedMap, err := util.Merge(
 				dst.Interface(), src.Interface(),
@@ -95,6 +200,15 @@ func (t nodeConfigTransformer) mergeInlineDocument(dst, src reflect.Value) error
 			if err != nil {
 				return err
 			}
+			dstMap, err := toMap(dstDoc)
+			if err != nil {
+				return err
+			}
+			srcMap, err := toMap(srcDoc)
+			if err != nil {
+				return err
+			}
+			applyPatchDirectives(mergedMap, dstMap, srcMap)
 			rawMap, err := toInlineDocument(mergedMap)
 			if err != nil {
 				return err
@@ -105,6 +219,18 @@ func (t nodeConfigTransformer) mergeInlineDocument(dst, src reflect.Value) error
 	return nil
 }
 
+func toMap(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func toInlineDocument(m map[string]interface{}) (InlineDocument, error) {
 	var rawMap = make(InlineDocument)
 	for key, value := range m {
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/pelletier/go-toml/v2"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 func toInlineDocumentMust(m map[string]interface{}) InlineDocument {
@@ -36,6 +37,114 @@ func TestMerge(t *testing.T) {
 		patchSpec    NodeConfigSpec
 		expectedSpec NodeConfigSpec
 	}{
+		{
+			name: "merge labels by key",
+			baseSpec: NodeConfigSpec{
+				Kubelet: KubeletOptions{Labels: map[string]string{"role": "worker", "team": "a"}},
+			},
+			patchSpec: NodeConfigSpec{
+				Kubelet: KubeletOptions{Labels: map[string]string{"team": "b", "example.com/zone": "1"}},
+			},
+			expectedSpec: NodeConfigSpec{
+				Kubelet: KubeletOptions{Labels: map[string]string{"role": "worker", "team": "b", "example.com/zone": "1"}},
+			},
+		},
+		{
+			name: "merge taints by key and effect",
+			baseSpec: NodeConfigSpec{
+				Kubelet: KubeletOptions{Taints: []Taint{
+					{Key: "dedicated", Value: "gpu", Effect: TaintEffectNoSchedule},
+					{Key: "dedicated", Value: "gpu", Effect: TaintEffectNoExecute},
+				}},
+			},
+			patchSpec: NodeConfigSpec{
+				Kubelet: KubeletOptions{Taints: []Taint{
+					{Key: "spot", Effect: TaintEffectPreferNoSchedule},
+					{Key: "dedicated", Value: "inference", Effect: TaintEffectNoSchedule},
+				}},
+			},
+			expectedSpec: NodeConfigSpec{
+				Kubelet: KubeletOptions{Taints: []Taint{
+					{Key: "dedicated", Value: "inference", Effect: TaintEffectNoSchedule},
+					{Key: "dedicated", Value: "gpu", Effect: TaintEffectNoExecute},
+					{Key: "spot", Effect: TaintEffectPreferNoSchedule},
+				}},
+			},
+		},
+		{
+			name: "merge hugepages by size",
+			baseSpec: NodeConfigSpec{
+				Instance: InstanceOptions{Hugepages: map[string]intstr.IntOrString{
+					"2Mi": intstr.FromInt32(512),
+					"1Gi": intstr.FromInt32(4),
+				}},
+			},
+			patchSpec: NodeConfigSpec{
+				Instance: InstanceOptions{Hugepages: map[string]intstr.IntOrString{
+					"2Mi": intstr.FromString("10%"),
+					"1Gi": intstr.FromInt32(0),
+				}},
+			},
+			expectedSpec: NodeConfigSpec{
+				Instance: InstanceOptions{Hugepages: map[string]intstr.IntOrString{
+					"2Mi": intstr.FromString("10%"),
+					"1Gi": intstr.FromInt32(0),
+				}},
+			},
+		},
+		{
+			name: "merge containerd runtimes by name",
+			baseSpec: NodeConfigSpec{
+				Containerd: ContainerdOptions{Runtimes: []ContainerdRuntime{
+					{Name: "runsc", RuntimeType: "io.containerd.runsc.v1"},
+					{Name: "kata", RuntimeType: "io.containerd.kata.v2"},
+				}},
+			},
+			patchSpec: NodeConfigSpec{
+				Containerd: ContainerdOptions{Runtimes: []ContainerdRuntime{
+					{Name: "runc-debug", BinaryPath: "/usr/local/bin/runc"},
+					{Name: "runsc", RuntimeType: "io.containerd.runsc.v1", Default: true},
+				}},
+			},
+			expectedSpec: NodeConfigSpec{
+				Containerd: ContainerdOptions{Runtimes: []ContainerdRuntime{
+					{Name: "runsc", RuntimeType: "io.containerd.runsc.v1", Default: true},
+					{Name: "kata", RuntimeType: "io.containerd.kata.v2"},
+					{Name: "runc-debug", BinaryPath: "/usr/local/bin/runc"},
+				}},
+			},
+		},
+		{
+			name: "merge prefetch images by reference",
+			baseSpec: NodeConfigSpec{
+				Containerd: ContainerdOptions{PrefetchImages: []PrefetchImage{
+					{Reference: "public.ecr.aws/eks/aws-load-balancer-controller:v2.7.1"},
+					{Reference: "public.ecr.aws/eks-distro/kubernetes/pause:3.10"},
+				}},
+			},
+			patchSpec: NodeConfigSpec{
+				Containerd: ContainerdOptions{PrefetchImages: []PrefetchImage{
+					{Reference: "public.ecr.aws/eks-distro/kubernetes/pause:3.9"},
+					{Reference: "public.ecr.aws/eks/aws-load-balancer-controller:v2.7.1", Pin: true},
+				}},
+			},
+			expectedSpec: NodeConfigSpec{
+				Containerd: ContainerdOptions{PrefetchImages: []PrefetchImage{
+					{Reference: "public.ecr.aws/eks/aws-load-balancer-controller:v2.7.1", Pin: true},
+					{Reference: "public.ecr.aws/eks-distro/kubernetes/pause:3.10"},
+					{Reference: "public.ecr.aws/eks-distro/kubernetes/pause:3.9"},
+				}},
+			},
+		},
+		{
+			name: "merge taints into empty taints",
+			patchSpec: NodeConfigSpec{
+				Kubelet: KubeletOptions{Taints: []Taint{{Key: "spot", Effect: TaintEffectNoSchedule}}},
+			},
+			expectedSpec: NodeConfigSpec{
+				Kubelet: KubeletOptions{Taints: []Taint{{Key: "spot", Effect: TaintEffectNoSchedule}}},
+			},
+		},
 		{
 			name: "merge with empty string field",
 			baseSpec: NodeConfigSpec{
@@ -80,6 +189,24 @@ func TestMerge(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "merge soci config as toml",
+			baseSpec: NodeConfigSpec{
+				Containerd: ContainerdOptions{
+					SOCI: SOCIOptions{MaxConcurrentDownloads: 8, Config: "[pull_modes.parallel_pull_unpack]\nmax_concurrent_unpacks = 4"},
+				},
+			},
+			patchSpec: NodeConfigSpec{
+				Containerd: ContainerdOptions{
+					SOCI: SOCIOptions{Config: "[pull_modes.parallel_pull_unpack]\nconcurrent_download_chunk_size = '32mb'"},
+				},
+			},
+			expectedSpec: NodeConfigSpec{
+				Containerd: ContainerdOptions{
+					SOCI: SOCIOptions{MaxConcurrentDownloads: 8, Config: "[pull_modes]\n[pull_modes.parallel_pull_unpack]\nconcurrent_download_chunk_size = '32mb'\nmax_concurrent_unpacks = 4\n"},
+				},
+			},
+		},
 		{
 			name: "customer overrides orchestrator defaults",
 			baseSpec: NodeConfigSpec{
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// FieldOrigin identifies the document that last set a field of a NodeConfig.
+type FieldOrigin struct {
+	// Source is the config source URL that the document was read from.
+	Source string
+	// Object is the file, object, parameter, or secret that the document was
+	// read from, when the source holds more than one.
+	Object string
+	// Part is the 1-based index of the MIME part that held the document, or 0
+	// when the document was not part of a MIME multi-part document.
+	Part int
+}
+
+func (o FieldOrigin) String() string {
+	var details []string
+	if len(o.Object) > 0 {
+		details = append(details, o.Object)
+	}
+	if o.Part > 0 {
+		details = append(details, fmt.Sprintf("part %d", o.Part))
+	}
+	source := o.Source
+	if len(source) == 0 {
+		source = "unknown"
+	}
+	if len(details) == 0 {
+		return source
+	}
+	return fmt.Sprintf("%s (%s)", source, strings.Join(details, ", "))
+}
+
+var (
+	kubeletFlagPathPattern = regexp.MustCompile(`^spec\.kubelet\.flags\[(\d+)\]$`)
+	taintPathPattern       = regexp.MustCompile(`^spec\.kubelet\.taints\[(\d+)\](.*)$`)
+)
+
+// RecordOrigin records the origin of every field that is set in the spec,
+// replacing any origin recorded before.
+func (c *NodeConfig) RecordOrigin(origin FieldOrigin) error {
+	fields, err := SpecFields(&c.Spec)
+	if err != nil {
+		return err
+	}
+	c.Origins = make(map[string]FieldOrigin, len(fields))
+	for path := range fields {
+		c.Origins[path] = origin
+	}
+	return nil
+}
+
+// SetOriginSource sets the source of every recorded origin.
+func (c *NodeConfig) SetOriginSource(source string) {
+	for path, origin := range c.Origins {
+		origin.Source = source
+		c.Origins[path] = origin
+	}
+}
+
+// SetOriginObject sets the object of every recorded origin.
+func (c *NodeConfig) SetOriginObject(object string) {
+	for path, origin := range c.Origins {
+		origin.Object = object
+		c.Origins[path] = origin
+	}
+}
+
+// mergeOrigins returns the origins of the fields of dst after src is merged
+// into it. The origins of fields that are no longer set after the merge may
+// remain, so they should be looked up by the fields of the merged spec.
+func mergeOrigins(dst, src *NodeConfig) map[string]FieldOrigin {
+	if len(dst.Origins) == 0 && len(src.Origins) == 0 {
+		return nil
+	}
+	origins := make(map[string]FieldOrigin, len(dst.Origins)+len(src.Origins))
+	for path, origin := range dst.Origins {
+		origins[path] = origin
+	}
+	// kubelet flags are appended rather than replaced, see mergeKubeletFlags.
+	flagOffset := len(dst.Spec.Kubelet.Flags)
+	// taints are merged by key and effect, see mergeTaints.
+	_, taintIndexes := mergeTaints(dst.Spec.Kubelet.Taints, src.Spec.Kubelet.Taints)
+	for path, origin := range src.Origins {
+		if match := kubeletFlagPathPattern.FindStringSubmatch(path); match != nil {
+			index, _ := strconv.Atoi(match[1])
+			path = field.NewPath("spec", "kubelet", "flags").Index(index + flagOffset).String()
+		} else if match := taintPathPattern.FindStringSubmatch(path); match != nil {
+			index, _ := strconv.Atoi(match[1])
+			if index < len(taintIndexes) {
+				path = field.NewPath("spec", "kubelet", "taints").Index(taintIndexes[index]).String() + match[2]
+			}
+		}
+		origins[path] = origin
+	}
+	return origins
+}
+
+// SpecFields returns the value of every leaf field that is set in the spec,
+// keyed by its path, e.g. `spec.kubelet.config.maxPods`.
+func SpecFields(spec *NodeConfigSpec) (map[string]interface{}, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	fields := make(map[string]interface{})
+	collectFields(field.NewPath("spec"), value, fields)
+	return fields, nil
+}
+
+func collectFields(path *field.Path, value interface{}, fields map[string]interface{}) {
+	switch v := value.(type) {
+	case nil:
+	case map[string]interface{}:
+		for key, child := range v {
+			// keys that are not identifiers, like label keys, are quoted.
+			if strings.ContainsAny(key, "./ ") {
+				collectFields(path.Key(key), child, fields)
+			} else {
+				collectFields(path.Child(key), child, fields)
+			}
+		}
+	case []interface{}:
+		for i, child := range v {
+			collectFields(path.Index(i), child, fields)
+		}
+	case string:
+		if len(v) > 0 {
+			fields[path.String()] = v
+		}
+	default:
+		fields[path.String()] = v
+	}
+}
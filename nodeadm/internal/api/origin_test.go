@@ -0,0 +1,88 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestMergeNodeConfigsOrigins(t *testing.T) {
+	first := &NodeConfig{
+		Spec: NodeConfigSpec{
+			Cluster: ClusterDetails{Name: "my-cluster"},
+			Instance: InstanceOptions{
+				Network: NetworkOptions{Nameservers: []string{"10.0.0.2", "10.0.0.3"}},
+			},
+			Kubelet: KubeletOptions{
+				Config: InlineDocument{
+					"maxPods": runtime.RawExtension{Raw: []byte("110")},
+					"labels":  runtime.RawExtension{Raw: []byte(`{"example.com/role":"worker"}`)},
+				},
+				Flags: []string{"--v=2"},
+				Taints: []Taint{
+					{Key: "dedicated", Value: "gpu", Effect: TaintEffectNoSchedule},
+					{Key: "spot", Effect: TaintEffectNoSchedule},
+				},
+			},
+		},
+	}
+	second := &NodeConfig{
+		Spec: NodeConfigSpec{
+			Instance: InstanceOptions{
+				Network: NetworkOptions{Nameservers: []string{"10.0.0.4"}},
+			},
+			Kubelet: KubeletOptions{
+				Config: InlineDocument{
+					"maxPods": runtime.RawExtension{Raw: []byte("58")},
+				},
+				Flags:  []string{"--v=5"},
+				Taints: []Taint{{Key: "spot", Value: "true", Effect: TaintEffectNoSchedule}},
+			},
+		},
+	}
+	firstOrigin := FieldOrigin{Source: "imds://user-data", Part: 1}
+	secondOrigin := FieldOrigin{Source: "file:///etc/eks/nodeadm.d/", Object: "10-kubelet.yaml"}
+	assert.NoError(t, first.RecordOrigin(FieldOrigin{Part: 1}))
+	first.SetOriginSource(firstOrigin.Source)
+	assert.NoError(t, second.RecordOrigin(secondOrigin))
+
+	merged, err := MergeNodeConfigs([]*NodeConfig{first, second})
+	if !assert.NoError(t, err) {
+		return
+	}
+	fields, err := SpecFields(&merged.Spec)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, map[string]interface{}{
+		"spec.cluster.name":                            "my-cluster",
+		"spec.instance.network.nameservers[0]":         "10.0.0.4",
+		"spec.kubelet.config.labels[example.com/role]": "worker",
+		"spec.kubelet.config.maxPods":                  float64(58),
+		"spec.kubelet.flags[0]":                        "--v=2",
+		"spec.kubelet.flags[1]":                        "--v=5",
+		"spec.kubelet.taints[0].key":                   "dedicated",
+		"spec.kubelet.taints[0].value":                 "gpu",
+		"spec.kubelet.taints[0].effect":                "NoSchedule",
+		"spec.kubelet.taints[1].key":                   "spot",
+		"spec.kubelet.taints[1].value":                 "true",
+		"spec.kubelet.taints[1].effect":                "NoSchedule",
+	}, fields)
+	expectedOrigins := map[string]FieldOrigin{
+		"spec.cluster.name":                            firstOrigin,
+		"spec.instance.network.nameservers[0]":         secondOrigin,
+		"spec.kubelet.config.labels[example.com/role]": firstOrigin,
+		"spec.kubelet.config.maxPods":                  secondOrigin,
+		"spec.kubelet.flags[0]":                        firstOrigin,
+		"spec.kubelet.flags[1]":                        secondOrigin,
+		"spec.kubelet.taints[0].key":                   firstOrigin,
+		"spec.kubelet.taints[1].value":                 secondOrigin,
+	}
+	for path, origin := range expectedOrigins {
+		assert.Equal(t, origin, merged.Origins[path], path)
+	}
+	assert.Equal(t, "imds://user-data (part 1)", firstOrigin.String())
+	assert.Equal(t, "file:///etc/eks/nodeadm.d/ (10-kubelet.yaml)", secondOrigin.String())
+	assert.Equal(t, "unknown", FieldOrigin{}.String())
+}
@@ -0,0 +1,214 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const (
+	// PatchDirectiveKey is the key of a map within an inline document that
+	// changes how the map is merged, like in a strategic merge patch.
+	PatchDirectiveKey = "$patch"
+	// PatchDirectiveReplace replaces the map from earlier configs, instead of
+	// merging into it.
+	PatchDirectiveReplace = "replace"
+	// PatchDirectiveDelete removes the map from earlier configs.
+	PatchDirectiveDelete = "delete"
+
+	// KubeletFlagDeletePrefix marks an entry of the kubelet flags that removes
+	// every earlier flag with the given name, e.g. `$delete=--node-labels`.
+	KubeletFlagDeletePrefix = "$delete="
+)
+
+// applyPatchDirectives replaces the maps of the merged document with those of
+// src that have a patch directive. The directives are kept, so that they also
+// apply when the merged document is merged into another.
+func applyPatchDirectives(merged, dst, src map[string]interface{}) {
+	for key, srcValue := range src {
+		srcMap, ok := srcValue.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		dstMap, _ := dst[key].(map[string]interface{})
+		if _, ok := srcMap[PatchDirectiveKey]; ok {
+			merged[key] = srcMap
+		} else if dstMap[PatchDirectiveKey] == PatchDirectiveDelete {
+			// the map was deleted before src set it again, which is the same
+			// as src replacing it.
+			merged[key] = withPatchDirective(srcMap, PatchDirectiveReplace)
+		} else if mergedMap, ok := merged[key].(map[string]interface{}); ok && dstMap != nil {
+			applyPatchDirectives(mergedMap, dstMap, srcMap)
+		}
+	}
+}
+
+func withPatchDirective(m map[string]interface{}, directive string) map[string]interface{} {
+	patched := make(map[string]interface{}, len(m)+1)
+	for key, value := range m {
+		patched[key] = value
+	}
+	patched[PatchDirectiveKey] = directive
+	return patched
+}
+
+// patchDirective returns the patch directive of the document itself, which
+// replaces or deletes the whole document.
+func (d InlineDocument) patchDirective() string {
+	raw, ok := d[PatchDirectiveKey]
+	if !ok {
+		return ""
+	}
+	var directive string
+	_ = json.Unmarshal(raw.Raw, &directive)
+	return directive
+}
+
+func (d InlineDocument) withPatchDirective(directive string) InlineDocument {
+	patched := make(InlineDocument, len(d)+1)
+	for key, value := range d {
+		patched[key] = value
+	}
+	raw, _ := json.Marshal(directive)
+	patched[PatchDirectiveKey] = runtime.RawExtension{Raw: raw}
+	return patched
+}
+
+// RemovePatchDirectives applies the patch directives that remain after
+// merging, and removes them from the config. This should be called once all
+// configs have been merged. The maps of the kubelet config that had a
+// directive are recorded in the status, as the kubelet config that nodeadm
+// generates is only merged with spec.kubelet.config later on.
+func (c *NodeConfig) RemovePatchDirectives() error {
+	var err error
+	if c.Spec.Kubelet.Config, c.Status.KubeletConfigOverrides, err = removeInlineDocumentPatchDirectives(c.Spec.Kubelet.Config, field.NewPath("spec", "kubelet", "config")); err != nil {
+		return err
+	}
+	if c.Spec.Containerd.BaseRuntimeSpec, _, err = removeInlineDocumentPatchDirectives(c.Spec.Containerd.BaseRuntimeSpec, field.NewPath("spec", "containerd", "baseRuntimeSpec")); err != nil {
+		return err
+	}
+	c.removeKubeletFlagDirectives()
+	return nil
+}
+
+// removeInlineDocumentPatchDirectives returns the document without its patch
+// directives, along with the paths of the maps within it that had one.
+func removeInlineDocumentPatchDirectives(doc InlineDocument, path *field.Path) (InlineDocument, [][]string, error) {
+	if len(doc) == 0 {
+		return doc, nil, nil
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	// documents without directives are left as they are.
+	if !bytes.Contains(data, []byte(`"`+PatchDirectiveKey+`"`)) {
+		return doc, nil, nil
+	}
+	m, err := toMap(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	var overrides [][]string
+	keep, err := removePatchDirectives(m, path, nil, &overrides)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !keep {
+		return nil, overrides, nil
+	}
+	doc, err = toInlineDocument(m)
+	return doc, overrides, err
+}
+
+// removePatchDirectives removes the directives from the map and the maps
+// within it, and returns false if the map itself should be deleted. The keys
+// of the maps within the document that had a directive are added to overrides.
+func removePatchDirectives(m map[string]interface{}, path *field.Path, keys []string, overrides *[][]string) (bool, error) {
+	if directive, ok := m[PatchDirectiveKey]; ok {
+		switch directive {
+		case PatchDirectiveReplace, PatchDirectiveDelete:
+			if len(keys) > 0 {
+				*overrides = append(*overrides, keys)
+			}
+		default:
+			return false, field.NotSupported(path.Child(PatchDirectiveKey), directive, []string{PatchDirectiveReplace, PatchDirectiveDelete})
+		}
+		if directive == PatchDirectiveDelete {
+			return false, nil
+		}
+		delete(m, PatchDirectiveKey)
+	}
+	for _, key := range slices.Sorted(maps.Keys(m)) {
+		child, ok := m[key].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		keep, err := removePatchDirectives(child, path.Child(key), append(slices.Clip(keys), key), overrides)
+		if err != nil {
+			return false, err
+		}
+		if !keep {
+			delete(m, key)
+		}
+	}
+	return true, nil
+}
+
+// removeKubeletFlagDirectives removes the flags that precede a matching
+// delete directive, along with the directives themselves.
+func (c *NodeConfig) removeKubeletFlagDirectives() {
+	flagsPath := field.NewPath("spec", "kubelet", "flags")
+	var flags KubeletFlags
+	// the original index of each of the remaining flags, to move their origins.
+	var indexes []int
+	for i, flag := range c.Spec.Kubelet.Flags {
+		name, ok := strings.CutPrefix(flag, KubeletFlagDeletePrefix)
+		if !ok {
+			flags = append(flags, flag)
+			indexes = append(indexes, i)
+			continue
+		}
+		name = kubeletFlagName(name)
+		var remainingFlags KubeletFlags
+		var remainingIndexes []int
+		for j, existing := range flags {
+			isValue := isKubeletFlagValue([]string(flags), j)
+			// the value of a flag written without '=' is removed with it.
+			if (!isValue && kubeletFlagName(existing) == name) || (isValue && kubeletFlagName(flags[j-1]) == name) {
+				continue
+			}
+			remainingFlags = append(remainingFlags, existing)
+			remainingIndexes = append(remainingIndexes, indexes[j])
+		}
+		flags, indexes = remainingFlags, remainingIndexes
+	}
+	if len(flags) == len(c.Spec.Kubelet.Flags) {
+		return
+	}
+	if c.Origins != nil {
+		for newIndex, oldIndex := range indexes {
+			c.Origins[flagsPath.Index(newIndex).String()] = c.Origins[flagsPath.Index(oldIndex).String()]
+		}
+	}
+	c.Spec.Kubelet.Flags = flags
+}
+
+// isKubeletFlagValue returns true if the flag at index i is the value of the
+// flag before it, which is written without '=', e.g. `foo=bar` of
+// ["--node-labels", "foo=bar"]. The flags are joined with spaces, so kubelet
+// parses this the same as `--node-labels=foo=bar`.
+func isKubeletFlagValue(flags []string, i int) bool {
+	return i > 0 && !strings.HasPrefix(flags[i], "-") && strings.HasPrefix(flags[i-1], "-") && !strings.Contains(flags[i-1], "=")
+}
+
+// kubeletFlagName returns the name of a flag such as `--node-labels=foo=bar`.
+func kubeletFlagName(flag string) string {
+	name, _, _ := strings.Cut(strings.TrimLeft(flag, "-"), "=")
+	return name
+}
@@ -0,0 +1,188 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func kubeletConfigSpec(m map[string]interface{}) NodeConfigSpec {
+	return NodeConfigSpec{Kubelet: KubeletOptions{Config: toInlineDocumentMust(m)}}
+}
+
+func TestPatchDirectives(t *testing.T) {
+	var tests = []struct {
+		name           string
+		specs          []NodeConfigSpec
+		expectedConfig map[string]interface{}
+		expectedErr    bool
+	}{
+		{
+			name: "replace map",
+			specs: []NodeConfigSpec{
+				kubeletConfigSpec(map[string]interface{}{
+					"evictionHard": map[string]interface{}{"memory.available": "100Mi", "nodefs.available": "10%"},
+				}),
+				kubeletConfigSpec(map[string]interface{}{
+					"evictionHard": map[string]interface{}{"$patch": "replace", "memory.available": "200Mi"},
+				}),
+			},
+			expectedConfig: map[string]interface{}{
+				"evictionHard": map[string]interface{}{"memory.available": "200Mi"},
+			},
+		},
+		{
+			name: "delete map",
+			specs: []NodeConfigSpec{
+				kubeletConfigSpec(map[string]interface{}{
+					"evictionHard": map[string]interface{}{"memory.available": "100Mi"},
+					"maxPods":      float64(110),
+				}),
+				kubeletConfigSpec(map[string]interface{}{
+					"evictionHard": map[string]interface{}{"$patch": "delete"},
+				}),
+			},
+			expectedConfig: map[string]interface{}{
+				"maxPods": float64(110),
+			},
+		},
+		{
+			name: "set map after delete",
+			specs: []NodeConfigSpec{
+				kubeletConfigSpec(map[string]interface{}{
+					"evictionHard": map[string]interface{}{"memory.available": "100Mi"},
+				}),
+				kubeletConfigSpec(map[string]interface{}{
+					"evictionHard": map[string]interface{}{"$patch": "delete"},
+				}),
+				kubeletConfigSpec(map[string]interface{}{
+					"evictionHard": map[string]interface{}{"nodefs.available": "10%"},
+				}),
+			},
+			expectedConfig: map[string]interface{}{
+				"evictionHard": map[string]interface{}{"nodefs.available": "10%"},
+			},
+		},
+		{
+			name: "replace whole document",
+			specs: []NodeConfigSpec{
+				kubeletConfigSpec(map[string]interface{}{
+					"maxPods":     float64(110),
+					"podsPerCore": float64(10),
+				}),
+				kubeletConfigSpec(map[string]interface{}{
+					"$patch":  "replace",
+					"maxPods": float64(58),
+				}),
+			},
+			expectedConfig: map[string]interface{}{
+				"maxPods": float64(58),
+			},
+		},
+		{
+			name: "unsupported directive",
+			specs: []NodeConfigSpec{
+				kubeletConfigSpec(map[string]interface{}{
+					"evictionHard": map[string]interface{}{"$patch": "merge"},
+				}),
+			},
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var configs []*NodeConfig
+			for _, spec := range test.specs {
+				configs = append(configs, &NodeConfig{Spec: *spec.DeepCopy()})
+			}
+			merged, err := MergeNodeConfigs(configs)
+			if !assert.NoError(t, err) {
+				return
+			}
+			err = merged.RemovePatchDirectives()
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, toInlineDocumentMust(test.expectedConfig), merged.Spec.Kubelet.Config)
+			}
+		})
+	}
+
+	t.Run("nested merges match a single merge", func(t *testing.T) {
+		base := kubeletConfigSpec(map[string]interface{}{
+			"evictionHard": map[string]interface{}{"memory.available": "100Mi"},
+		})
+		deleted := kubeletConfigSpec(map[string]interface{}{
+			"evictionHard": map[string]interface{}{"$patch": "delete"},
+		})
+		set := kubeletConfigSpec(map[string]interface{}{
+			"evictionHard": map[string]interface{}{"nodefs.available": "10%"},
+		})
+		// e.g. the last two are parts of the same MIME multi-part document.
+		later, err := MergeNodeConfigs([]*NodeConfig{{Spec: *deleted.DeepCopy()}, {Spec: *set.DeepCopy()}})
+		if !assert.NoError(t, err) {
+			return
+		}
+		merged, err := MergeNodeConfigs([]*NodeConfig{{Spec: *base.DeepCopy()}, later})
+		if assert.NoError(t, err) && assert.NoError(t, merged.RemovePatchDirectives()) {
+			assert.Equal(t, toInlineDocumentMust(map[string]interface{}{
+				"evictionHard": map[string]interface{}{"nodefs.available": "10%"},
+			}), merged.Spec.Kubelet.Config)
+		}
+	})
+}
+
+func TestKubeletConfigOverrides(t *testing.T) {
+	config := &NodeConfig{Spec: kubeletConfigSpec(map[string]interface{}{
+		"evictionHard": map[string]interface{}{"$patch": "replace", "memory.available": "200Mi"},
+		"evictionSoft": map[string]interface{}{"$patch": "delete"},
+		"featureGates": map[string]interface{}{"Foo": true},
+	})}
+	if assert.NoError(t, config.RemovePatchDirectives()) {
+		assert.Equal(t, [][]string{{"evictionHard"}, {"evictionSoft"}}, config.Status.KubeletConfigOverrides)
+	}
+
+	// a directive of the whole document only applies to earlier configs.
+	config = &NodeConfig{Spec: kubeletConfigSpec(map[string]interface{}{
+		"$patch":  "replace",
+		"maxPods": float64(110),
+	})}
+	if assert.NoError(t, config.RemovePatchDirectives()) {
+		assert.Empty(t, config.Status.KubeletConfigOverrides)
+	}
+}
+
+func TestKubeletFlagDeleteDirective(t *testing.T) {
+	base := &NodeConfig{Spec: NodeConfigSpec{Kubelet: KubeletOptions{
+		Flags: []string{"--v=2", "--node-labels=foo=bar", "--register-with-taints=a=b:NoSchedule"},
+	}}}
+	patch := &NodeConfig{Spec: NodeConfigSpec{Kubelet: KubeletOptions{
+		Flags: []string{"$delete=--node-labels", "$delete=register-with-taints", "--node-labels=foo=baz"},
+	}}}
+	assert.NoError(t, base.RecordOrigin(FieldOrigin{Source: "base"}))
+	assert.NoError(t, patch.RecordOrigin(FieldOrigin{Source: "patch"}))
+
+	merged, err := MergeNodeConfigs([]*NodeConfig{base, patch})
+	if assert.NoError(t, err) && assert.NoError(t, merged.RemovePatchDirectives()) {
+		assert.Equal(t, KubeletFlags{"--v=2", "--node-labels=foo=baz"}, merged.Spec.Kubelet.Flags)
+		assert.Equal(t, "base", merged.Origins["spec.kubelet.flags[0]"].Source)
+		assert.Equal(t, "patch", merged.Origins["spec.kubelet.flags[1]"].Source)
+	}
+}
+
+func TestKubeletFlagDeleteDirectiveSplitValue(t *testing.T) {
+	base := &NodeConfig{Spec: NodeConfigSpec{Kubelet: KubeletOptions{
+		Flags: []string{"--node-labels", "foo=bar", "--v", "2"},
+	}}}
+	patch := &NodeConfig{Spec: NodeConfigSpec{Kubelet: KubeletOptions{
+		Flags: []string{"$delete=--node-labels"},
+	}}}
+
+	merged, err := MergeNodeConfigs([]*NodeConfig{base, patch})
+	if assert.NoError(t, err) && assert.NoError(t, merged.RemovePatchDirectives()) {
+		assert.Equal(t, KubeletFlags{"--v", "2"}, merged.Spec.Kubelet.Flags)
+	}
+}
@@ -3,8 +3,10 @@
 package api
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 const KindNodeConfig = "NodeConfig"
@@ -18,6 +20,10 @@ type NodeConfig struct {
 	Spec              NodeConfigSpec `json:"spec,omitempty"`
 	// +k8s:conversion-gen=false
 	Status NodeConfigStatus `json:"status,omitempty"`
+	// Origins is the origin of each field of the spec that is set, keyed by
+	// the path of the field.
+	// +k8s:conversion-gen=false
+	Origins map[string]FieldOrigin `json:"-"`
 }
 
 // +kubebuilder:object:root=true
@@ -41,6 +47,32 @@ type NodeConfigStatus struct {
 	Instance       InstanceDetails `json:"instance,omitempty"`
 	Defaults       DefaultOptions  `json:"default,omitempty"`
 	KubeletVersion string          `json:"kubeletVersion,omitempty"`
+	Kernel         KernelStatus    `json:"kernel,omitempty"`
+	SOCI           SOCIStatus      `json:"soci,omitzero"`
+	// KubeletConfigOverrides are the paths of the maps within
+	// spec.kubelet.config that had a patch directive. These maps replace or
+	// remove those of the kubelet config that nodeadm generates too.
+	KubeletConfigOverrides [][]string `json:"kubeletConfigOverrides,omitempty"`
+}
+
+// KernelStatus records the changes to the kernel command-line that have
+// already been made, which must persist across reboots.
+type KernelStatus struct {
+	// RebootedForArgs are the kernel arguments that the instance was rebooted
+	// to apply. The instance is only rebooted once.
+	RebootedForArgs []string `json:"rebootedForArgs,omitempty"`
+}
+
+// SOCIStatus records whether the SOCI snapshotter is used, and the settings
+// that were resolved for it from the NodeConfig and its defaults.
+type SOCIStatus struct {
+	Enabled                        bool              `json:"enabled,omitempty"`
+	MinMemory                      resource.Quantity `json:"minMemory,omitzero"`
+	MinCPU                         resource.Quantity `json:"minCPU,omitzero"`
+	MaxConcurrentDownloads         int32             `json:"maxConcurrentDownloads,omitempty"`
+	MaxConcurrentDownloadsPerImage int32             `json:"maxConcurrentDownloadsPerImage,omitempty"`
+	MaxConcurrentUnpacks           int32             `json:"maxConcurrentUnpacks,omitempty"`
+	MaxConcurrentUnpacksPerImage   int32             `json:"maxConcurrentUnpacksPerImage,omitempty"`
 }
 
 type InstanceDetails struct {
@@ -80,18 +112,130 @@ type KubeletOptions struct {
 	// over the result of this expression. If the expression is successfully evaluated,
 	// kubeReserved will always be calculated on its result.
 	MaxPodsExpression string `json:"maxPodsExpression,omitempty"`
+	// MaxPodsStrategy configures the max pods calculation, whose result is
+	// the max_pods variable of MaxPodsExpression.
+	MaxPodsStrategy MaxPodsStrategy `json:"maxPodsStrategy,omitempty"`
+	// Labels are added to the node when it registers, and take precedence
+	// over the labels set by nodeadm.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Taints are added to the node when it registers.
+	Taints []Taint `json:"taints,omitempty"`
+	// AutoLabels enables labels that nodeadm detects on the instance.
+	AutoLabels []AutoLabel `json:"autoLabels,omitempty"`
+	// KubeReservedExpressions replace the default kubeReserved of nodeadm.
+	KubeReservedExpressions ReservedResourceExpressions `json:"kubeReservedExpressions,omitempty"`
+	// SystemReservedExpressions set the systemReserved of the kubelet.
+	SystemReservedExpressions ReservedResourceExpressions `json:"systemReservedExpressions,omitempty"`
+}
+
+// MaxPodsStrategy selects the variants of the VPC CNI max pods calculation.
+type MaxPodsStrategy struct {
+	PrefixDelegation bool `json:"prefixDelegation,omitempty"`
+	CustomNetworking bool `json:"customNetworking,omitempty"`
+	CapByVCPU        bool `json:"capByVCPU,omitempty"`
+}
+
+// ReservedResourceExpressions are CEL expressions for the millicores of CPU,
+// and the mebibytes of memory and ephemeral storage to reserve.
+type ReservedResourceExpressions struct {
+	CPU              string `json:"cpu,omitempty"`
+	Memory           string `json:"memory,omitempty"`
+	EphemeralStorage string `json:"ephemeralStorage,omitempty"`
 }
 
+type AutoLabel string
+
+const (
+	AutoLabelNeuron        AutoLabel = "Neuron"
+	AutoLabelEFA           AutoLabel = "EFA"
+	AutoLabelInstanceStore AutoLabel = "InstanceStore"
+	AutoLabelCPU           AutoLabel = "CPU"
+	AutoLabelOutpost       AutoLabel = "Outpost"
+	AutoLabelInstanceType  AutoLabel = "InstanceType"
+)
+
+type Taint struct {
+	Key    string      `json:"key"`
+	Value  string      `json:"value,omitempty"`
+	Effect TaintEffect `json:"effect"`
+}
+
+type TaintEffect string
+
+const (
+	TaintEffectNoSchedule       TaintEffect = "NoSchedule"
+	TaintEffectPreferNoSchedule TaintEffect = "PreferNoSchedule"
+	TaintEffectNoExecute        TaintEffect = "NoExecute"
+)
+
 // InlineDocument is an alias to a dynamically typed map. This allows using
 // embedded YAML and JSON types within the parent yaml config.
 type InlineDocument map[string]runtime.RawExtension
 
 type ContainerdConfig string
 type ContainerdOptions struct {
-	Config          ContainerdConfig `json:"config,omitempty"`
-	BaseRuntimeSpec InlineDocument   `json:"baseRuntimeSpec,omitempty"`
+	Config                ContainerdConfig           `json:"config,omitempty"`
+	BaseRuntimeSpec       InlineDocument             `json:"baseRuntimeSpec,omitempty"`
+	Registries            map[string]RegistryOptions `json:"registries,omitempty"`
+	Runtimes              []ContainerdRuntime        `json:"runtimes,omitempty"`
+	PrefetchImages        []PrefetchImage            `json:"prefetchImages,omitempty"`
+	PrefetchImagesTimeout *metav1.Duration           `json:"prefetchImagesTimeout,omitempty"`
+	SandboxImage          string                     `json:"sandboxImage,omitempty"`
+	ImageArchives         []string                   `json:"imageArchives,omitempty"`
+	SOCI                  SOCIOptions                `json:"soci,omitempty"`
+}
+
+type SOCIConfig string
+type SOCIOptions struct {
+	MinMemory                      *resource.Quantity `json:"minMemory,omitempty"`
+	MinCPU                         *resource.Quantity `json:"minCPU,omitempty"`
+	MaxConcurrentDownloads         int32              `json:"maxConcurrentDownloads,omitempty"`
+	MaxConcurrentDownloadsPerImage int32              `json:"maxConcurrentDownloadsPerImage,omitempty"`
+	MaxConcurrentUnpacks           int32              `json:"maxConcurrentUnpacks,omitempty"`
+	MaxConcurrentUnpacksPerImage   int32              `json:"maxConcurrentUnpacksPerImage,omitempty"`
+	Config                         SOCIConfig         `json:"config,omitempty"`
+}
+
+type ContainerdRuntime struct {
+	Name        string         `json:"name"`
+	BinaryPath  string         `json:"binaryPath,omitempty"`
+	RuntimeType string         `json:"runtimeType,omitempty"`
+	Options     InlineDocument `json:"options,omitempty"`
+	Default     bool           `json:"default,omitempty"`
+}
+
+type PrefetchImage struct {
+	Reference string `json:"reference"`
+	Pin       bool   `json:"pin,omitempty"`
+}
+
+// DefaultRegistryHost is the key of ContainerdOptions.Registries that applies
+// to every registry without its own entry.
+const DefaultRegistryHost = "_default"
+
+type RegistryOptions struct {
+	Server     string           `json:"server,omitempty"`
+	Mirrors    []RegistryMirror `json:"mirrors,omitempty"`
+	CA         string           `json:"ca,omitempty"`
+	SkipVerify bool             `json:"skipVerify,omitempty"`
+}
+
+type RegistryMirror struct {
+	URL          string               `json:"url"`
+	Capabilities []RegistryCapability `json:"capabilities,omitempty"`
+	CA           string               `json:"ca,omitempty"`
+	SkipVerify   bool                 `json:"skipVerify,omitempty"`
+	OverridePath bool                 `json:"overridePath,omitempty"`
 }
 
+type RegistryCapability string
+
+const (
+	RegistryCapabilityPull    RegistryCapability = "pull"
+	RegistryCapabilityResolve RegistryCapability = "resolve"
+	RegistryCapabilityPush    RegistryCapability = "push"
+)
+
 type IPFamily string
 
 const (
@@ -103,6 +247,16 @@ type InstanceOptions struct {
 	LocalStorage LocalStorageOptions `json:"localStorage,omitempty"`
 	Environment  EnvironmentOptions  `json:"environment,omitempty"`
 	Network      NetworkOptions      `json:"network,omitempty"`
+	Sysctls      map[string]string   `json:"sysctls,omitempty"`
+	Kernel       KernelOptions       `json:"kernel,omitempty"`
+	// Hugepages are keyed by page size, with a number of pages or a
+	// percentage of online memory.
+	Hugepages map[string]intstr.IntOrString `json:"hugepages,omitempty"`
+}
+
+type KernelOptions struct {
+	Modules []string `json:"modules,omitempty"`
+	Args    []string `json:"args,omitempty"`
 }
 
 type NetworkOptions struct {
@@ -0,0 +1,19 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestSOCIStatusOmitsUnresolvedSettings(t *testing.T) {
+	status, err := json.Marshal(NodeConfigStatus{})
+	assert.NoError(t, err)
+	assert.NotContains(t, string(status), "soci")
+
+	status, err = json.Marshal(SOCIStatus{Enabled: true, MinCPU: resource.MustParse("4"), MaxConcurrentDownloads: -1})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"enabled":true,"minCPU":"4","maxConcurrentDownloads":-1}`, string(status))
+}
@@ -1,24 +1,424 @@
 package api
 
-import "fmt"
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"maps"
+	"net"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
 
-func ValidateNodeConfig(cfg *NodeConfig) error {
-	if cfg.Spec.Cluster.Name == "" {
-		return fmt.Errorf("Name is missing in cluster configuration")
+	"github.com/pelletier/go-toml/v2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	kubeletapis "k8s.io/kubelet/pkg/apis"
+)
+
+var (
+	supportedLocalStorageStrategies = []LocalStorageStrategy{LocalStorageRAID0, LocalStorageRAID10, LocalStorageMount}
+	supportedDisabledMounts         = []DisabledMount{DisabledMountContainerd, DisabledMountPodLogs, DisabledMountSOCI}
+	supportedTaintEffects           = []TaintEffect{TaintEffectNoSchedule, TaintEffectPreferNoSchedule, TaintEffectNoExecute}
+	supportedAutoLabels             = []AutoLabel{AutoLabelNeuron, AutoLabelEFA, AutoLabelInstanceStore, AutoLabelCPU, AutoLabelOutpost, AutoLabelInstanceType}
+	supportedRegistryCapabilities   = []RegistryCapability{RegistryCapabilityPull, RegistryCapabilityResolve, RegistryCapabilityPush}
+
+	// the names of kernel parameters, in which either dots or slashes may
+	// separate the parts, see sysctl.d(5).
+	sysctlNamePattern = regexp.MustCompile(`^([a-z0-9]([-_a-z0-9]*[a-z0-9])?[./])*[a-z0-9]([-_a-z0-9]*[a-z0-9])?$`)
+	// modprobe treats dashes and underscores in module names the same.
+	kernelModuleNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+	runtimeTypePattern      = regexp.MustCompile(`^[A-Za-z0-9_-]+(\.[A-Za-z0-9_-]+)+$`)
+	// a registry host name with an optional port, which is also the name of
+	// its directory beneath certs.d.
+	registryHostPattern = regexp.MustCompile(`^([A-Za-z0-9]([-A-Za-z0-9]*[A-Za-z0-9])?\.)*[A-Za-z0-9]([-A-Za-z0-9]*[A-Za-z0-9])?(:[0-9]{1,5})?$`)
+	// an image reference with an optional registry host, tag and digest, see:
+	// https://github.com/distribution/reference/blob/main/reference.go
+	imageReferencePattern = regexp.MustCompile(`^(([A-Za-z0-9]([-A-Za-z0-9]*[A-Za-z0-9])?\.)*[A-Za-z0-9]([-A-Za-z0-9]*[A-Za-z0-9])?(:[0-9]{1,5})?/)?[a-z0-9]+(([._]|__|-+)[a-z0-9]+)*(/[a-z0-9]+(([._]|__|-+)[a-z0-9]+)*)*(:[A-Za-z0-9_][A-Za-z0-9_.-]{0,127})?(@[A-Za-z][A-Za-z0-9]*([-_+.][A-Za-z][A-Za-z0-9]*)*:[0-9a-fA-F]{32,})?$`)
+)
+
+// ValidateNodeConfig checks the NodeConfig for problems, returning every one
+// that is found along with the path of the offending field.
+func ValidateNodeConfig(cfg *NodeConfig) field.ErrorList {
+	specPath := field.NewPath("spec")
+	var errs field.ErrorList
+	errs = append(errs, validateClusterDetails(&cfg.Spec.Cluster, specPath.Child("cluster"))...)
+	errs = append(errs, validateContainerdOptions(&cfg.Spec.Containerd, specPath.Child("containerd"))...)
+	errs = append(errs, validateInstanceOptions(&cfg.Spec.Instance, specPath.Child("instance"))...)
+	errs = append(errs, validateKubeletOptions(&cfg.Spec.Kubelet, specPath.Child("kubelet"))...)
+	return errs
+}
+
+func validateClusterDetails(cluster *ClusterDetails, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if cluster.Name == "" {
+		errs = append(errs, field.Required(fldPath.Child("name"), "cluster name must be provided"))
+	}
+	if cluster.APIServerEndpoint == "" {
+		errs = append(errs, field.Required(fldPath.Child("apiServerEndpoint"), "apiserver endpoint must be provided"))
+	} else if endpoint, err := url.Parse(cluster.APIServerEndpoint); err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+		errs = append(errs, field.Invalid(fldPath.Child("apiServerEndpoint"), cluster.APIServerEndpoint, "must be an absolute URL"))
+	}
+	if cluster.CertificateAuthority == nil {
+		errs = append(errs, field.Required(fldPath.Child("certificateAuthority"), "certificate authority must be provided"))
+	}
+	if cluster.CIDR == "" {
+		errs = append(errs, field.Required(fldPath.Child("cidr"), "service CIDR must be provided"))
+	} else if _, _, err := net.ParseCIDR(cluster.CIDR); err != nil {
+		errs = append(errs, field.Invalid(fldPath.Child("cidr"), cluster.CIDR, "must be a valid CIDR"))
+	}
+	if enabled := cluster.EnableOutpost; enabled != nil && *enabled {
+		if cluster.ID == "" {
+			errs = append(errs, field.Required(fldPath.Child("id"), "cluster ID must be provided for outposts"))
+		}
+	}
+	return errs
+}
+
+func validateContainerdOptions(containerd *ContainerdOptions, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if len(containerd.Config) > 0 {
+		var config map[string]any
+		if err := toml.Unmarshal([]byte(containerd.Config), &config); err != nil {
+			errs = append(errs, field.Invalid(fldPath.Child("config"), field.OmitValueType{}, err.Error()))
+		}
+	}
+	registriesPath := fldPath.Child("registries")
+	for _, host := range slices.Sorted(maps.Keys(containerd.Registries)) {
+		registry := containerd.Registries[host]
+		registryPath := registriesPath.Key(host)
+		if host != DefaultRegistryHost && !registryHostPattern.MatchString(host) {
+			errs = append(errs, field.Invalid(registryPath, host, "must be a registry host with an optional port, or "+DefaultRegistryHost))
+		}
+		if registry.Server != "" {
+			errs = append(errs, validateRegistryURL(registry.Server, registryPath.Child("server"))...)
+		}
+		if registry.CA != "" {
+			errs = append(errs, validateCABundle(registry.CA, registryPath.Child("ca"))...)
+		}
+		mirrorURLs := map[string]bool{}
+		for i, mirror := range registry.Mirrors {
+			mirrorPath := registryPath.Child("mirrors").Index(i)
+			if mirror.URL == "" {
+				errs = append(errs, field.Required(mirrorPath.Child("url"), "mirror URL must be provided"))
+			} else if mirrorURLs[mirror.URL] {
+				errs = append(errs, field.Duplicate(mirrorPath.Child("url"), mirror.URL))
+			} else {
+				errs = append(errs, validateRegistryURL(mirror.URL, mirrorPath.Child("url"))...)
+			}
+			mirrorURLs[mirror.URL] = true
+			for j, capability := range mirror.Capabilities {
+				if !slices.Contains(supportedRegistryCapabilities, capability) {
+					errs = append(errs, field.NotSupported(mirrorPath.Child("capabilities").Index(j), capability, supportedRegistryCapabilities))
+				}
+			}
+			if mirror.CA != "" {
+				errs = append(errs, validateCABundle(mirror.CA, mirrorPath.Child("ca"))...)
+			}
+		}
+	}
+	errs = append(errs, validateContainerdRuntimes(containerd.Runtimes, fldPath.Child("runtimes"))...)
+	errs = append(errs, validatePrefetchImages(containerd.PrefetchImages, fldPath.Child("prefetchImages"))...)
+	if timeout := containerd.PrefetchImagesTimeout; timeout != nil && timeout.Duration <= 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("prefetchImagesTimeout"), timeout.Duration.String(), "must be a positive duration"))
+	}
+	if containerd.SandboxImage != "" && !imageReferencePattern.MatchString(containerd.SandboxImage) {
+		errs = append(errs, field.Invalid(fldPath.Child("sandboxImage"), containerd.SandboxImage, "must be an image reference such as public.ecr.aws/eks-distro/kubernetes/pause:3.10"))
+	}
+	errs = append(errs, validateSOCIOptions(&containerd.SOCI, fldPath.Child("soci"))...)
+	archives := map[string]bool{}
+	for i, archive := range containerd.ImageArchives {
+		archivePath := fldPath.Child("imageArchives").Index(i)
+		if !path.IsAbs(archive) {
+			errs = append(errs, field.Invalid(archivePath, archive, "must be an absolute path"))
+		} else if archives[archive] {
+			errs = append(errs, field.Duplicate(archivePath, archive))
+		}
+		archives[archive] = true
+	}
+	return errs
+}
+
+func validateContainerdRuntimes(runtimes []ContainerdRuntime, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	names := map[string]bool{}
+	defaultRuntime := ""
+	for i, runtime := range runtimes {
+		runtimePath := fldPath.Index(i)
+		if runtime.Name == "" {
+			errs = append(errs, field.Required(runtimePath.Child("name"), "runtime name must be provided"))
+		} else if msgs := validation.IsDNS1123Label(runtime.Name); len(msgs) > 0 {
+			errs = append(errs, field.Invalid(runtimePath.Child("name"), runtime.Name, strings.Join(msgs, "; ")))
+		} else if names[runtime.Name] {
+			errs = append(errs, field.Duplicate(runtimePath.Child("name"), runtime.Name))
+		}
+		names[runtime.Name] = true
+		if runtime.BinaryPath != "" && !path.IsAbs(runtime.BinaryPath) {
+			errs = append(errs, field.Invalid(runtimePath.Child("binaryPath"), runtime.BinaryPath, "must be an absolute path"))
+		}
+		if runtime.RuntimeType != "" && !runtimeTypePattern.MatchString(runtime.RuntimeType) {
+			errs = append(errs, field.Invalid(runtimePath.Child("runtimeType"), runtime.RuntimeType, "must be a runtime type such as io.containerd.runc.v2"))
+		}
+		if runtime.Default {
+			if defaultRuntime != "" {
+				errs = append(errs, field.Invalid(runtimePath.Child("default"), runtime.Default, fmt.Sprintf("only one runtime can be the default, but %s is already the default", defaultRuntime)))
+			}
+			defaultRuntime = runtime.Name
+		}
+	}
+	return errs
+}
+
+func validateSOCIOptions(soci *SOCIOptions, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if soci.MinMemory != nil && soci.MinMemory.Sign() < 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("minMemory"), soci.MinMemory.String(), "must not be negative"))
+	}
+	if soci.MinCPU != nil && soci.MinCPU.Sign() < 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("minCPU"), soci.MinCPU.String(), "must not be negative"))
+	}
+	limits := []struct {
+		name  string
+		value int32
+	}{
+		{"maxConcurrentDownloads", soci.MaxConcurrentDownloads},
+		{"maxConcurrentDownloadsPerImage", soci.MaxConcurrentDownloadsPerImage},
+		{"maxConcurrentUnpacks", soci.MaxConcurrentUnpacks},
+		{"maxConcurrentUnpacksPerImage", soci.MaxConcurrentUnpacksPerImage},
+	}
+	for _, limit := range limits {
+		if limit.value < -1 {
+			errs = append(errs, field.Invalid(fldPath.Child(limit.name), limit.value, "must be a positive number, or -1 for no limit"))
+		}
+	}
+	if len(soci.Config) > 0 {
+		var config map[string]any
+		if err := toml.Unmarshal([]byte(soci.Config), &config); err != nil {
+			errs = append(errs, field.Invalid(fldPath.Child("config"), field.OmitValueType{}, err.Error()))
+		}
+	}
+	return errs
+}
+
+func validatePrefetchImages(images []PrefetchImage, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	seen := map[string]bool{}
+	for i, image := range images {
+		imagePath := fldPath.Index(i)
+		if image.Reference == "" {
+			errs = append(errs, field.Required(imagePath.Child("reference"), "image reference must be provided"))
+		} else if !imageReferencePattern.MatchString(image.Reference) {
+			errs = append(errs, field.Invalid(imagePath.Child("reference"), image.Reference, "must be an image reference such as public.ecr.aws/eks-distro/kubernetes/pause:3.10"))
+		}
+		if image.Reference != "" && seen[image.Reference] {
+			errs = append(errs, field.Duplicate(imagePath, image.Reference))
+		}
+		seen[image.Reference] = true
+	}
+	return errs
+}
+
+func validateRegistryURL(rawURL string, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.RawQuery != "" || u.Fragment != "" {
+		errs = append(errs, field.Invalid(fldPath, rawURL, "must be an http or https URL without a query or fragment"))
+	}
+	return errs
+}
+
+func validateCABundle(bundle string, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	rest := []byte(bundle)
+	for count := 0; ; count++ {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			if count == 0 || len(bytes.TrimSpace(rest)) > 0 {
+				errs = append(errs, field.Invalid(fldPath, field.OmitValueType{}, "must be a bundle of PEM-encoded certificates"))
+			}
+			return errs
+		}
+		if block.Type != "CERTIFICATE" {
+			return append(errs, field.Invalid(fldPath, field.OmitValueType{}, fmt.Sprintf("must only contain certificates, found %s", block.Type)))
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return append(errs, field.Invalid(fldPath, field.OmitValueType{}, err.Error()))
+		}
+	}
+}
+
+func validateInstanceOptions(instance *InstanceOptions, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	localStoragePath := fldPath.Child("localStorage")
+	if strategy := instance.LocalStorage.Strategy; strategy != "" && !slices.Contains(supportedLocalStorageStrategies, strategy) {
+		errs = append(errs, field.NotSupported(localStoragePath.Child("strategy"), strategy, supportedLocalStorageStrategies))
+	}
+	if mountPath := instance.LocalStorage.MountPath; mountPath != "" && !path.IsAbs(mountPath) {
+		errs = append(errs, field.Invalid(localStoragePath.Child("mountPath"), mountPath, "must be an absolute path"))
+	}
+	for i, disabledMount := range instance.LocalStorage.DisabledMounts {
+		if !slices.Contains(supportedDisabledMounts, disabledMount) {
+			errs = append(errs, field.NotSupported(localStoragePath.Child("disabledMounts").Index(i), disabledMount, supportedDisabledMounts))
+		}
+	}
+	for i, nameserver := range instance.Network.Nameservers {
+		if net.ParseIP(nameserver) == nil {
+			errs = append(errs, field.Invalid(fldPath.Child("network", "nameservers").Index(i), nameserver, "must be a valid IP address"))
+		}
+	}
+	sysctlsPath := fldPath.Child("sysctls")
+	for _, name := range slices.Sorted(maps.Keys(instance.Sysctls)) {
+		if !sysctlNamePattern.MatchString(name) {
+			errs = append(errs, field.Invalid(sysctlsPath, name, "must be a kernel parameter name, e.g. 'net.core.somaxconn'"))
+		}
+		if value := instance.Sysctls[name]; len(strings.TrimSpace(value)) == 0 || strings.ContainsAny(value, "\n\r") {
+			errs = append(errs, field.Invalid(sysctlsPath.Key(name), value, "must be a non-empty, single-line value"))
+		}
+	}
+	hugepagesPath := fldPath.Child("hugepages")
+	var hugepagesPercent int
+	for _, size := range slices.Sorted(maps.Keys(instance.Hugepages)) {
+		if quantity, err := resource.ParseQuantity(size); err != nil {
+			errs = append(errs, field.Invalid(hugepagesPath.Key(size), size, "must be a page size, e.g. '2Mi' or '1Gi'"))
+		} else if bytes := quantity.Value(); bytes < 4096 || bytes&(bytes-1) != 0 {
+			errs = append(errs, field.Invalid(hugepagesPath.Key(size), size, "must be a power of two of at least 4Ki"))
+		}
+		value := instance.Hugepages[size]
+		if value.Type == intstr.String {
+			percent, err := strconv.Atoi(strings.TrimSuffix(value.StrVal, "%"))
+			if !strings.HasSuffix(value.StrVal, "%") || err != nil || percent < 0 || percent > 100 {
+				errs = append(errs, field.Invalid(hugepagesPath.Key(size), value.StrVal, "must be a number of pages or a percentage between 0% and 100%"))
+				continue
+			}
+			hugepagesPercent += percent
+		} else if value.IntVal < 0 {
+			errs = append(errs, field.Invalid(hugepagesPath.Key(size), value.IntVal, "must not be negative"))
+		}
+	}
+	if hugepagesPercent > 100 {
+		errs = append(errs, field.Invalid(hugepagesPath, fmt.Sprintf("%d%%", hugepagesPercent), "percentages of memory must not add up to more than 100%"))
+	}
+	for i, module := range instance.Kernel.Modules {
+		if !kernelModuleNamePattern.MatchString(module) {
+			errs = append(errs, field.Invalid(fldPath.Child("kernel", "modules").Index(i), module, "must be a kernel module name, e.g. 'br_netfilter'"))
+		}
+	}
+	for i, arg := range instance.Kernel.Args {
+		if len(arg) == 0 || strings.ContainsFunc(arg, unicode.IsSpace) || strings.ContainsAny(arg, `"'`) {
+			errs = append(errs, field.Invalid(fldPath.Child("kernel", "args").Index(i), arg, "must be a single kernel argument without whitespace or quotes"))
+		}
 	}
-	if cfg.Spec.Cluster.APIServerEndpoint == "" {
-		return fmt.Errorf("Apiserver endpoint is missing in cluster configuration")
+	return errs
+}
+
+// isKubernetesLabel returns true if the namespace of the label key is the
+// kubernetes.io or k8s.io namespace, or a subdomain of either.
+func isKubernetesLabel(key string) bool {
+	namespace, _, found := strings.Cut(key, "/")
+	if !found {
+		return false
+	}
+	for _, kubernetesNamespace := range []string{"kubernetes.io", "k8s.io"} {
+		if namespace == kubernetesNamespace || strings.HasSuffix(namespace, "."+kubernetesNamespace) {
+			return true
+		}
+	}
+	return false
+}
+
+func validateKubeletOptions(kubelet *KubeletOptions, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	for i, flag := range kubelet.Flags {
+		if strings.HasPrefix(flag, "-") || isKubeletFlagValue(kubelet.Flags, i) {
+			continue
+		}
+		errs = append(errs, field.Invalid(fldPath.Child("flags").Index(i), flag, "must be a command-line flag beginning with '-', or the value of the flag before it"))
+	}
+	labelsPath := fldPath.Child("labels")
+	for _, key := range slices.Sorted(maps.Keys(kubelet.Labels)) {
+		value := kubelet.Labels[key]
+		if msgs := validation.IsQualifiedName(key); len(msgs) > 0 {
+			errs = append(errs, field.Invalid(labelsPath, key, strings.Join(msgs, "; ")))
+		} else if isKubernetesLabel(key) && !kubeletapis.IsKubeletLabel(key) {
+			// the NodeRestriction admission plugin does not allow the kubelet
+			// to set these on its Node, and the kubelet refuses to start.
+			errs = append(errs, field.Invalid(labelsPath, key, "the kubernetes.io and k8s.io label namespaces are restricted to labels that the kubelet may set, such as those of the node.kubernetes.io and kubelet.kubernetes.io namespaces"))
+		}
+		if msgs := validation.IsValidLabelValue(value); len(msgs) > 0 {
+			errs = append(errs, field.Invalid(labelsPath.Key(key), value, strings.Join(msgs, "; ")))
+		}
+	}
+	type taintKey struct {
+		key    string
+		effect TaintEffect
 	}
-	if cfg.Spec.Cluster.CertificateAuthority == nil {
-		return fmt.Errorf("Certificate authority is missing in cluster configuration")
+	taintKeys := make(map[taintKey]bool)
+	for i, taint := range kubelet.Taints {
+		taintPath := fldPath.Child("taints").Index(i)
+		if msgs := validation.IsQualifiedName(taint.Key); len(msgs) > 0 {
+			errs = append(errs, field.Invalid(taintPath.Child("key"), taint.Key, strings.Join(msgs, "; ")))
+		}
+		if msgs := validation.IsValidLabelValue(taint.Value); len(msgs) > 0 {
+			errs = append(errs, field.Invalid(taintPath.Child("value"), taint.Value, strings.Join(msgs, "; ")))
+		}
+		if !slices.Contains(supportedTaintEffects, taint.Effect) {
+			errs = append(errs, field.NotSupported(taintPath.Child("effect"), taint.Effect, supportedTaintEffects))
+		}
+		key := taintKey{key: taint.Key, effect: taint.Effect}
+		if taintKeys[key] {
+			errs = append(errs, field.Duplicate(taintPath, taint))
+		}
+		taintKeys[key] = true
+	}
+	for i, autoLabel := range kubelet.AutoLabels {
+		if !slices.Contains(supportedAutoLabels, autoLabel) {
+			errs = append(errs, field.NotSupported(fldPath.Child("autoLabels").Index(i), autoLabel, supportedAutoLabels))
+		}
 	}
-	if cfg.Spec.Cluster.CIDR == "" {
-		return fmt.Errorf("CIDR is missing in cluster configuration")
+	if len(kubelet.MaxPodsExpression) > 0 {
+		env, err := NewMaxPodsExpressionEnv()
+		if err != nil {
+			errs = append(errs, field.InternalError(fldPath.Child("maxPodsExpression"), err))
+		} else if _, issues := env.Compile(kubelet.MaxPodsExpression); issues != nil && issues.Err() != nil {
+			errs = append(errs, field.Invalid(fldPath.Child("maxPodsExpression"), kubelet.MaxPodsExpression, issues.Err().Error()))
+		}
+	}
+	errs = append(errs, validateReservedResourceExpressions(&kubelet.KubeReservedExpressions, fldPath.Child("kubeReservedExpressions"))...)
+	errs = append(errs, validateReservedResourceExpressions(&kubelet.SystemReservedExpressions, fldPath.Child("systemReservedExpressions"))...)
+	return errs
+}
+
+func validateReservedResourceExpressions(expressions *ReservedResourceExpressions, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if *expressions == (ReservedResourceExpressions{}) {
+		return errs
+	}
+	env, err := NewReservedExpressionEnv()
+	if err != nil {
+		return append(errs, field.InternalError(fldPath, err))
 	}
-	if enabled := cfg.Spec.Cluster.EnableOutpost; enabled != nil && *enabled {
-		if cfg.Spec.Cluster.ID == "" {
-			return fmt.Errorf("cluster ID must be provided for outposts")
+	for _, expression := range []struct {
+		name  string
+		value string
+	}{
+		{name: "cpu", value: expressions.CPU},
+		{name: "memory", value: expressions.Memory},
+		{name: "ephemeralStorage", value: expressions.EphemeralStorage},
+	} {
+		if len(expression.value) == 0 {
+			continue
+		}
+		if _, issues := env.Compile(expression.value); issues != nil && issues.Err() != nil {
+			errs = append(errs, field.Invalid(fldPath.Child(expression.name), expression.value, issues.Err().Error()))
 		}
 	}
-	return nil
+	return errs
 }
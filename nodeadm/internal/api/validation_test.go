@@ -0,0 +1,365 @@
+package api
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func validNodeConfig() *NodeConfig {
+	return &NodeConfig{
+		Spec: NodeConfigSpec{
+			Cluster: ClusterDetails{
+				Name:                 "my-cluster",
+				APIServerEndpoint:    "https://example.com",
+				CertificateAuthority: []byte("certificateAuthority"),
+				CIDR:                 "10.100.0.0/16",
+			},
+		},
+	}
+}
+
+// testCABundle returns a PEM-encoded self-signed certificate authority.
+func testCABundle(t *testing.T) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "registry-ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestValidateNodeConfig(t *testing.T) {
+	enabled := true
+	ca := testCABundle(t)
+	tests := []struct {
+		name           string
+		modify         func(*NodeConfig)
+		expectedFields []string
+	}{
+		{
+			name:   "valid",
+			modify: func(cfg *NodeConfig) {},
+		},
+		{
+			name: "missing cluster details",
+			modify: func(cfg *NodeConfig) {
+				cfg.Spec.Cluster = ClusterDetails{EnableOutpost: &enabled}
+			},
+			expectedFields: []string{
+				"spec.cluster.name",
+				"spec.cluster.apiServerEndpoint",
+				"spec.cluster.certificateAuthority",
+				"spec.cluster.cidr",
+				"spec.cluster.id",
+			},
+		},
+		{
+			name: "invalid cluster details",
+			modify: func(cfg *NodeConfig) {
+				cfg.Spec.Cluster.APIServerEndpoint = "example.com"
+				cfg.Spec.Cluster.CIDR = "10.100.0.0"
+			},
+			expectedFields: []string{
+				"spec.cluster.apiServerEndpoint",
+				"spec.cluster.cidr",
+			},
+		},
+		{
+			name: "invalid containerd config",
+			modify: func(cfg *NodeConfig) {
+				cfg.Spec.Containerd.Config = "[plugins"
+			},
+			expectedFields: []string{"spec.containerd.config"},
+		},
+		{
+			name: "valid containerd registries",
+			modify: func(cfg *NodeConfig) {
+				cfg.Spec.Containerd.Registries = map[string]RegistryOptions{
+					DefaultRegistryHost: {Mirrors: []RegistryMirror{{URL: "https://mirror.example.com"}}},
+					"registry.example.com:5000": {
+						Server:     "http://registry.example.com:5000",
+						CA:         ca,
+						SkipVerify: true,
+						Mirrors: []RegistryMirror{{
+							URL:          "https://mirror.example.com/v2/registry.example.com",
+							Capabilities: []RegistryCapability{RegistryCapabilityPull},
+							CA:           ca + ca,
+							OverridePath: true,
+						}},
+					},
+				}
+			},
+		},
+		{
+			name: "valid containerd runtimes",
+			modify: func(cfg *NodeConfig) {
+				cfg.Spec.Containerd.Runtimes = []ContainerdRuntime{
+					{Name: "runsc", RuntimeType: "io.containerd.runsc.v1", Options: InlineDocument{"TypeUrl": {Raw: []byte(`"io.containerd.runsc.v1.options"`)}}},
+					{Name: "runc-debug", BinaryPath: "/usr/local/bin/runc", Default: true},
+				}
+			},
+		},
+		{
+			name: "invalid containerd runtimes",
+			modify: func(cfg *NodeConfig) {
+				cfg.Spec.Containerd.Runtimes = []ContainerdRuntime{
+					{Name: "runsc", RuntimeType: "runsc", Default: true},
+					{Name: "runsc", BinaryPath: "bin/runsc"},
+					{Name: "Kata_Containers", Default: true},
+					{},
+				}
+			},
+			expectedFields: []string{
+				"spec.containerd.runtimes[0].runtimeType",
+				"spec.containerd.runtimes[1].name",
+				"spec.containerd.runtimes[1].binaryPath",
+				"spec.containerd.runtimes[2].name",
+				"spec.containerd.runtimes[2].default",
+				"spec.containerd.runtimes[3].name",
+			},
+		},
+		{
+			name: "valid prefetch images",
+			modify: func(cfg *NodeConfig) {
+				cfg.Spec.Containerd.PrefetchImages = []PrefetchImage{
+					{Reference: "public.ecr.aws/eks/aws-load-balancer-controller:v2.7.1", Pin: true},
+					{Reference: "registry.example.com:5000/busybox@sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+					{Reference: "nginx"},
+				}
+			},
+		},
+		{
+			name: "invalid prefetch images",
+			modify: func(cfg *NodeConfig) {
+				cfg.Spec.Containerd.PrefetchImages = []PrefetchImage{
+					{Reference: "public.ecr.aws/eks/aws-load-balancer-controller:v2.7.1"},
+					{Reference: "public.ecr.aws/eks/aws-load-balancer-controller:v2.7.1"},
+					{Reference: "Public.ecr.aws/EKS/pause:3.10"},
+					{},
+				}
+				cfg.Spec.Containerd.PrefetchImagesTimeout = &metav1.Duration{Duration: -time.Minute}
+			},
+			expectedFields: []string{
+				"spec.containerd.prefetchImages[1]",
+				"spec.containerd.prefetchImages[2].reference",
+				"spec.containerd.prefetchImages[3].reference",
+				"spec.containerd.prefetchImagesTimeout",
+			},
+		},
+		{
+			name: "valid sandbox image and image archives",
+			modify: func(cfg *NodeConfig) {
+				cfg.Spec.Containerd.SandboxImage = "registry.example.com/eks/pause:3.10"
+				cfg.Spec.Containerd.ImageArchives = []string{"/opt/images/pause.tar", "/opt/images/agent.tar"}
+			},
+		},
+		{
+			name: "invalid sandbox image and image archives",
+			modify: func(cfg *NodeConfig) {
+				cfg.Spec.Containerd.SandboxImage = "registry.example.com/eks/pause:"
+				cfg.Spec.Containerd.ImageArchives = []string{"/opt/images/pause.tar", "images/agent.tar", "/opt/images/pause.tar"}
+			},
+			expectedFields: []string{
+				"spec.containerd.sandboxImage",
+				"spec.containerd.imageArchives[1]",
+				"spec.containerd.imageArchives[2]",
+			},
+		},
+		{
+			name: "valid soci options",
+			modify: func(cfg *NodeConfig) {
+				minMemory, minCPU := resource.MustParse("16Gi"), resource.MustParse("3500m")
+				cfg.Spec.Containerd.SOCI = SOCIOptions{
+					MinMemory:                      &minMemory,
+					MinCPU:                         &minCPU,
+					MaxConcurrentDownloads:         -1,
+					MaxConcurrentDownloadsPerImage: 10,
+					Config:                         "[pull_modes.parallel_pull_unpack]\nconcurrent_download_chunk_size = '32mb'\n",
+				}
+			},
+		},
+		{
+			name: "invalid soci options",
+			modify: func(cfg *NodeConfig) {
+				minMemory := resource.MustParse("-1Gi")
+				cfg.Spec.Containerd.SOCI = SOCIOptions{
+					MinMemory:            &minMemory,
+					MaxConcurrentUnpacks: -2,
+					Config:               "[pull_modes",
+				}
+			},
+			expectedFields: []string{
+				"spec.containerd.soci.minMemory",
+				"spec.containerd.soci.maxConcurrentUnpacks",
+				"spec.containerd.soci.config",
+			},
+		},
+		{
+			name: "invalid containerd registries",
+			modify: func(cfg *NodeConfig) {
+				cfg.Spec.Containerd.Registries = map[string]RegistryOptions{
+					"../etc": {},
+					"docker.io": {
+						Server: "registry-1.docker.io",
+						CA:     "certificate",
+						Mirrors: []RegistryMirror{
+							{URL: "https://mirror.example.com", Capabilities: []RegistryCapability{"delete"}},
+							{URL: "https://mirror.example.com"},
+							{URL: "ftp://mirror.example.com", CA: ca + "trailing"},
+							{},
+						},
+					},
+				}
+			},
+			expectedFields: []string{
+				"spec.containerd.registries[../etc]",
+				"spec.containerd.registries[docker.io].server",
+				"spec.containerd.registries[docker.io].ca",
+				"spec.containerd.registries[docker.io].mirrors[0].capabilities[0]",
+				"spec.containerd.registries[docker.io].mirrors[1].url",
+				"spec.containerd.registries[docker.io].mirrors[2].url",
+				"spec.containerd.registries[docker.io].mirrors[2].ca",
+				"spec.containerd.registries[docker.io].mirrors[3].url",
+			},
+		},
+		{
+			name: "invalid instance options",
+			modify: func(cfg *NodeConfig) {
+				cfg.Spec.Instance.LocalStorage = LocalStorageOptions{
+					Strategy:       "RAID5",
+					MountPath:      "mnt/k8s-disks",
+					DisabledMounts: []DisabledMount{DisabledMountPodLogs, "Logs"},
+				}
+				cfg.Spec.Instance.Network.Nameservers = []string{"8.8.8.8", "dns.google"}
+				cfg.Spec.Instance.Sysctls = map[string]string{
+					"net.core.somaxconn":               "4096",
+					"net.ipv4.conf.eth0/100.rp_filter": "0",
+					"net..somaxconn":                   "4096",
+					"vm.max_map_count":                 "",
+				}
+				cfg.Spec.Instance.Hugepages = map[string]intstr.IntOrString{
+					"2Mi":  intstr.FromString("60%"),
+					"1Gi":  intstr.FromString("50%"),
+					"64Ki": intstr.FromInt32(16),
+					"3Mi":  intstr.FromString("ten"),
+					"32Mi": intstr.FromInt32(-1),
+				}
+				cfg.Spec.Instance.Kernel = KernelOptions{
+					Modules: []string{"ip_vs", "br_netfilter; reboot"},
+					Args:    []string{"transparent_hugepage=never", "quiet splash"},
+				}
+			},
+			expectedFields: []string{
+				"spec.instance.localStorage.strategy",
+				"spec.instance.localStorage.mountPath",
+				"spec.instance.localStorage.disabledMounts[1]",
+				"spec.instance.network.nameservers[1]",
+				"spec.instance.sysctls",
+				"spec.instance.sysctls[vm.max_map_count]",
+				"spec.instance.hugepages[32Mi]",
+				"spec.instance.hugepages[3Mi]",
+				"spec.instance.hugepages[3Mi]",
+				"spec.instance.hugepages",
+				"spec.instance.kernel.modules[1]",
+				"spec.instance.kernel.args[1]",
+			},
+		},
+		{
+			name: "invalid kubelet options",
+			modify: func(cfg *NodeConfig) {
+				cfg.Spec.Kubelet.Flags = []string{"--v=5", "node-labels=foo=bar"}
+				cfg.Spec.Kubelet.MaxPodsExpression = "max_pods + unknown_var"
+				cfg.Spec.Kubelet.AutoLabels = []AutoLabel{AutoLabelCPU, "GPU"}
+				cfg.Spec.Kubelet.KubeReservedExpressions = ReservedResourceExpressions{
+					CPU:    "default_reserved + vcpu_millicores / 100",
+					Memory: "max_pods * unknown_var",
+				}
+				cfg.Spec.Kubelet.SystemReservedExpressions = ReservedResourceExpressions{
+					Memory:           "instance_type.startsWith('m5.') ? 512 : 256",
+					EphemeralStorage: "1024 +",
+				}
+			},
+			expectedFields: []string{
+				"spec.kubelet.flags[1]",
+				"spec.kubelet.autoLabels[1]",
+				"spec.kubelet.maxPodsExpression",
+				"spec.kubelet.kubeReservedExpressions.memory",
+				"spec.kubelet.systemReservedExpressions.ephemeralStorage",
+			},
+		},
+		{
+			name: "split kubelet flag values",
+			modify: func(cfg *NodeConfig) {
+				cfg.Spec.Kubelet.Flags = []string{"--node-labels", "foo=bar", "--v", "5", "6", "--register-with-taints=a=b:NoSchedule", "c=d:NoSchedule"}
+			},
+			expectedFields: []string{
+				"spec.kubelet.flags[4]",
+				"spec.kubelet.flags[6]",
+			},
+		},
+		{
+			name: "invalid labels and taints",
+			modify: func(cfg *NodeConfig) {
+				cfg.Spec.Kubelet.Labels = map[string]string{
+					"example.com/role":               "worker",
+					"-invalid":                       "worker",
+					"team":                           "a b",
+					"kubernetes.io/role":             "worker",
+					"node-role.kubernetes.io/worker": "",
+					"node.kubernetes.io/lifecycle":   "spot",
+					"topology.kubernetes.io/zone":    "us-west-2a",
+				}
+				cfg.Spec.Kubelet.Taints = []Taint{
+					{Key: "dedicated", Value: "gpu", Effect: TaintEffectNoSchedule},
+					{Key: "dedicated", Value: "inference", Effect: TaintEffectNoSchedule},
+					{Key: "spot", Effect: "NoRun"},
+					{Key: "", Effect: TaintEffectNoExecute},
+				}
+			},
+			expectedFields: []string{
+				"spec.kubelet.labels",
+				"spec.kubelet.labels",
+				"spec.kubelet.labels",
+				"spec.kubelet.labels[team]",
+				"spec.kubelet.taints[1]",
+				"spec.kubelet.taints[2].effect",
+				"spec.kubelet.taints[3].key",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := validNodeConfig()
+			test.modify(cfg)
+			var fields []string
+			for _, err := range ValidateNodeConfig(cfg) {
+				fields = append(fields, err.Field)
+			}
+			assert.Equal(t, test.expectedFields, fields)
+		})
+	}
+}
@@ -13,6 +13,7 @@ type FakeIMDSClient struct {
 	GetPropertyBytesFunc            func(ctx context.Context, prop IMDSProperty) ([]byte, error)
 	GetUserDataFunc                 func(ctx context.Context) ([]byte, error)
 	GetInstanceIdentityDocumentFunc func(ctx context.Context) (*imds.GetInstanceIdentityDocumentOutput, error)
+	GetInstanceTagsFunc             func(ctx context.Context) (map[string]string, error)
 }
 
 // GetInstanceIdentityDocument implements IMDSClient.
@@ -46,3 +47,11 @@ func (f *FakeIMDSClient) GetUserData(ctx context.Context) ([]byte, error) {
 	}
 	return f.GetUserDataFunc(ctx)
 }
+
+// GetInstanceTags implements IMDSClient.
+func (f *FakeIMDSClient) GetInstanceTags(ctx context.Context) (map[string]string, error) {
+	if f.GetInstanceTagsFunc == nil {
+		panic("unimplemented")
+	}
+	return f.GetInstanceTagsFunc(ctx)
+}
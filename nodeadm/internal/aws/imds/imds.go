@@ -2,28 +2,32 @@ package imds
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"path"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws/ratelimit"
 	"github.com/aws/aws-sdk-go-v2/aws/retry"
 	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/aws/aws-sdk-go-v2/feature/ec2/imds"
+	"golang.org/x/net/http/httpproxy"
 )
 
 var _defaultClient *imds.Client
 
-// This function is a wrapper around the default `http.ProxyFromEnvironment` function
-// which cachces the proxy variables in environment when first invoked thus, preventing subsequent
-// configuration attempts of http proxy via derived from user-data. Since, the first outbound
-// API call in nodeadm is to the IMDS for fetching user-data, we bypass caching.
+// ProxyFromCurrentEnvironment behaves like http.ProxyFromEnvironment, except
+// that the environment is read on every call instead of being cached when first
+// invoked. This allows the proxy variables that nodeadm sets from user-data to
+// take effect for the requests that follow, including the AWS API calls made
+// by nodeadm. The IMDS is always reached directly.
 // Ref: https://github.com/golang/go/blob/master/src/net/http/transport.go#L499
-func dynamicProxyFunc(req *http.Request) (*url.URL, error) {
+func ProxyFromCurrentEnvironment(req *http.Request) (*url.URL, error) {
 	// Link-local addresses for IMDS do not need to be going through a proxy
 	// The IMDS has two endpoints on an instance: IPv4 (169.254.169.254) and IPv6 ([fd00:ec2::254])
 	// https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/configuring-instance-metadata-options.html
@@ -33,7 +37,7 @@ func dynamicProxyFunc(req *http.Request) (*url.URL, error) {
 		return nil, nil
 	}
 
-	return http.ProxyFromEnvironment(req)
+	return httpproxy.FromEnvironment().ProxyFunc()(req.URL)
 }
 
 func init() {
@@ -47,24 +51,34 @@ const (
 	LocalIPv4      IMDSProperty = "local-ipv4"
 	MAC            IMDSProperty = "mac"
 	MACs           IMDSProperty = "network/interfaces/macs/"
+	InstanceTags   IMDSProperty = "tags/instance"
+	OutpostARN     IMDSProperty = "outpost-arn"
 )
 
 var (
 	DeviceIndex = func(mac string) IMDSProperty { return IMDSProperty(path.Join(string(MACs), mac, "device-number")) }
 	NetworkCard = func(mac string) IMDSProperty { return IMDSProperty(path.Join(string(MACs), mac, "network-card")) }
 	LocalIPv4s  = func(mac string) IMDSProperty { return IMDSProperty(path.Join(string(MACs), mac, "local-ipv4s")) }
+	InstanceTag = func(key string) IMDSProperty { return IMDSProperty(path.Join(string(InstanceTags), key)) }
+)
+
+var (
+	// ErrInstanceTagsUnavailable is returned when access to instance tags in
+	// instance metadata has not been enabled.
+	ErrInstanceTagsUnavailable = errors.New("instance tags are not available in instance metadata")
 )
 
 type IMDSClient interface {
 	GetInstanceIdentityDocument(ctx context.Context) (*imds.GetInstanceIdentityDocumentOutput, error)
 	GetUserData(ctx context.Context) ([]byte, error)
 	GetProperty(ctx context.Context, prop IMDSProperty) (string, error)
+	GetInstanceTags(ctx context.Context) (map[string]string, error)
 }
 
 func New(retry404s bool, fnOpts ...func(*imds.Options)) *imds.Client {
 	// Create HTTP client with dynamic proxy function
 	httpClient := awshttp.NewBuildableClient().WithTransportOptions(func(tr *http.Transport) {
-		tr.Proxy = dynamicProxyFunc
+		tr.Proxy = ProxyFromCurrentEnvironment
 	})
 
 	return imds.New(imds.Options{
@@ -130,3 +144,31 @@ func (c *imdsClient) GetProperty(ctx context.Context, prop IMDSProperty) (string
 	}
 	return string(bytes), nil
 }
+
+func (c *imdsClient) GetInstanceTags(ctx context.Context) (map[string]string, error) {
+	keys, err := c.GetProperty(ctx, InstanceTags)
+	if err != nil {
+		if IsNotFound(err) {
+			return nil, ErrInstanceTagsUnavailable
+		}
+		return nil, err
+	}
+	tags := make(map[string]string)
+	// tag keys in instance metadata cannot contain spaces, and are listed one
+	// per line.
+	for _, key := range strings.Fields(keys) {
+		value, err := c.GetProperty(ctx, InstanceTag(key))
+		if err != nil {
+			return nil, err
+		}
+		tags[key] = value
+	}
+	return tags, nil
+}
+
+// IsNotFound returns whether the error is from a request for a property that
+// does not exist in instance metadata.
+func IsNotFound(err error) bool {
+	var respErr interface{ HTTPStatusCode() int }
+	return errors.As(err, &respErr) && respErr.HTTPStatusCode() == http.StatusNotFound
+}
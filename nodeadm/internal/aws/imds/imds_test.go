@@ -1,12 +1,16 @@
 package imds
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/exec"
+	"strings"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/feature/ec2/imds"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -51,9 +55,8 @@ func TestDynamicProxyFuncBehavior(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Testing the dynamicProxyFunc can invoke http.ProxyFromEnvironment() function
-			// which freezes the proxy environment variables for the entire process due to sync.Once caching.
-			// Hence, each test-case is run in a separate process to avoid caching.
+			// Each test-case is run in a separate process so that the proxy environment
+			// variables of one case do not leak into the others.
 			cmd := exec.Command("go", "test", "-run", "TestSingleProxyCase", "./")
 
 			cmd.Env = os.Environ()
@@ -84,10 +87,10 @@ func TestSingleProxyCase(t *testing.T) {
 	expectedProxy := os.Getenv("EXPECTED_PROXY")
 
 	req, _ := http.NewRequest("GET", testURL, nil)
-	proxyURL, err := dynamicProxyFunc(req)
+	proxyURL, err := ProxyFromCurrentEnvironment(req)
 
 	if err != nil {
-		t.Fatalf("dynamicProxyFunc returned error: %v", err)
+		t.Fatalf("ProxyFromCurrentEnvironment returned error: %v", err)
 	}
 
 	var actualProxy string
@@ -97,3 +100,48 @@ func TestSingleProxyCase(t *testing.T) {
 
 	assert.Equal(t, expectedProxy, actualProxy)
 }
+
+func TestGetInstanceTags(t *testing.T) {
+	newServer := func(tags map[string]string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodPut && r.URL.Path == "/latest/api/token":
+				_, _ = w.Write([]byte("token"))
+			case tags == nil:
+				w.WriteHeader(http.StatusNotFound)
+			case r.URL.Path == "/latest/meta-data/tags/instance":
+				var keys []string
+				for key := range tags {
+					keys = append(keys, key)
+				}
+				_, _ = w.Write([]byte(strings.Join(keys, "\n")))
+			default:
+				value, ok := tags[strings.TrimPrefix(r.URL.Path, "/latest/meta-data/tags/instance/")]
+				if !ok {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				_, _ = w.Write([]byte(value))
+			}
+		}))
+	}
+
+	t.Run("Enabled", func(t *testing.T) {
+		tags := map[string]string{"Name": "my-node", "node.eks.aws:labels": "role=worker"}
+		server := newServer(tags)
+		defer server.Close()
+		client := NewClient(New(false, func(o *imds.Options) { o.Endpoint = server.URL }))
+		actual, err := client.GetInstanceTags(context.TODO())
+		if assert.NoError(t, err) {
+			assert.Equal(t, tags, actual)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		server := newServer(nil)
+		defer server.Close()
+		client := NewClient(New(false, func(o *imds.Options) { o.Endpoint = server.URL }))
+		_, err := client.GetInstanceTags(context.TODO())
+		assert.ErrorIs(t, err, ErrInstanceTagsUnavailable)
+	})
+}
@@ -15,7 +15,7 @@ import (
 )
 
 // ResolveConfig returns either the cached config or the provided config chain.
-func ResolveConfig(log *zap.Logger, rawConfigSourceURLs []string, configCachePath string) (cfg *api.NodeConfig, isChanged bool, shouldEnrichConfig bool, err error) {
+func ResolveConfig(log *zap.Logger, rawConfigSourceURLs []string, configCachePath string, opts ...configprovider.BuildOption) (cfg *api.NodeConfig, isChanged bool, shouldEnrichConfig bool, err error) {
 	var cachedConfig *api.NodeConfig
 	shouldEnrichConfig = false
 
@@ -24,11 +24,19 @@ func ResolveConfig(log *zap.Logger, rawConfigSourceURLs []string, configCachePat
 		if err != nil {
 			log.Warn("failed to load cached config", zap.Error(err))
 		} else {
+			if err := config.RecordOrigin(api.FieldOrigin{Source: configCachePath}); err != nil {
+				log.Warn("failed to record origin of cached config", zap.Error(err))
+			}
 			cachedConfig = config
 		}
 	}
 
-	provider, err := configprovider.BuildConfigProviderChain(rawConfigSourceURLs)
+	buildOpts := opts
+	if len(configCachePath) > 0 {
+		// config sources keep what they fetched alongside the config cache.
+		buildOpts = append(buildOpts, configprovider.WithCacheDir(configCachePath+".d"))
+	}
+	provider, err := configprovider.BuildConfigProviderChain(rawConfigSourceURLs, buildOpts...)
 	if err != nil {
 		return nil, false, shouldEnrichConfig, err
 	}
@@ -48,6 +56,9 @@ func ResolveConfig(log *zap.Logger, rawConfigSourceURLs []string, configCachePat
 	//
 	// if perf of reflect.DeepEqual becomes an issue, look into something like: https://github.com/Wind-River/deepequal-gen
 	if cachedConfig != nil && reflect.DeepEqual(nodeConfig.Spec, cachedConfig.Spec) {
+		cachedConfig.Origins = nodeConfig.Origins
+		// the directives that were removed from the spec may differ.
+		cachedConfig.Status.KubeletConfigOverrides = nodeConfig.Status.KubeletConfigOverrides
 		return cachedConfig, false, shouldEnrichConfig, nil
 	}
 
@@ -56,14 +67,22 @@ func ResolveConfig(log *zap.Logger, rawConfigSourceURLs []string, configCachePat
 	// In both cases, the config should be enriched
 	shouldEnrichConfig = true
 
+	if cachedConfig != nil {
+		// changes made to the kernel persist regardless of the spec, so that
+		// the instance is only rebooted once for them.
+		nodeConfig.Status.Kernel = cachedConfig.Status.Kernel
+	}
+
 	// we return the presence of a cache as the `isChanged` value, because if we
 	// had a cache hit and didnt use it, it's because we have a modified config.
 	return nodeConfig, cachedConfig != nil, shouldEnrichConfig, nil
 }
 
 func LoadCachedConfig(path string) (*api.NodeConfig, error) {
+	// the cache is read from where SaveCachedConfig writes it, which is
+	// beneath the root directory of a dry run.
 	// #nosec G304 // intended mechanism to read user-provided config file
-	nodeConfigData, err := os.ReadFile(path)
+	nodeConfigData, err := os.ReadFile(util.RootedPath(path))
 	if err != nil {
 		return nil, err
 	}
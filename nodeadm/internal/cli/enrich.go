@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"go.uber.org/zap"
+
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/aws/imds"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/kubelet"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/util"
+)
+
+// EnrichConfig populates the internal .status portion of the NodeConfig, used
+// only for internal implementation details.
+func EnrichConfig(log *zap.Logger, cfg *api.NodeConfig, opts *GlobalOptions) error {
+	log.Info("Fetching kubelet version..")
+	kubeletVersion, err := kubelet.GetKubeletVersion()
+	if err != nil {
+		return err
+	}
+	cfg.Status.KubeletVersion = kubeletVersion
+	log.Info("Fetched kubelet version", zap.String("version", kubeletVersion))
+	log.Info("Fetching instance details..")
+	awsClientLogMode := aws.LogRetries
+	if opts.DevelopmentMode {
+		// SDK v2 log modes are just bitwise operations, toggle all bits for maximum verbosity
+		// https://github.com/aws/aws-sdk-go-v2/blob/838fb872e9701fc62b7b86164389791f5313bfcb/aws/logging.go#L18
+		awsClientLogMode = aws.ClientLogMode((1 << 64) - 1)
+	}
+	awsConfig, err := util.LoadAWSConfig(context.TODO(), config.WithClientLogMode(awsClientLogMode))
+	if err != nil {
+		return err
+	}
+	instanceDetails, err := api.GetInstanceDetails(context.TODO(), cfg.Spec.FeatureGates, ec2.NewFromConfig(awsConfig), imds.DefaultClient())
+	if err != nil {
+		return err
+	}
+	cfg.Status.Instance = *instanceDetails
+	log.Info("Instance details populated", zap.Reflect("details", instanceDetails))
+	log.Info("Fetching default options...")
+	cfg.Status.Defaults = api.DefaultOptions{
+		SandboxImage: "localhost/kubernetes/pause:latest",
+	}
+	log.Info("Default options populated", zap.Reflect("defaults", cfg.Status.Defaults))
+	return nil
+}
@@ -34,7 +34,7 @@ func RegisterFlagConfigOutput(c *flaggy.Subcommand, configOutput *string) {
 // Callers should fall back to DefaultConfigSources if the user has provided no input.
 // ResolveConfigSources can be used for this, for convenience.
 func RegisterFlagConfigSources(c *flaggy.Subcommand, configSources *[]string) {
-	c.StringSlice(configSources, "c", "config-source", "Source(s) of node configuration. The format is a URI with supported schemes: [imds, file]. Sources will be evaluated in the order specified.")
+	c.StringSlice(configSources, "c", "config-source", "Source(s) of node configuration. The format is a URI with supported schemes: [imds, file, s3, ssm, secretsmanager, http, https]. Sources will be evaluated in the order specified.")
 }
 
 // ResolveConfigSources returns the default config sources if the specified slice is empty.
@@ -47,5 +47,5 @@ func ResolveConfigSources(configSources []string) []string {
 }
 
 func RegisterFlagConfigCache(c *flaggy.Subcommand, configCache *string) {
-	c.String(configCache, "", "config-cache", "File path at which to cache the resolved/enriched config. This can make repeated init calls more efficient. JSON encoding will be used.")
+	c.String(configCache, "", "config-cache", "File path at which to cache the resolved/enriched config. This can make repeated init calls more efficient. JSON encoding will be used. Responses from HTTP(S) config sources are cached in a directory with the same path and a .d suffix.")
 }
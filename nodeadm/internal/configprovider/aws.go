@@ -0,0 +1,21 @@
+package configprovider
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/util"
+)
+
+// loadAWSConfig loads the SDK configuration used to fetch config from AWS
+// services with the credentials of the instance. When no region is given, the
+// region is resolved from the environment or from IMDS.
+func loadAWSConfig(ctx context.Context, region string) (aws.Config, error) {
+	var optFns []func(*config.LoadOptions) error
+	if len(region) > 0 {
+		optFns = append(optFns, config.WithRegion(region))
+	}
+	return util.LoadAWSConfig(ctx, optFns...)
+}
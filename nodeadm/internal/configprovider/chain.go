@@ -15,6 +15,12 @@ var (
 
 type configProviderChain struct {
 	providers []ConfigProvider
+	// sources are the URLs of the providers, recorded as the origin of the
+	// fields that each provider sets.
+	sources []string
+	// onConfig is called with the config of each provider, e.g. so that later
+	// providers reach AWS APIs through the proxy configured by earlier ones.
+	onConfig func(*internalapi.NodeConfig) error
 }
 
 func NewConfigProviderChain(providers []ConfigProvider) *configProviderChain {
@@ -35,6 +41,22 @@ func (c *configProviderChain) Provide() (*internalapi.NodeConfig, error) {
 				if errors.Is(err, ErrNoConfigInDirectory) {
 					continue
 				}
+			case *s3ConfigProvider:
+				if errors.Is(err, ErrNoConfigInS3Prefix) {
+					continue
+				}
+			case *ssmConfigProvider:
+				if errors.Is(err, ErrNoConfigInSSMPath) {
+					continue
+				}
+			case *secretsManagerConfigProvider:
+				if errors.Is(err, ErrNoConfigInSecretsManagerPrefix) {
+					continue
+				}
+			case *instanceTagsConfigProvider:
+				if errors.Is(err, ErrNoConfigInInstanceTags) {
+					continue
+				}
 			case *userDataConfigProvider:
 				if errors.Is(err, ErrNoConfigInUserData) {
 					continue
@@ -47,11 +69,26 @@ func (c *configProviderChain) Provide() (*internalapi.NodeConfig, error) {
 			}
 			return nil, fmt.Errorf("config provider at index %d failed: %v", idx, err)
 		} else {
+			if idx < len(c.sources) {
+				config.SetOriginSource(c.sources[idx])
+			}
 			configs = append(configs, config)
+			if c.onConfig != nil {
+				if err := c.onConfig(config); err != nil {
+					return nil, err
+				}
+			}
 		}
 	}
 	if len(configs) == 0 {
 		return nil, ErrNoConfigInChain
 	}
-	return internalapi.MergeNodeConfigs(configs)
+	config, err := internalapi.MergeNodeConfigs(configs)
+	if err != nil {
+		return nil, err
+	}
+	if err := config.RemovePatchDirectives(); err != nil {
+		return nil, err
+	}
+	return config, nil
 }
@@ -140,33 +140,41 @@ func TestChainConfigProvider_MultiProvider(t *testing.T) {
 				fileProvider1,
 				fileProvider2,
 			},
+			sources: []string{"file:///one/", "file:///two/"},
 		}
 
 		nodeConfig, err := chainProvider.Provide()
 		assert.NoError(t, err)
-		assert.Equal(t, nodeConfig, &api.NodeConfig{
-			Spec: api.NodeConfigSpec{
-				Cluster: api.ClusterDetails{
-					Name:                 "my-cluster",
-					APIServerEndpoint:    "https://example.com",
-					CertificateAuthority: []byte("certificateAuthority"),
-					CIDR:                 "10.100.0.0/16",
+		assert.Equal(t, nodeConfig.Spec, api.NodeConfigSpec{
+			Cluster: api.ClusterDetails{
+				Name:                 "my-cluster",
+				APIServerEndpoint:    "https://example.com",
+				CertificateAuthority: []byte("certificateAuthority"),
+				CIDR:                 "10.100.0.0/16",
+			},
+			Kubelet: api.KubeletOptions{
+				Config: api.InlineDocument{
+					"maxPods":        runtime.RawExtension{Raw: []byte("150")},
+					"podsPerCore":    runtime.RawExtension{Raw: []byte("20")},
+					"port":           runtime.RawExtension{Raw: []byte("1010")},
+					"systemReserved": runtime.RawExtension{Raw: []byte(`{"cpu":"150m"}`)},
 				},
-				Kubelet: api.KubeletOptions{
-					Config: api.InlineDocument{
-						"maxPods":        runtime.RawExtension{Raw: []byte("150")},
-						"podsPerCore":    runtime.RawExtension{Raw: []byte("20")},
-						"port":           runtime.RawExtension{Raw: []byte("1010")},
-						"systemReserved": runtime.RawExtension{Raw: []byte(`{"cpu":"150m"}`)},
-					},
-					Flags: []string{
-						"--v=2",
-						"--node-labels=foo=bar,nodegroup=test",
-						"--v=5",
-						"--node-labels=foo=baz",
-					},
+				Flags: []string{
+					"--v=2",
+					"--node-labels=foo=bar,nodegroup=test",
+					"--v=5",
+					"--node-labels=foo=baz",
 				},
 			},
 		})
+		one := api.FieldOrigin{Source: "file:///one/", Object: "config.yaml"}
+		two := api.FieldOrigin{Source: "file:///two/", Object: "config.yaml"}
+		assert.Equal(t, one, nodeConfig.Origins["spec.cluster.name"])
+		assert.Equal(t, one, nodeConfig.Origins["spec.kubelet.config.port"])
+		assert.Equal(t, two, nodeConfig.Origins["spec.kubelet.config.maxPods"])
+		assert.Equal(t, two, nodeConfig.Origins["spec.kubelet.config.systemReserved.cpu"])
+		assert.Equal(t, one, nodeConfig.Origins["spec.kubelet.flags[1]"])
+		assert.Equal(t, two, nodeConfig.Origins["spec.kubelet.flags[2]"])
+		assert.Equal(t, "file:///two/ (config.yaml)", nodeConfig.Origins["spec.kubelet.flags[3]"].String())
 	})
 }
@@ -6,6 +6,8 @@ import (
 	"encoding/base64"
 	"fmt"
 	"io"
+
+	internalapi "github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
 )
 
 func decodeIfBase64(data []byte) ([]byte, error) {
@@ -40,3 +42,36 @@ func decompressIfGZIP(data []byte) ([]byte, error) {
 	}
 	return data, nil
 }
+
+// parseEncodedConfig parses data that may be base64 encoded and GZIP
+// compressed, in the same manner as user data.
+func parseEncodedConfig(data []byte) (*internalapi.NodeConfig, error) {
+	data, err := decodeIfBase64(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode config: %v", err)
+	}
+	data, err = decompressIfGZIP(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress config: %v", err)
+	}
+	return ParseMaybeMultipart(data)
+}
+
+// parseEncodedConfigs parses the data of each of the named configs, in order,
+// and merges the results.
+func parseEncodedConfigs(names []string, getData func(name string) ([]byte, error)) (*internalapi.NodeConfig, error) {
+	var nodeConfigs []*internalapi.NodeConfig
+	for _, name := range names {
+		data, err := getData(name)
+		if err != nil {
+			return nil, err
+		}
+		config, err := parseEncodedConfig(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse config %s: %w", name, err)
+		}
+		config.SetOriginObject(name)
+		nodeConfigs = append(nodeConfigs, config)
+	}
+	return internalapi.MergeNodeConfigs(nodeConfigs)
+}
@@ -3,41 +3,136 @@ package configprovider
 import (
 	"fmt"
 	"net/url"
+	"strings"
+
+	"go.uber.org/zap"
+
+	internalapi "github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
 )
 
+type buildOptions struct {
+	cacheDir string
+	onConfig func(*internalapi.NodeConfig) error
+}
+
+type BuildOption func(*buildOptions)
+
+// WithCacheDir sets the directory in which config sources may keep what they
+// fetched, to avoid fetching it again when it has not changed.
+func WithCacheDir(cacheDir string) BuildOption {
+	return func(o *buildOptions) {
+		o.cacheDir = cacheDir
+	}
+}
+
+// WithConfigHook sets a function that the chain calls with the config of each
+// source as soon as it is provided, before the next source is evaluated.
+func WithConfigHook(onConfig func(*internalapi.NodeConfig) error) BuildOption {
+	return func(o *buildOptions) {
+		o.onConfig = onConfig
+	}
+}
+
 // BuildConfigProviderChain returns a ConfigProvider that evaluates multiple config sources, in the order specified, merging the result.
-func BuildConfigProviderChain(rawConfigSourceURLs []string) (ConfigProvider, error) {
+func BuildConfigProviderChain(rawConfigSourceURLs []string, opts ...BuildOption) (ConfigProvider, error) {
 	var providers []ConfigProvider
 	for _, configSource := range rawConfigSourceURLs {
-		provider, err := BuildConfigProvider(configSource)
+		provider, err := BuildConfigProvider(configSource, opts...)
 		if err != nil {
 			return nil, fmt.Errorf("failed to build provider from config source %q: %v", configSource, err)
 		}
 		providers = append(providers, provider)
 	}
-	return NewConfigProviderChain(providers), nil
+	var options buildOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	chain := NewConfigProviderChain(providers)
+	chain.sources = rawConfigSourceURLs
+	chain.onConfig = options.onConfig
+	return chain, nil
 }
 
 // BuildConfigProvider returns a ConfigProvider appropriate for the given source URL.
 // The source URL must have a scheme, and the supported schemes are:
 // - `file`. To use configuration from the filesystem: `file:///path/to/file/or/directory`.
-// - `imds`. To use configuration from the instance's user data: `imds://user-data`.
-func BuildConfigProvider(rawConfigSourceURL string) (ConfigProvider, error) {
+// - `imds`. To use configuration from the instance's user data: `imds://user-data`, or from the
+// well-known tags of the instance: `imds://tags`.
+// - `s3`. To use configuration from an S3 object, or from every object under a prefix ending with a slash:
+// `s3://bucket/path/to/object` or `s3://bucket/path/to/prefix/`. The bucket's region may be set with a
+// `region` query parameter, and otherwise defaults to the region of the instance.
+// - `ssm`. To use configuration from an SSM parameter, or from every parameter under a path ending with a slash:
+// `ssm://parameter-name` or `ssm:///path/to/parameters/`. A name with a slash is made absolute, as SSM requires, so
+// `ssm://path/to/parameter` is the same as `ssm:///path/to/parameter`.
+// - `secretsmanager`. To use configuration from a Secrets Manager secret, or from every secret under a name prefix
+// ending with a slash: `secretsmanager://secret-name` or `secretsmanager://prefix/`. A secret is referenced by ARN
+// without the slashes, as the colons of an ARN are not valid in a URL host: `secretsmanager:arn:aws:...`.
+// The `ssm` and `secretsmanager` schemes accept the same `region` query parameter as `s3`.
+// - `http` and `https`. To use configuration from an HTTP(S) endpoint: `https://example.com/path/to/config`.
+// A CA bundle and a bearer token file may be set in the URL fragment, which is not sent to the server:
+// `https://example.com/path/to/config#caBundle=/path/to/ca.pem&tokenFile=/path/to/token`.
+func BuildConfigProvider(rawConfigSourceURL string, opts ...BuildOption) (ConfigProvider, error) {
+	var options buildOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
 	parsedURL, err := url.Parse(rawConfigSourceURL)
 	if err != nil {
 		return nil, err
 	}
 	switch parsedURL.Scheme {
 	case "imds":
+		if parsedURL.Host == "tags" {
+			return NewInstanceTagsConfigProvider(), nil
+		}
 		return NewUserDataConfigProvider(), nil
 	case "file":
 		filePath := getURLWithoutScheme(parsedURL)
 		return NewFileConfigProvider(filePath), nil
+	case "s3":
+		if len(parsedURL.Host) == 0 {
+			return nil, fmt.Errorf("missing bucket name in S3 URL")
+		}
+		key := strings.TrimPrefix(parsedURL.Path, "/")
+		return NewS3ConfigProvider(parsedURL.Host, key, parsedURL.Query().Get("region")), nil
+	case "ssm":
+		name := getURLWithoutScheme(parsedURL)
+		if len(name) == 0 {
+			return nil, fmt.Errorf("missing parameter name in SSM URL")
+		}
+		// the names of parameters in a hierarchy must begin with a slash.
+		if strings.Contains(name, "/") && !strings.HasPrefix(name, "/") {
+			name = "/" + name
+		}
+		return NewSSMConfigProvider(name, parsedURL.Query().Get("region")), nil
+	case "secretsmanager":
+		secretID := getURLWithoutScheme(parsedURL)
+		if len(secretID) == 0 {
+			return nil, fmt.Errorf("missing secret ID in Secrets Manager URL")
+		}
+		return NewSecretsManagerConfigProvider(secretID, parsedURL.Query().Get("region")), nil
+	case "http", "https":
+		if len(parsedURL.Host) == 0 {
+			return nil, fmt.Errorf("missing host in HTTP URL")
+		}
+		endpointURL, caBundlePath, tokenPath, err := parseHTTPConfigSourceURL(parsedURL)
+		if err != nil {
+			return nil, err
+		}
+		if parsedURL.Scheme == "http" && len(tokenPath) > 0 {
+			zap.L().Warn("Sending bearer token to config source without TLS", zap.String("url", endpointURL))
+		}
+		return NewHTTPConfigProvider(endpointURL, caBundlePath, tokenPath, options.cacheDir), nil
 	default:
 		return nil, fmt.Errorf("unsupported scheme: %s", parsedURL.Scheme)
 	}
 }
 
 func getURLWithoutScheme(url *url.URL) string {
+	// URLs without slashes after the scheme, such as an ARN in
+	// `secretsmanager:arn:aws:...`, are opaque.
+	if len(url.Opaque) > 0 {
+		return url.Opaque
+	}
 	return fmt.Sprintf("%s%s", url.Host, url.Path)
 }
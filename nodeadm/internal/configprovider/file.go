@@ -55,7 +55,7 @@ func (fcs *fileConfigProvider) provideFromDirectory() (*internalapi.NodeConfig,
 			continue
 		}
 		name := entry.Name()
-		if fcs.isConfigFile(name) {
+		if isConfigFile(name) {
 			configFiles = append(configFiles, name)
 		}
 	}
@@ -69,6 +69,7 @@ func (fcs *fileConfigProvider) provideFromDirectory() (*internalapi.NodeConfig,
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse config file %s: %w", filename, err)
 		}
+		config.SetOriginObject(filename)
 		nodeConfigs = append(nodeConfigs, config)
 	}
 	if len(nodeConfigs) == 0 {
@@ -77,7 +78,9 @@ func (fcs *fileConfigProvider) provideFromDirectory() (*internalapi.NodeConfig,
 	return internalapi.MergeNodeConfigs(nodeConfigs)
 }
 
-func (fcs *fileConfigProvider) isConfigFile(filename string) bool {
+// isConfigFile returns true if the file name has an extension used for NodeConfig
+// documents.
+func isConfigFile(filename string) bool {
 	lower := strings.ToLower(filename)
 	for _, ext := range []string{".yaml", ".yml", ".json"} {
 		if strings.HasSuffix(lower, ext) {
@@ -109,8 +109,6 @@ spec:
 }
 
 func TestFileConfigProvider_isConfigFile(t *testing.T) {
-	provider := &fileConfigProvider{}
-
 	testCases := []struct {
 		filename string
 		expected bool
@@ -127,7 +125,7 @@ func TestFileConfigProvider_isConfigFile(t *testing.T) {
 	}
 
 	for _, tc := range testCases {
-		result := provider.isConfigFile(tc.filename)
+		result := isConfigFile(tc.filename)
 		if result != tc.expected {
 			t.Errorf("isConfigFile(%s) = %v, expected %v", tc.filename, result, tc.expected)
 		}
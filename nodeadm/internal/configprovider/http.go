@@ -0,0 +1,229 @@
+package configprovider
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	internalapi "github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/aws/imds"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/util"
+)
+
+const (
+	// httpMaxAttempts allows several minutes of exponential backoff to
+	// accommodate delays in network readiness.
+	httpMaxAttempts = 8
+	httpTimeout     = 30 * time.Second
+)
+
+type httpConfigProvider struct {
+	url string
+	// caBundlePath is the path to a PEM bundle of the certificate authorities
+	// trusted for the endpoint, in place of the system roots.
+	caBundlePath string
+	// tokenPath is the path to a file with a bearer token for the endpoint.
+	tokenPath string
+	// cacheDir is where the last response is kept to be reused when the
+	// endpoint reports that its ETag has not changed.
+	cacheDir   string
+	newRetrier func() *util.Retrier
+}
+
+// httpCacheEntry is the last successful response from a config endpoint.
+type httpCacheEntry struct {
+	URL  string `json:"url"`
+	ETag string `json:"etag"`
+	Data []byte `json:"data"`
+}
+
+// NewHTTPConfigProvider returns a ConfigProvider that reads config from an
+// HTTP(S) endpoint. An empty caBundlePath uses the system roots, an empty
+// tokenPath sends no Authorization header, and an empty cacheDir disables the
+// reuse of responses by ETag.
+func NewHTTPConfigProvider(url, caBundlePath, tokenPath, cacheDir string) ConfigProvider {
+	return &httpConfigProvider{
+		url:          url,
+		caBundlePath: caBundlePath,
+		tokenPath:    tokenPath,
+		cacheDir:     cacheDir,
+		newRetrier: func() *util.Retrier {
+			return util.NewRetrier(util.WithRetryCount(httpMaxAttempts))
+		},
+	}
+}
+
+func (p *httpConfigProvider) Provide() (*internalapi.NodeConfig, error) {
+	client, err := p.newClient()
+	if err != nil {
+		return nil, err
+	}
+	cached := p.loadCacheEntry()
+
+	var data []byte
+	var fetchErr error
+	zap.L().Info("Fetching config from HTTP endpoint", zap.String("url", p.url))
+	err = p.newRetrier().Retry(context.TODO(), func() error {
+		req, err := http.NewRequest(http.MethodGet, p.url, nil)
+		if err != nil {
+			fetchErr = err
+			return nil
+		}
+		if len(p.tokenPath) > 0 {
+			// the token is read on every attempt so that a rotated token is
+			// picked up.
+			// #nosec G304 // intended mechanism to read user-provided token file
+			token, err := os.ReadFile(p.tokenPath)
+			if err != nil {
+				fetchErr = fmt.Errorf("failed to read bearer token: %w", err)
+				return nil
+			}
+			req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+		}
+		if cached != nil {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		res, err := client.Do(req)
+		if err != nil {
+			var certErr *tls.CertificateVerificationError
+			if errors.As(err, &certErr) {
+				fetchErr = err
+				return nil
+			}
+			return err
+		}
+		defer res.Body.Close()
+		switch {
+		case res.StatusCode == http.StatusNotModified && cached != nil:
+			zap.L().Info("Reusing cached config from HTTP endpoint", zap.String("etag", cached.ETag))
+			data = cached.Data
+			fetchErr = nil
+			return nil
+		case res.StatusCode == http.StatusOK:
+			body, err := io.ReadAll(res.Body)
+			if err != nil {
+				return err
+			}
+			data = body
+			fetchErr = nil
+			p.saveCacheEntry(res.Header.Get("ETag"), body)
+			return nil
+		case res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500:
+			return fmt.Errorf("unexpected response from %s: %s", p.url, res.Status)
+		default:
+			// other responses won't change by trying again.
+			fetchErr = fmt.Errorf("unexpected response from %s: %s", p.url, res.Status)
+			return nil
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if fetchErr != nil {
+		return nil, fetchErr
+	}
+	return parseEncodedConfig(data)
+}
+
+func (p *httpConfigProvider) newClient() (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = imds.ProxyFromCurrentEnvironment
+	if len(p.caBundlePath) > 0 {
+		// #nosec G304 // intended mechanism to read user-provided CA bundle
+		caBundle, err := os.ReadFile(p.caBundlePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		rootCAs := x509.NewCertPool()
+		if !rootCAs.AppendCertsFromPEM(caBundle) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", p.caBundlePath)
+		}
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs:    rootCAs,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+	return &http.Client{
+		Transport: transport,
+		Timeout:   httpTimeout,
+	}, nil
+}
+
+func (p *httpConfigProvider) cachePath() string {
+	hash := sha256.Sum256([]byte(p.url))
+	return filepath.Join(p.cacheDir, "http-"+hex.EncodeToString(hash[:])+".json")
+}
+
+// loadCacheEntry returns the last response from the endpoint, or nil when
+// there is none that can be revalidated.
+func (p *httpConfigProvider) loadCacheEntry() *httpCacheEntry {
+	if len(p.cacheDir) == 0 {
+		return nil
+	}
+	// the entry is saved under the root dir, so it is read from there too.
+	data, err := os.ReadFile(util.RootedPath(p.cachePath()))
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			zap.L().Warn("Failed to read cached HTTP config", zap.Error(err))
+		}
+		return nil
+	}
+	var entry httpCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		zap.L().Warn("Failed to parse cached HTTP config", zap.Error(err))
+		return nil
+	}
+	if entry.URL != p.url || len(entry.ETag) == 0 {
+		return nil
+	}
+	return &entry
+}
+
+func (p *httpConfigProvider) saveCacheEntry(etag string, data []byte) {
+	if len(p.cacheDir) == 0 || len(etag) == 0 {
+		return
+	}
+	entry, err := json.Marshal(httpCacheEntry{URL: p.url, ETag: etag, Data: data})
+	if err != nil {
+		zap.L().Warn("Failed to encode HTTP config for the cache", zap.Error(err))
+		return
+	}
+	// the config may contain credentials, so it is only readable by root.
+	if err := util.WriteFileWithDir(p.cachePath(), entry, 0600); err != nil {
+		zap.L().Warn("Failed to cache HTTP config", zap.Error(err))
+	}
+}
+
+// parseHTTPConfigSourceURL splits the nodeadm options out of the fragment of
+// an HTTP(S) config source URL, since the fragment is never sent to the
+// server. The supported options are `caBundle` and `tokenFile`, for example:
+// `https://example.com/config.yaml#caBundle=/etc/pki/ca.pem&tokenFile=/run/token`.
+func parseHTTPConfigSourceURL(parsedURL *url.URL) (rawURL string, caBundlePath string, tokenPath string, err error) {
+	options, err := url.ParseQuery(parsedURL.Fragment)
+	if err != nil {
+		return "", "", "", fmt.Errorf("invalid options in URL fragment: %w", err)
+	}
+	for key := range options {
+		if key != "caBundle" && key != "tokenFile" {
+			return "", "", "", fmt.Errorf("unsupported option in URL fragment: %s", key)
+		}
+	}
+	endpointURL := *parsedURL
+	endpointURL.Fragment = ""
+	endpointURL.RawFragment = ""
+	return endpointURL.String(), options.Get("caBundle"), options.Get("tokenFile"), nil
+}
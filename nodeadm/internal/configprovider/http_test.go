@@ -0,0 +1,170 @@
+package configprovider
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/util"
+)
+
+const httpTestConfig = `{"apiVersion":"node.eks.aws/v1alpha1","kind":"NodeConfig","spec":{"cluster":{"name":"my-cluster"}}}`
+
+func newTestHTTPConfigProvider(url, caBundlePath, tokenPath, cacheDir string) *httpConfigProvider {
+	provider := NewHTTPConfigProvider(url, caBundlePath, tokenPath, cacheDir).(*httpConfigProvider)
+	provider.newRetrier = func() *util.Retrier {
+		return util.NewRetrier(util.WithRetryCount(3), util.WithBackoffFixed(time.Millisecond))
+	}
+	return provider
+}
+
+func TestHTTPConfigProvider(t *testing.T) {
+	t.Run("Plain", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(httpTestConfig))
+		}))
+		defer server.Close()
+		config, err := newTestHTTPConfigProvider(server.URL, "", "", "").Provide()
+		if assert.NoError(t, err) {
+			assert.Equal(t, "my-cluster", config.Spec.Cluster.Name)
+		}
+	})
+
+	t.Run("BearerToken", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer my-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(httpTestConfig))
+		}))
+		defer server.Close()
+		tokenPath := filepath.Join(t.TempDir(), "token")
+		assert.NoError(t, os.WriteFile(tokenPath, []byte("my-token\n"), 0600))
+
+		config, err := newTestHTTPConfigProvider(server.URL, "", tokenPath, "").Provide()
+		if assert.NoError(t, err) {
+			assert.Equal(t, "my-cluster", config.Spec.Cluster.Name)
+		}
+		_, err = newTestHTTPConfigProvider(server.URL, "", "", "").Provide()
+		assert.ErrorContains(t, err, "401")
+	})
+
+	t.Run("CABundle", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(httpTestConfig))
+		}))
+		defer server.Close()
+		caBundlePath := filepath.Join(t.TempDir(), "ca.pem")
+		caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+		assert.NoError(t, os.WriteFile(caBundlePath, caBundle, 0644))
+
+		config, err := newTestHTTPConfigProvider(server.URL, caBundlePath, "", "").Provide()
+		if assert.NoError(t, err) {
+			assert.Equal(t, "my-cluster", config.Spec.Cluster.Name)
+		}
+		// the test server's certificate is not trusted by the system roots.
+		_, err = newTestHTTPConfigProvider(server.URL, "", "", "").Provide()
+		assert.Error(t, err)
+	})
+
+	t.Run("ETag", func(t *testing.T) {
+		var requests, notModified int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				notModified++
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			_, _ = w.Write([]byte(httpTestConfig))
+		}))
+		defer server.Close()
+		cacheDir := t.TempDir()
+
+		for i := 0; i < 2; i++ {
+			config, err := newTestHTTPConfigProvider(server.URL, "", "", cacheDir).Provide()
+			if assert.NoError(t, err) {
+				assert.Equal(t, "my-cluster", config.Spec.Cluster.Name)
+			}
+		}
+		assert.Equal(t, 2, requests)
+		assert.Equal(t, 1, notModified)
+	})
+
+	t.Run("ETagRootDir", func(t *testing.T) {
+		var notModified int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				notModified++
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			_, _ = w.Write([]byte(httpTestConfig))
+		}))
+		defer server.Close()
+		util.SetRootDir(t.TempDir())
+		t.Cleanup(func() { util.SetRootDir("/") })
+
+		for i := 0; i < 2; i++ {
+			_, err := newTestHTTPConfigProvider(server.URL, "", "", "/var/cache/nodeadm").Provide()
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, 1, notModified)
+	})
+
+	t.Run("Retry", func(t *testing.T) {
+		var requests int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if requests < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write([]byte(httpTestConfig))
+		}))
+		defer server.Close()
+		config, err := newTestHTTPConfigProvider(server.URL, "", "", "").Provide()
+		if assert.NoError(t, err) {
+			assert.Equal(t, "my-cluster", config.Spec.Cluster.Name)
+		}
+		assert.Equal(t, 3, requests)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		var requests int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+		_, err := newTestHTTPConfigProvider(server.URL, "", "", "").Provide()
+		assert.ErrorContains(t, err, "404")
+		assert.Equal(t, 1, requests)
+	})
+}
+
+func TestBuildConfigProvider_HTTP(t *testing.T) {
+	provider, err := BuildConfigProvider("https://example.com/config.yaml?env=prod#caBundle=/etc/pki/ca.pem&tokenFile=/run/token", WithCacheDir("/var/cache/nodeadm"))
+	if assert.NoError(t, err) {
+		httpProvider, ok := provider.(*httpConfigProvider)
+		if assert.True(t, ok) {
+			assert.Equal(t, "https://example.com/config.yaml?env=prod", httpProvider.url)
+			assert.Equal(t, "/etc/pki/ca.pem", httpProvider.caBundlePath)
+			assert.Equal(t, "/run/token", httpProvider.tokenPath)
+			assert.Equal(t, "/var/cache/nodeadm", httpProvider.cacheDir)
+		}
+	}
+	_, err = BuildConfigProvider("https://example.com/config.yaml#unknown=true")
+	assert.Error(t, err)
+	_, err = BuildConfigProvider("http:///config.yaml")
+	assert.Error(t, err)
+}
@@ -27,13 +27,16 @@ func ParseMaybeMultipart(data []byte) (*internalapi.NodeConfig, error) {
 		if err != nil {
 			return nil, err
 		}
+		if err := config.RecordOrigin(internalapi.FieldOrigin{}); err != nil {
+			return nil, err
+		}
 		return config, nil
 	}
 }
 
 func ParseMultipart(userDataReader *multipart.Reader) (*internalapi.NodeConfig, error) {
 	var nodeConfigs []*internalapi.NodeConfig
-	for {
+	for partIndex := 1; ; partIndex++ {
 		part, err := userDataReader.NextPart()
 		if err == io.EOF {
 			break
@@ -63,6 +66,9 @@ func ParseMultipart(userDataReader *multipart.Reader) (*internalapi.NodeConfig,
 				if err != nil {
 					return nil, err
 				}
+				if err := decodedConfig.RecordOrigin(internalapi.FieldOrigin{Part: partIndex}); err != nil {
+					return nil, err
+				}
 				nodeConfigs = append(nodeConfigs, decodedConfig)
 			}
 		}
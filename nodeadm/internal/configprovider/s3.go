@@ -0,0 +1,117 @@
+package configprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"go.uber.org/zap"
+
+	internalapi "github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+)
+
+var (
+	ErrNoConfigInS3Prefix = errors.New("no config found under S3 prefix")
+)
+
+type s3API interface {
+	s3.ListObjectsV2APIClient
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+}
+
+type s3ConfigProvider struct {
+	bucket string
+	// key is either the key of a single object, or a prefix ending with a
+	// slash under which every config object is used.
+	key       string
+	newClient func(ctx context.Context) (s3API, error)
+}
+
+// NewS3ConfigProvider returns a ConfigProvider that reads config from an S3
+// object, or from every config object directly under a prefix when the key is
+// empty or ends with a slash. The region of the instance is used when no
+// region is given.
+func NewS3ConfigProvider(bucket, key, region string) ConfigProvider {
+	return &s3ConfigProvider{
+		bucket: bucket,
+		key:    key,
+		newClient: func(ctx context.Context) (s3API, error) {
+			awsConfig, err := loadAWSConfig(ctx, region)
+			if err != nil {
+				return nil, err
+			}
+			return s3.NewFromConfig(awsConfig, func(o *s3.Options) {
+				// S3-compatible endpoints generally do not support virtual
+				// hosted-style bucket addressing.
+				if o.BaseEndpoint != nil {
+					o.UsePathStyle = true
+				}
+			}), nil
+		},
+	}
+}
+
+func (p *s3ConfigProvider) Provide() (*internalapi.NodeConfig, error) {
+	ctx := context.TODO()
+	client, err := p.newClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+	if p.isPrefix() {
+		return p.provideFromPrefix(ctx, client)
+	}
+	data, err := p.getObject(ctx, client, p.key)
+	if err != nil {
+		return nil, err
+	}
+	return parseEncodedConfig(data)
+}
+
+func (p *s3ConfigProvider) isPrefix() bool {
+	return len(p.key) == 0 || strings.HasSuffix(p.key, "/")
+}
+
+func (p *s3ConfigProvider) getObject(ctx context.Context, client s3API, key string) ([]byte, error) {
+	zap.L().Info("Fetching config from S3", zap.String("bucket", p.bucket), zap.String("key", key))
+	res, err := client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(p.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	return io.ReadAll(res.Body)
+}
+
+func (p *s3ConfigProvider) provideFromPrefix(ctx context.Context, client s3API) (*internalapi.NodeConfig, error) {
+	var configKeys []string
+	// objects are listed in lexicographical order of their keys, which matches
+	// the order in which files are read from a directory.
+	paginator := s3.NewListObjectsV2Paginator(client, &s3.ListObjectsV2Input{
+		Bucket:    aws.String(p.bucket),
+		Prefix:    aws.String(p.key),
+		Delimiter: aws.String("/"),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, object := range page.Contents {
+			if key := aws.ToString(object.Key); isConfigFile(key) {
+				configKeys = append(configKeys, key)
+			}
+		}
+	}
+	if len(configKeys) == 0 {
+		return nil, ErrNoConfigInS3Prefix
+	}
+	return parseEncodedConfigs(configKeys, func(key string) ([]byte, error) {
+		return p.getObject(ctx, client, key)
+	})
+}
@@ -0,0 +1,167 @@
+package configprovider
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeS3Server is a minimal S3-compatible stand-in serving objects with
+// path-style addressing.
+type fakeS3Server struct {
+	// "bucket/key" -> content
+	objects map[string][]byte
+}
+
+type fakeListBucketResult struct {
+	XMLName     xml.Name `xml:"ListBucketResult"`
+	Name        string   `xml:"Name"`
+	Prefix      string   `xml:"Prefix"`
+	IsTruncated bool     `xml:"IsTruncated"`
+	KeyCount    int      `xml:"KeyCount"`
+	Contents    []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+}
+
+func (s *fakeS3Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if r.URL.Query().Get("list-type") == "2" {
+		prefix := r.URL.Query().Get("prefix")
+		result := fakeListBucketResult{Name: bucket, Prefix: prefix}
+		var keys []string
+		for objectPath := range s.objects {
+			objectBucket, objectKey, _ := strings.Cut(objectPath, "/")
+			if objectBucket == bucket && strings.HasPrefix(objectKey, prefix) && !strings.Contains(strings.TrimPrefix(objectKey, prefix), "/") {
+				keys = append(keys, objectKey)
+			}
+		}
+		sort.Strings(keys)
+		for _, objectKey := range keys {
+			result.Contents = append(result.Contents, struct {
+				Key string `xml:"Key"`
+			}{Key: objectKey})
+		}
+		result.KeyCount = len(keys)
+		w.Header().Set("Content-Type", "application/xml")
+		_ = xml.NewEncoder(w).Encode(result)
+		return
+	}
+	data, ok := s.objects[bucket+"/"+key]
+	if !ok {
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`))
+		return
+	}
+	_, _ = w.Write(data)
+}
+
+func newFakeS3Endpoint(t *testing.T, objects map[string][]byte) {
+	server := httptest.NewServer(&fakeS3Server{objects: objects})
+	t.Cleanup(server.Close)
+	t.Setenv("AWS_ENDPOINT_URL_S3", server.URL)
+	t.Setenv("AWS_REGION", "us-west-2")
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+}
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	_, err := writer.Write(data)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func TestS3ConfigProvider(t *testing.T) {
+	clusterConfig := linesToBytes(
+		"apiVersion: node.eks.aws/v1alpha1",
+		"kind: NodeConfig",
+		"spec:",
+		"  cluster:",
+		"    name: my-cluster",
+		"    apiServerEndpoint: https://example.com",
+	)
+	kubeletConfig := linesToBytes(
+		"apiVersion: node.eks.aws/v1alpha1",
+		"kind: NodeConfig",
+		"spec:",
+		"  kubelet:",
+		"    flags:",
+		"    - --v=2",
+	)
+	newFakeS3Endpoint(t, map[string][]byte{
+		"my-bucket/single.yaml":             clusterConfig,
+		"my-bucket/encoded.yaml":            []byte(base64.StdEncoding.EncodeToString(gzipBytes(t, clusterConfig))),
+		"my-bucket/prefix/a.yaml":           clusterConfig,
+		"my-bucket/prefix/b.json":           []byte(`{"apiVersion":"node.eks.aws/v1alpha1","kind":"NodeConfig","spec":{"cluster":{"name":"my-other-cluster"}}}`),
+		"my-bucket/prefix/c.yml":            kubeletConfig,
+		"my-bucket/prefix/ignored.txt":      []byte("ignored"),
+		"my-bucket/prefix/nested/d.yaml":    []byte("not a config"),
+		"my-bucket/empty-prefix/ignore.txt": []byte("ignored"),
+	})
+
+	t.Run("SingleObject", func(t *testing.T) {
+		config, err := NewS3ConfigProvider("my-bucket", "single.yaml", "").Provide()
+		if assert.NoError(t, err) {
+			assert.Equal(t, "my-cluster", config.Spec.Cluster.Name)
+		}
+	})
+
+	t.Run("EncodedObject", func(t *testing.T) {
+		config, err := NewS3ConfigProvider("my-bucket", "encoded.yaml", "").Provide()
+		if assert.NoError(t, err) {
+			assert.Equal(t, "https://example.com", config.Spec.Cluster.APIServerEndpoint)
+		}
+	})
+
+	t.Run("Prefix", func(t *testing.T) {
+		config, err := NewS3ConfigProvider("my-bucket", "prefix/", "").Provide()
+		if assert.NoError(t, err) {
+			assert.Equal(t, "my-other-cluster", config.Spec.Cluster.Name)
+			assert.Equal(t, "https://example.com", config.Spec.Cluster.APIServerEndpoint)
+			assert.Equal(t, []string{"--v=2"}, []string(config.Spec.Kubelet.Flags))
+		}
+	})
+
+	t.Run("MissingObject", func(t *testing.T) {
+		_, err := NewS3ConfigProvider("my-bucket", "missing.yaml", "").Provide()
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrNoConfigInS3Prefix)
+	})
+
+	t.Run("EmptyPrefixInChain", func(t *testing.T) {
+		chainProvider := configProviderChain{
+			providers: []ConfigProvider{NewS3ConfigProvider("my-bucket", "empty-prefix/", "")},
+		}
+		_, err := chainProvider.Provide()
+		assert.ErrorIs(t, err, ErrNoConfigInChain)
+	})
+}
+
+func TestBuildConfigProvider_S3(t *testing.T) {
+	provider, err := BuildConfigProvider("s3://my-bucket/path/to/prefix/?region=us-east-1")
+	if assert.NoError(t, err) {
+		s3Provider, ok := provider.(*s3ConfigProvider)
+		if assert.True(t, ok) {
+			assert.Equal(t, "my-bucket", s3Provider.bucket)
+			assert.Equal(t, "path/to/prefix/", s3Provider.key)
+			assert.True(t, s3Provider.isPrefix())
+		}
+	}
+	_, err = BuildConfigProvider("s3:///key.yaml")
+	assert.Error(t, err)
+}
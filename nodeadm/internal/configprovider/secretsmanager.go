@@ -0,0 +1,116 @@
+package configprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"go.uber.org/zap"
+
+	internalapi "github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+)
+
+var (
+	ErrNoConfigInSecretsManagerPrefix = errors.New("no config found under Secrets Manager prefix")
+)
+
+type secretsManagerAPI interface {
+	secretsmanager.ListSecretsAPIClient
+	GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error)
+}
+
+type secretsManagerConfigProvider struct {
+	// secretID is either the name or ARN of a single secret, or a name prefix
+	// ending with a slash under which every secret is used.
+	secretID  string
+	newClient func(ctx context.Context) (secretsManagerAPI, error)
+}
+
+// NewSecretsManagerConfigProvider returns a ConfigProvider that reads config
+// from a Secrets Manager secret, or from every secret directly under a name
+// prefix when the ID ends with a slash. The region of the instance is used
+// when no region is given.
+func NewSecretsManagerConfigProvider(secretID, region string) ConfigProvider {
+	return &secretsManagerConfigProvider{
+		secretID: secretID,
+		newClient: func(ctx context.Context) (secretsManagerAPI, error) {
+			awsConfig, err := loadAWSConfig(ctx, region)
+			if err != nil {
+				return nil, err
+			}
+			return secretsmanager.NewFromConfig(awsConfig), nil
+		},
+	}
+}
+
+func (p *secretsManagerConfigProvider) Provide() (*internalapi.NodeConfig, error) {
+	ctx := context.TODO()
+	client, err := p.newClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Secrets Manager client: %w", err)
+	}
+	if p.isPrefix() {
+		return p.provideFromPrefix(ctx, client)
+	}
+	data, err := p.getSecretValue(ctx, client, p.secretID)
+	if err != nil {
+		return nil, err
+	}
+	return parseEncodedConfig(data)
+}
+
+func (p *secretsManagerConfigProvider) isPrefix() bool {
+	return strings.HasSuffix(p.secretID, "/")
+}
+
+func (p *secretsManagerConfigProvider) getSecretValue(ctx context.Context, client secretsManagerAPI, secretID string) ([]byte, error) {
+	zap.L().Info("Fetching config from Secrets Manager", zap.String("secretId", secretID))
+	res, err := client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(secretID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res.SecretString != nil {
+		return []byte(*res.SecretString), nil
+	}
+	return res.SecretBinary, nil
+}
+
+func (p *secretsManagerConfigProvider) provideFromPrefix(ctx context.Context, client secretsManagerAPI) (*internalapi.NodeConfig, error) {
+	var names []string
+	paginator := secretsmanager.NewListSecretsPaginator(client, &secretsmanager.ListSecretsInput{
+		Filters: []types.Filter{
+			{
+				Key:    types.FilterNameStringTypeName,
+				Values: []string{p.secretID},
+			},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, secret := range page.SecretList {
+			name := aws.ToString(secret.Name)
+			// the name filter matches any secret with the prefix, but only the
+			// secrets directly under it are used, like an S3 prefix.
+			if suffix, ok := strings.CutPrefix(name, p.secretID); ok && len(suffix) > 0 && !strings.Contains(suffix, "/") {
+				names = append(names, name)
+			}
+		}
+	}
+	if len(names) == 0 {
+		return nil, ErrNoConfigInSecretsManagerPrefix
+	}
+	sort.Strings(names)
+	return parseEncodedConfigs(names, func(name string) ([]byte, error) {
+		return p.getSecretValue(ctx, client, name)
+	})
+}
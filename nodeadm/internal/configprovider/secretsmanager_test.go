@@ -0,0 +1,140 @@
+package configprovider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSecretsManagerClient struct {
+	secretStrings map[string]string
+	secretBinary  map[string][]byte
+}
+
+func (c *fakeSecretsManagerClient) GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	secretID := aws.ToString(params.SecretId)
+	if value, ok := c.secretStrings[secretID]; ok {
+		return &secretsmanager.GetSecretValueOutput{Name: params.SecretId, SecretString: aws.String(value)}, nil
+	}
+	if value, ok := c.secretBinary[secretID]; ok {
+		return &secretsmanager.GetSecretValueOutput{Name: params.SecretId, SecretBinary: value}, nil
+	}
+	return nil, &types.ResourceNotFoundException{Message: aws.String(secretID)}
+}
+
+func (c *fakeSecretsManagerClient) ListSecrets(ctx context.Context, params *secretsmanager.ListSecretsInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.ListSecretsOutput, error) {
+	prefix := params.Filters[0].Values[0]
+	var output secretsmanager.ListSecretsOutput
+	for name := range c.secretStrings {
+		if strings.HasPrefix(name, prefix) {
+			output.SecretList = append(output.SecretList, types.SecretListEntry{Name: aws.String(name)})
+		}
+	}
+	for name := range c.secretBinary {
+		if strings.HasPrefix(name, prefix) {
+			output.SecretList = append(output.SecretList, types.SecretListEntry{Name: aws.String(name)})
+		}
+	}
+	return &output, nil
+}
+
+func newFakeSecretsManagerConfigProvider(secretID string, client *fakeSecretsManagerClient) *secretsManagerConfigProvider {
+	return &secretsManagerConfigProvider{
+		secretID: secretID,
+		newClient: func(ctx context.Context) (secretsManagerAPI, error) {
+			return client, nil
+		},
+	}
+}
+
+func TestSecretsManagerConfigProvider(t *testing.T) {
+	clusterConfig := linesToBytes(
+		"apiVersion: node.eks.aws/v1alpha1",
+		"kind: NodeConfig",
+		"spec:",
+		"  cluster:",
+		"    name: my-cluster",
+		"    apiServerEndpoint: https://example.com",
+	)
+	client := &fakeSecretsManagerClient{
+		secretStrings: map[string]string{
+			"cluster":                string(clusterConfig),
+			"nodeadm/10-cluster":     string(clusterConfig),
+			"nodeadm/20-kubelet":     `{"apiVersion":"node.eks.aws/v1alpha1","kind":"NodeConfig","spec":{"cluster":{"name":"my-other-cluster"},"kubelet":{"flags":["--v=2"]}}}`,
+			"nodeadm/nested/30":      "not a config",
+			"nodeadm-other/10":       "not a config",
+			"empty/nested/10-config": "not a config",
+		},
+		secretBinary: map[string][]byte{
+			"binary": gzipBytes(t, clusterConfig),
+		},
+	}
+
+	t.Run("SecretString", func(t *testing.T) {
+		config, err := newFakeSecretsManagerConfigProvider("cluster", client).Provide()
+		if assert.NoError(t, err) {
+			assert.Equal(t, "my-cluster", config.Spec.Cluster.Name)
+		}
+	})
+
+	t.Run("SecretBinary", func(t *testing.T) {
+		config, err := newFakeSecretsManagerConfigProvider("binary", client).Provide()
+		if assert.NoError(t, err) {
+			assert.Equal(t, "https://example.com", config.Spec.Cluster.APIServerEndpoint)
+		}
+	})
+
+	t.Run("Prefix", func(t *testing.T) {
+		config, err := newFakeSecretsManagerConfigProvider("nodeadm/", client).Provide()
+		if assert.NoError(t, err) {
+			assert.Equal(t, "my-other-cluster", config.Spec.Cluster.Name)
+			assert.Equal(t, "https://example.com", config.Spec.Cluster.APIServerEndpoint)
+			assert.Equal(t, []string{"--v=2"}, []string(config.Spec.Kubelet.Flags))
+		}
+	})
+
+	t.Run("MissingSecret", func(t *testing.T) {
+		_, err := newFakeSecretsManagerConfigProvider("missing", client).Provide()
+		var notFound *types.ResourceNotFoundException
+		assert.ErrorAs(t, err, &notFound)
+	})
+
+	t.Run("EmptyPrefixInChain", func(t *testing.T) {
+		chainProvider := configProviderChain{
+			providers: []ConfigProvider{newFakeSecretsManagerConfigProvider("empty/", client)},
+		}
+		_, err := chainProvider.Provide()
+		assert.ErrorIs(t, err, ErrNoConfigInChain)
+	})
+}
+
+func TestBuildConfigProvider_SecretsManager(t *testing.T) {
+	provider, err := BuildConfigProvider("secretsmanager://nodeadm/?region=us-east-1")
+	if assert.NoError(t, err) {
+		secretsManagerProvider, ok := provider.(*secretsManagerConfigProvider)
+		if assert.True(t, ok) {
+			assert.Equal(t, "nodeadm/", secretsManagerProvider.secretID)
+			assert.True(t, secretsManagerProvider.isPrefix())
+		}
+	}
+	provider, err = BuildConfigProvider("secretsmanager:arn:aws:secretsmanager:us-west-2:111122223333:secret:nodeadm-a1b2c3")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "arn:aws:secretsmanager:us-west-2:111122223333:secret:nodeadm-a1b2c3", provider.(*secretsManagerConfigProvider).secretID)
+	}
+	provider, err = BuildConfigProvider("secretsmanager://my-cluster/nodeadm-secret")
+	if assert.NoError(t, err) {
+		secretsManagerProvider := provider.(*secretsManagerConfigProvider)
+		assert.Equal(t, "my-cluster/nodeadm-secret", secretsManagerProvider.secretID)
+		assert.False(t, secretsManagerProvider.isPrefix())
+	}
+	// an ARN is not a valid URL host, so it must be opaque
+	_, err = BuildConfigProvider("secretsmanager://arn:aws:secretsmanager:us-west-2:111122223333:secret:nodeadm-a1b2c3")
+	assert.Error(t, err)
+	_, err = BuildConfigProvider("secretsmanager://")
+	assert.Error(t, err)
+}
@@ -0,0 +1,116 @@
+package configprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"go.uber.org/zap"
+
+	internalapi "github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+)
+
+var (
+	ErrNoConfigInSSMPath = errors.New("no config found under SSM parameter path")
+)
+
+type ssmAPI interface {
+	ssm.GetParametersByPathAPIClient
+	GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
+}
+
+type ssmConfigProvider struct {
+	// name is either the name of a single parameter, or a path ending with a
+	// slash under which every parameter is used.
+	name      string
+	newClient func(ctx context.Context) (ssmAPI, error)
+}
+
+// NewSSMConfigProvider returns a ConfigProvider that reads config from an SSM
+// parameter, or from every parameter directly under a path when the name ends
+// with a slash. The region of the instance is used when no region is given.
+func NewSSMConfigProvider(name, region string) ConfigProvider {
+	return &ssmConfigProvider{
+		name: name,
+		newClient: func(ctx context.Context) (ssmAPI, error) {
+			awsConfig, err := loadAWSConfig(ctx, region)
+			if err != nil {
+				return nil, err
+			}
+			return ssm.NewFromConfig(awsConfig), nil
+		},
+	}
+}
+
+func (p *ssmConfigProvider) Provide() (*internalapi.NodeConfig, error) {
+	ctx := context.TODO()
+	client, err := p.newClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SSM client: %w", err)
+	}
+	if p.isPath() {
+		return p.provideFromPath(ctx, client)
+	}
+	zap.L().Info("Fetching config from SSM parameter", zap.String("name", p.name))
+	res, err := client.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(p.name),
+		WithDecryption: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseEncodedConfig([]byte(aws.ToString(res.Parameter.Value)))
+}
+
+func (p *ssmConfigProvider) isPath() bool {
+	return strings.HasSuffix(p.name, "/")
+}
+
+func (p *ssmConfigProvider) provideFromPath(ctx context.Context, client ssmAPI) (*internalapi.NodeConfig, error) {
+	zap.L().Info("Fetching config from SSM parameter path", zap.String("path", p.name))
+	values := map[string]string{}
+	paginator := ssm.NewGetParametersByPathPaginator(client, &ssm.GetParametersByPathInput{
+		// the path of a parameter hierarchy must not end with a slash, except
+		// for the root.
+		Path:           aws.String(pathWithoutTrailingSlash(p.name)),
+		Recursive:      aws.Bool(false),
+		WithDecryption: aws.Bool(true),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, parameter := range page.Parameters {
+			values[aws.ToString(parameter.Name)] = aws.ToString(parameter.Value)
+		}
+	}
+	if len(values) == 0 {
+		return nil, ErrNoConfigInSSMPath
+	}
+	return parseEncodedConfigs(sortedKeys(values), func(name string) ([]byte, error) {
+		return []byte(values[name]), nil
+	})
+}
+
+func pathWithoutTrailingSlash(path string) string {
+	if path == "/" {
+		return path
+	}
+	return strings.TrimSuffix(path, "/")
+}
+
+// sortedKeys returns the keys of the map in lexicographical order, which
+// matches the order in which files are read from a directory.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,155 @@
+package configprovider
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/stretchr/testify/assert"
+
+	internalapi "github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+)
+
+type fakeSSMClient struct {
+	parameters map[string]string
+}
+
+func (c *fakeSSMClient) GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+	name := aws.ToString(params.Name)
+	value, ok := c.parameters[name]
+	if !ok {
+		return nil, &types.ParameterNotFound{Message: aws.String(name)}
+	}
+	return &ssm.GetParameterOutput{
+		Parameter: &types.Parameter{Name: params.Name, Value: aws.String(value)},
+	}, nil
+}
+
+func (c *fakeSSMClient) GetParametersByPath(ctx context.Context, params *ssm.GetParametersByPathInput, optFns ...func(*ssm.Options)) (*ssm.GetParametersByPathOutput, error) {
+	path := aws.ToString(params.Path)
+	if !strings.HasSuffix(path, "/") {
+		path += "/"
+	}
+	var output ssm.GetParametersByPathOutput
+	for name, value := range c.parameters {
+		if suffix, ok := strings.CutPrefix(name, path); ok && !strings.Contains(suffix, "/") {
+			output.Parameters = append(output.Parameters, types.Parameter{Name: aws.String(name), Value: aws.String(value)})
+		}
+	}
+	return &output, nil
+}
+
+func newFakeSSMConfigProvider(name string, client *fakeSSMClient) *ssmConfigProvider {
+	return &ssmConfigProvider{
+		name: name,
+		newClient: func(ctx context.Context) (ssmAPI, error) {
+			return client, nil
+		},
+	}
+}
+
+func TestSSMConfigProvider(t *testing.T) {
+	client := &fakeSSMClient{
+		parameters: map[string]string{
+			"cluster": string(linesToBytes(
+				"apiVersion: node.eks.aws/v1alpha1",
+				"kind: NodeConfig",
+				"spec:",
+				"  cluster:",
+				"    name: my-cluster",
+			)),
+			"/eks/nodeadm/10-cluster": `{"apiVersion":"node.eks.aws/v1alpha1","kind":"NodeConfig","spec":{"cluster":{"name":"my-cluster","apiServerEndpoint":"https://example.com"}}}`,
+			"/eks/nodeadm/20-kubelet": `{"apiVersion":"node.eks.aws/v1alpha1","kind":"NodeConfig","spec":{"cluster":{"name":"my-other-cluster"},"kubelet":{"flags":["--v=2"]}}}`,
+			"/eks/nodeadm/nested/30":  "not a config",
+			"/eks/empty/nested/10":    "not a config",
+		},
+	}
+
+	t.Run("SingleParameter", func(t *testing.T) {
+		config, err := newFakeSSMConfigProvider("cluster", client).Provide()
+		if assert.NoError(t, err) {
+			assert.Equal(t, "my-cluster", config.Spec.Cluster.Name)
+		}
+	})
+
+	t.Run("Path", func(t *testing.T) {
+		config, err := newFakeSSMConfigProvider("/eks/nodeadm/", client).Provide()
+		if assert.NoError(t, err) {
+			assert.Equal(t, "my-other-cluster", config.Spec.Cluster.Name)
+			assert.Equal(t, "https://example.com", config.Spec.Cluster.APIServerEndpoint)
+			assert.Equal(t, []string{"--v=2"}, []string(config.Spec.Kubelet.Flags))
+		}
+	})
+
+	t.Run("MissingParameter", func(t *testing.T) {
+		_, err := newFakeSSMConfigProvider("missing", client).Provide()
+		var notFound *types.ParameterNotFound
+		assert.ErrorAs(t, err, &notFound)
+	})
+
+	t.Run("EmptyPathInChain", func(t *testing.T) {
+		chainProvider := configProviderChain{
+			providers: []ConfigProvider{newFakeSSMConfigProvider("/eks/empty/", client)},
+		}
+		_, err := chainProvider.Provide()
+		assert.ErrorIs(t, err, ErrNoConfigInChain)
+	})
+}
+
+func TestSSMConfigProvider_ConfigHookBeforeLaterSource(t *testing.T) {
+	t.Setenv("HTTPS_PROXY", "")
+	client := &fakeSSMClient{
+		parameters: map[string]string{
+			"proxy": `{"apiVersion":"node.eks.aws/v1alpha1","kind":"NodeConfig","spec":{"instance":{"environment":{"default":{"HTTPS_PROXY":"http://proxy.example.com:3128"}}}}}`,
+		},
+	}
+	var proxyWhenCreatingClient string
+	later := &ssmConfigProvider{
+		name: "cluster",
+		newClient: func(ctx context.Context) (ssmAPI, error) {
+			proxyWhenCreatingClient = os.Getenv("HTTPS_PROXY")
+			return &fakeSSMClient{parameters: map[string]string{
+				"cluster": `{"apiVersion":"node.eks.aws/v1alpha1","kind":"NodeConfig","spec":{"cluster":{"name":"my-cluster"}}}`,
+			}}, nil
+		},
+	}
+	chainProvider := configProviderChain{
+		providers: []ConfigProvider{newFakeSSMConfigProvider("proxy", client), later},
+		onConfig: func(config *internalapi.NodeConfig) error {
+			for name, value := range config.Spec.Instance.Environment["default"] {
+				t.Setenv(name, value)
+			}
+			return nil
+		},
+	}
+	config, err := chainProvider.Provide()
+	if assert.NoError(t, err) {
+		assert.Equal(t, "my-cluster", config.Spec.Cluster.Name)
+		assert.Equal(t, "http://proxy.example.com:3128", proxyWhenCreatingClient)
+	}
+}
+
+func TestBuildConfigProvider_SSM(t *testing.T) {
+	provider, err := BuildConfigProvider("ssm:///eks/nodeadm/?region=us-east-1")
+	if assert.NoError(t, err) {
+		ssmProvider, ok := provider.(*ssmConfigProvider)
+		if assert.True(t, ok) {
+			assert.Equal(t, "/eks/nodeadm/", ssmProvider.name)
+			assert.True(t, ssmProvider.isPath())
+		}
+	}
+	provider, err = BuildConfigProvider("ssm://cluster-config")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "cluster-config", provider.(*ssmConfigProvider).name)
+	}
+	provider, err = BuildConfigProvider("ssm://env/kubelet")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "/env/kubelet", provider.(*ssmConfigProvider).name)
+	}
+	_, err = BuildConfigProvider("ssm://")
+	assert.Error(t, err)
+}
@@ -0,0 +1,176 @@
+package configprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	internalapi "github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+	imds "github.com/awslabs/amazon-eks-ami/nodeadm/internal/aws/imds"
+)
+
+var (
+	// represents when none of the instance tags map to a NodeConfig, including
+	// when instance tags are not available in instance metadata.
+	ErrNoConfigInInstanceTags = errors.New("no config found in instance tags")
+)
+
+// Tag keys in instance metadata cannot contain slashes, so the parts of the
+// well-known keys are separated by colons.
+const (
+	instanceTagPrefix = "node.eks.aws:"
+	// a NodeConfig document, which may be base64 encoded and GZIP compressed.
+	instanceTagNodeConfig = instanceTagPrefix + "nodeconfig"
+	// comma-separated `key=value` node labels.
+	instanceTagLabels = instanceTagPrefix + "labels"
+	// comma-separated `key=value:Effect` node taints.
+	instanceTagTaints = instanceTagPrefix + "taints"
+	// comma-separated `Feature=bool` nodeadm feature gates.
+	instanceTagFeatureGates = instanceTagPrefix + "feature-gates"
+	// the value of the kubelet flag named by the rest of the key.
+	instanceTagKubeletFlagPrefix = instanceTagPrefix + "kubelet-flag:"
+)
+
+type instanceTagsProvider interface {
+	GetInstanceTags(context.Context) (map[string]string, error)
+}
+
+type instanceTagsConfigProvider struct {
+	instanceTagsProvider instanceTagsProvider
+}
+
+// NewInstanceTagsConfigProvider returns a ConfigProvider that maps the
+// well-known tags of the instance, read from instance metadata, to a
+// NodeConfig.
+func NewInstanceTagsConfigProvider() ConfigProvider {
+	return &instanceTagsConfigProvider{
+		instanceTagsProvider: imds.DefaultClient(),
+	}
+}
+
+func (p *instanceTagsConfigProvider) Provide() (*internalapi.NodeConfig, error) {
+	tags, err := p.instanceTagsProvider.GetInstanceTags(context.TODO())
+	if err != nil {
+		if errors.Is(err, imds.ErrInstanceTagsUnavailable) {
+			return nil, fmt.Errorf("%w: %w", ErrNoConfigInInstanceTags, err)
+		}
+		return nil, err
+	}
+	return nodeConfigFromInstanceTags(tags)
+}
+
+func nodeConfigFromInstanceTags(tags map[string]string) (*internalapi.NodeConfig, error) {
+	var nodeConfigs []*internalapi.NodeConfig
+	// the document is merged first, so that the more specific tags take
+	// precedence over it.
+	if document, ok := tags[instanceTagNodeConfig]; ok {
+		config, err := parseEncodedConfig([]byte(document))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse config in tag %s: %w", instanceTagNodeConfig, err)
+		}
+		config.SetOriginObject(instanceTagNodeConfig)
+		nodeConfigs = append(nodeConfigs, config)
+	}
+
+	var config internalapi.NodeConfig
+	var found bool
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	// kubelet flags are added in a stable order.
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := tags[key]
+		switch {
+		case !strings.HasPrefix(key, instanceTagPrefix), key == instanceTagNodeConfig:
+			continue
+		case key == instanceTagLabels:
+			labels, err := parseLabels(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value of tag %s: %w", key, err)
+			}
+			config.Spec.Kubelet.Labels = labels
+		case key == instanceTagTaints:
+			taints, err := parseTaints(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value of tag %s: %w", key, err)
+			}
+			config.Spec.Kubelet.Taints = taints
+		case key == instanceTagFeatureGates:
+			featureGates, err := parseFeatureGates(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value of tag %s: %w", key, err)
+			}
+			config.Spec.FeatureGates = featureGates
+		case strings.HasPrefix(key, instanceTagKubeletFlagPrefix):
+			flagName := strings.TrimPrefix(key, instanceTagKubeletFlagPrefix)
+			config.Spec.Kubelet.Flags = append(config.Spec.Kubelet.Flags, fmt.Sprintf("--%s=%s", flagName, value))
+		default:
+			zap.L().Warn("Ignoring unknown instance tag", zap.String("key", key))
+			continue
+		}
+		found = true
+	}
+	if found {
+		if err := config.RecordOrigin(internalapi.FieldOrigin{}); err != nil {
+			return nil, err
+		}
+		nodeConfigs = append(nodeConfigs, &config)
+	}
+
+	if len(nodeConfigs) == 0 {
+		return nil, ErrNoConfigInInstanceTags
+	}
+	return internalapi.MergeNodeConfigs(nodeConfigs)
+}
+
+func parseFeatureGates(value string) (map[internalapi.Feature]bool, error) {
+	featureGates := make(map[internalapi.Feature]bool)
+	for _, featureGate := range strings.Split(value, ",") {
+		name, rawEnabled, ok := strings.Cut(strings.TrimSpace(featureGate), "=")
+		if !ok {
+			return nil, fmt.Errorf("feature gate %q is not in the form Feature=bool", featureGate)
+		}
+		enabled, err := strconv.ParseBool(rawEnabled)
+		if err != nil {
+			return nil, fmt.Errorf("feature gate %q is not in the form Feature=bool", featureGate)
+		}
+		featureGates[internalapi.Feature(name)] = enabled
+	}
+	return featureGates, nil
+}
+
+func parseLabels(value string) (map[string]string, error) {
+	labels := make(map[string]string)
+	for _, label := range strings.Split(value, ",") {
+		key, labelValue, ok := strings.Cut(strings.TrimSpace(label), "=")
+		if !ok {
+			return nil, fmt.Errorf("label %q is not in the form key=value", label)
+		}
+		labels[key] = labelValue
+	}
+	return labels, nil
+}
+
+func parseTaints(value string) ([]internalapi.Taint, error) {
+	var taints []internalapi.Taint
+	for _, taint := range strings.Split(value, ",") {
+		keyValue, effect, ok := strings.Cut(strings.TrimSpace(taint), ":")
+		if !ok {
+			return nil, fmt.Errorf("taint %q is not in the form key=value:Effect", taint)
+		}
+		key, taintValue, _ := strings.Cut(keyValue, "=")
+		taints = append(taints, internalapi.Taint{
+			Key:    key,
+			Value:  taintValue,
+			Effect: internalapi.TaintEffect(effect),
+		})
+	}
+	return taints, nil
+}
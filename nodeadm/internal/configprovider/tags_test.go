@@ -0,0 +1,122 @@
+package configprovider
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	internalapi "github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/aws/imds"
+)
+
+func TestInstanceTagsConfigProvider(t *testing.T) {
+	encodedConfig := base64.StdEncoding.EncodeToString([]byte(`{"apiVersion":"node.eks.aws/v1alpha1","kind":"NodeConfig","spec":{"cluster":{"name":"my-cluster"},"kubelet":{"flags":["--v=2"]},"featureGates":{"FastImagePull":true}}}`))
+
+	testCases := []struct {
+		scenario     string
+		tags         map[string]string
+		tagsErr      error
+		expectedSpec *internalapi.NodeConfigSpec
+		expectedErr  error
+	}{
+		{
+			scenario: "well-known tags are mapped to the NodeConfig",
+			tags: map[string]string{
+				"Name":                          "my-node",
+				"node.eks.aws:labels":           "role=worker,example.com/team=a",
+				"node.eks.aws:taints":           "dedicated=gpu:NoSchedule",
+				"node.eks.aws:feature-gates":    "InstanceIdNodeName=true, FastImagePull=false",
+				"node.eks.aws:kubelet-flag:v":   "5",
+				"node.eks.aws:unknown":          "ignored",
+				"node.eks.aws:kubelet-flag:a-b": "c",
+			},
+			expectedSpec: &internalapi.NodeConfigSpec{
+				FeatureGates: map[internalapi.Feature]bool{
+					internalapi.InstanceIdNodeName: true,
+					internalapi.FastImagePull:      false,
+				},
+				Kubelet: internalapi.KubeletOptions{
+					Flags: []string{"--a-b=c", "--v=5"},
+					Labels: map[string]string{
+						"role":             "worker",
+						"example.com/team": "a",
+					},
+					Taints: []internalapi.Taint{
+						{Key: "dedicated", Value: "gpu", Effect: internalapi.TaintEffectNoSchedule},
+					},
+				},
+			},
+		},
+		{
+			scenario: "tags take precedence over the config document",
+			tags: map[string]string{
+				"node.eks.aws:nodeconfig":     encodedConfig,
+				"node.eks.aws:kubelet-flag:v": "5",
+			},
+			expectedSpec: &internalapi.NodeConfigSpec{
+				Cluster:      internalapi.ClusterDetails{Name: "my-cluster"},
+				FeatureGates: map[internalapi.Feature]bool{internalapi.FastImagePull: true},
+				Kubelet: internalapi.KubeletOptions{
+					Flags: []string{"--v=2", "--v=5"},
+				},
+			},
+		},
+		{
+			scenario:    "no well-known tags",
+			tags:        map[string]string{"Name": "my-node"},
+			expectedErr: ErrNoConfigInInstanceTags,
+		},
+		{
+			scenario:    "tags not available in instance metadata",
+			tagsErr:     imds.ErrInstanceTagsUnavailable,
+			expectedErr: ErrNoConfigInInstanceTags,
+		},
+		{
+			scenario: "invalid labels",
+			tags:     map[string]string{"node.eks.aws:labels": "role"},
+		},
+		{
+			scenario: "invalid taints",
+			tags:     map[string]string{"node.eks.aws:taints": "dedicated=gpu"},
+		},
+		{
+			scenario: "invalid feature gates",
+			tags:     map[string]string{"node.eks.aws:feature-gates": "InstanceIdNodeName"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.scenario, func(t *testing.T) {
+			imdsClient := imds.FakeIMDSClient{
+				GetInstanceTagsFunc: func(ctx context.Context) (map[string]string, error) {
+					return testCase.tags, testCase.tagsErr
+				},
+			}
+			provider := &instanceTagsConfigProvider{instanceTagsProvider: &imdsClient}
+			config, err := provider.Provide()
+			if testCase.expectedSpec == nil {
+				assert.Error(t, err)
+				if testCase.expectedErr != nil {
+					assert.ErrorIs(t, err, testCase.expectedErr)
+				}
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, *testCase.expectedSpec, config.Spec)
+			}
+		})
+	}
+}
+
+func TestBuildConfigProvider_InstanceTags(t *testing.T) {
+	provider, err := BuildConfigProvider("imds://tags")
+	if assert.NoError(t, err) {
+		assert.IsType(t, &instanceTagsConfigProvider{}, provider)
+	}
+	provider, err = BuildConfigProvider("imds://user-data")
+	if assert.NoError(t, err) {
+		assert.IsType(t, &userDataConfigProvider{}, provider)
+	}
+}
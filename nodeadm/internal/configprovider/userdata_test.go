@@ -254,6 +254,8 @@ func Test_Provide(t *testing.T) {
 			} else {
 				assert.Nil(t, err)
 				if assert.NotNil(t, actualNodeConfig) {
+					// origins are covered by TestProvideOrigins
+					actualNodeConfig.Origins = nil
 					assert.Equal(t, testCase.expectedNodeConfig, *actualNodeConfig)
 				}
 			}
@@ -261,6 +263,58 @@ func Test_Provide(t *testing.T) {
 	}
 }
 
+func TestProvideOrigins(t *testing.T) {
+	imdsClient := imds.FakeIMDSClient{
+		GetUserDataFunc: func(ctx context.Context) ([]byte, error) {
+			return linesToBytes(
+				"MIME-Version: 1.0",
+				`Content-Type: multipart/mixed; boundary="BOUNDARY"`,
+				"",
+				"--BOUNDARY",
+				"Content-Type: text/x-shellscript",
+				"",
+				"#!/bin/bash",
+				"--BOUNDARY",
+				"Content-Type: application/node.eks.aws",
+				"",
+				"---",
+				"apiVersion: node.eks.aws/v1alpha1",
+				"kind: NodeConfig",
+				"spec:",
+				"  cluster:",
+				"    name: my-cluster",
+				"  kubelet:",
+				"    config:",
+				"      maxPods: 120",
+				"",
+				"--BOUNDARY",
+				"Content-Type: application/node.eks.aws",
+				"",
+				"---",
+				"apiVersion: node.eks.aws/v1alpha1",
+				"kind: NodeConfig",
+				"spec:",
+				"  kubelet:",
+				"    config:",
+				"      maxPods: 150",
+				"",
+				"--BOUNDARY--",
+			), nil
+		},
+	}
+	chainProvider := configProviderChain{
+		providers: []ConfigProvider{&userDataConfigProvider{userDataProvider: &imdsClient}},
+		sources:   []string{"imds://user-data"},
+	}
+	config, err := chainProvider.Provide()
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string]api.FieldOrigin{
+			"spec.cluster.name":           {Source: "imds://user-data", Part: 2},
+			"spec.kubelet.config.maxPods": {Source: "imds://user-data", Part: 3},
+		}, config.Origins)
+	}
+}
+
 func linesToBytes(lines ...string) []byte {
 	var buf bytes.Buffer
 	for i, line := range lines {
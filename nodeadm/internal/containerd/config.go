@@ -4,8 +4,6 @@ import (
 	"bytes"
 	_ "embed"
 	"fmt"
-	"os/exec"
-	"strings"
 	"text/template"
 
 	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
@@ -14,6 +12,7 @@ import (
 	"github.com/pelletier/go-toml/v2"
 	"go.uber.org/zap"
 	"golang.org/x/mod/semver"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 const ContainerRuntimeEndpoint = "unix:///run/containerd/containerd.sock"
@@ -22,6 +21,7 @@ const (
 	containerdConfigFile      = "/etc/containerd/config.toml"
 	sociSnapshotterConfigFile = "/etc/soci-snapshotter-grpc/config.toml"
 	configPerm                = 0644
+	sociSnapshotter           = "soci"
 )
 
 var (
@@ -30,8 +30,8 @@ var (
 	//go:embed config2.template.toml
 	containerdConfigTemplateData2 string
 
-	//go:embed snapshotter/soci-snapshotter.config.toml
-	sociSnapshotterTemplateData []byte
+	//go:embed snapshotter/soci-snapshotter.config.template.toml
+	sociSnapshotterTemplateData string
 )
 
 type ConfigSchema string
@@ -51,8 +51,9 @@ var containerdTemplateVersionMap = map[ConfigSchema]string{
 type containerdTemplateVars struct {
 	EnableCDI          bool
 	SandboxImage       string
-	RuntimeName        string
-	RuntimeBinaryName  string
+	PinnedImages       map[string]string
+	DefaultRuntimeName string
+	Runtimes           []runtimeConfig
 	UseSOCISnapshotter bool
 }
 
@@ -65,30 +66,38 @@ func writeContainerdConfig(cfg *api.NodeConfig, resources system.Resources) erro
 	if err != nil {
 		return err
 	}
-	containerdConfig, err := generateContainerdConfig(cfg, resources, templateVersion)
+	// a V2 config passed in NodeConfig when using containerd 2.* is migrated
+	// to V3 before it is merged, so that the merged config is always V3.
+	userConfig, err := migrateUserConfig(cfg.Spec.Containerd.Config, templateVersion)
 	if err != nil {
 		return err
 	}
-	containerdConfig, err = combineContainerdConfigs(containerdConfig, cfg.Spec.Containerd.Config)
+	containerdConfig, err := generateContainerdConfig(cfg, resources, templateVersion)
 	if err != nil {
 		return err
 	}
-
-	zap.L().Info("Writing containerd config to file..", zap.String("path", containerdConfigFile))
-	err = util.WriteFileWithDir(containerdConfigFile, containerdConfig, configPerm)
+	containerdConfig, err = combineTOMLConfigs(containerdConfig, []byte(userConfig))
 	if err != nil {
 		return err
 	}
-	// configuration V3 template will be used for containerd 2.* by default, unless there are configuration V2 property passed in NodeConfig,
-	// then need to run containerd config migrate. Need to run after write file because it only work for what already in the config file.
-	if isContainerdV2 && templateVersion == ConfigSchemaV2 {
-		zap.L().Info("Migrate containerd config to V3..", zap.String("path", containerdConfigFile))
-		return migrateConfig()
+	// the containerd config of the NodeConfig was validated on its own, so
+	// this also checks the runtimes of the NodeConfig in the template, and the
+	// config after it is migrated to the version of the template.
+	errs, warnings := validateMergedConfig(cfg, containerdConfig, templateVersion)
+	for _, warning := range warnings {
+		zap.L().Warn("Questionable merged containerd config", zap.String("field", warning.Field), zap.String("warning", warning.ErrorBody()))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid merged containerd config: %w", errs.ToAggregate())
 	}
-	return nil
+
+	zap.L().Info("Writing containerd config to file..", zap.String("path", containerdConfigFile))
+	return util.WriteFileWithDir(containerdConfigFile, containerdConfig, configPerm)
 }
 
-func combineContainerdConfigs(configA []byte, configB api.ContainerdConfig) ([]byte, error) {
+// combineTOMLConfigs merges configB into configA, which are the TOML configs
+// of containerd or of the SOCI snapshotter.
+func combineTOMLConfigs(configA []byte, configB []byte) ([]byte, error) {
 	// because the logic in containerd's import merge decides to completely
 	// overwrite entire sections, we want to implement this merging ourselves.
 	// see: https://github.com/containerd/containerd/blob/a91b05d99ceac46329be06eb43f7ae10b89aad45/cmd/containerd/server/config/config.go#L407-L431
@@ -96,22 +105,26 @@ func combineContainerdConfigs(configA []byte, configB api.ContainerdConfig) ([]b
 		return configA, nil
 	}
 
-	containerdConfigMap, err := util.Merge(configA, []byte(configB), toml.Marshal, toml.Unmarshal)
+	configMap, err := util.Merge(configA, configB, toml.Marshal, toml.Unmarshal)
 	if err != nil {
 		return nil, err
 	}
 
-	return toml.Marshal(containerdConfigMap)
+	return toml.Marshal(configMap)
 
 }
 
 func generateContainerdConfig(cfg *api.NodeConfig, resources system.Resources, templateVersion ConfigSchema) ([]byte, error) {
-	runtimeOptions := getRuntimeOptions(cfg)
+	runtimeOptions, err := getRuntimeOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	configVars := containerdTemplateVars{
-		SandboxImage:       cfg.Status.Defaults.SandboxImage,
-		RuntimeBinaryName:  runtimeOptions.RuntimeBinaryPath,
-		RuntimeName:        runtimeOptions.RuntimeName,
+		SandboxImage:       getSandboxImage(cfg),
+		PinnedImages:       pinnedImages(cfg.Spec.Containerd.PrefetchImages),
+		DefaultRuntimeName: runtimeOptions.DefaultRuntimeName,
+		Runtimes:           runtimeOptions.Runtimes,
 		EnableCDI:          semver.Compare(cfg.Status.KubeletVersion, "v1.32.0") >= 0,
 		UseSOCISnapshotter: UseSOCISnapshotter(cfg, resources),
 	}
@@ -124,44 +137,106 @@ func generateContainerdConfig(cfg *api.NodeConfig, resources system.Resources, t
 }
 
 func getConfigTemplateVersion(cfg *api.NodeConfig, isContainerdV2 bool) (ConfigSchema, error) {
-	config := string(cfg.Spec.Containerd.Config)
 	if isContainerdV2 {
-		// side case: if V2 config passed in nodeConfig when using containerd 2.*, we use V2 config template and will run containerd config migrate
-		if len(cfg.Spec.Containerd.Config) > 0 && !Version3configInNodeConfig(config) {
-			return ConfigSchemaV2, nil
-		}
 		return ConfigSchemaV3, nil
-	} else {
+	}
+	if len(cfg.Spec.Containerd.Config) > 0 {
+		var config map[string]any
+		if err := toml.Unmarshal([]byte(cfg.Spec.Containerd.Config), &config); err != nil {
+			return "", err
+		}
 		// side case: if v3 config passed in nodeConfig when using containerd 1.*, throw error
-		if len(cfg.Spec.Containerd.Config) > 0 && Version3configInNodeConfig(config) {
+		if detectConfigSchema(config) == ConfigSchemaV3 {
 			zap.L().Error("Invalid containerd config passed, containerd 1.* doesn't support containerd configuration V3 properties")
 			return "", fmt.Errorf("failed to get config template version")
 		}
-		return ConfigSchemaV2, nil
 	}
+	return ConfigSchemaV2, nil
 }
 
-// Most proprty are moved under `io.containerd.cri.v1.runtime` and `io.containerd.cri.v1.images` in config v3, only a few left
-// in `io.containerd.grpc.v1.cri` which is same as config V2. So assume it is config V2 if `io.containerd.grpc.v1.cri` detected
-func Version3configInNodeConfig(config string) bool {
-	return strings.Contains(config, "io.containerd.cri.v1.images") ||
-		strings.Contains(config, "io.containerd.cri.v1.runtime")
+// getSandboxImage returns the sandbox image of the NodeConfig, or else the
+// pause image that is cached on the AMI.
+func getSandboxImage(cfg *api.NodeConfig) string {
+	if cfg.Spec.Containerd.SandboxImage != "" {
+		return cfg.Spec.Containerd.SandboxImage
+	}
+	return cfg.Status.Defaults.SandboxImage
 }
 
-func migrateConfig() error {
-	migratedConfig, err := exec.Command("containerd", "config", "migrate").Output()
+func writeSnapshotterConfig(cfg *api.NodeConfig) error {
+	if !cfg.Status.SOCI.Enabled {
+		return nil
+	}
+	zap.L().Info("Resolved SOCI snapshotter settings", zap.Reflect("settings", cfg.Status.SOCI))
+	sociConfig, err := generateSOCISnapshotterConfig(cfg.Status.SOCI)
 	if err != nil {
 		return err
 	}
-	return util.WriteFileWithDir(containerdConfigFile, migratedConfig, configPerm)
+	sociConfig, err = combineTOMLConfigs(sociConfig, []byte(cfg.Spec.Containerd.SOCI.Config))
+	if err != nil {
+		return err
+	}
+	return util.WriteFileWithDir(sociSnapshotterConfigFile, sociConfig, configPerm)
 }
 
-func writeSnapshotterConfig(cfg *api.NodeConfig, resources system.Resources) error {
-	if UseSOCISnapshotter(cfg, resources) {
-		return util.WriteFileWithDir(sociSnapshotterConfigFile, sociSnapshotterTemplateData, configPerm)
+func generateSOCISnapshotterConfig(settings api.SOCIStatus) ([]byte, error) {
+	var buf bytes.Buffer
+	sociSnapshotterTemplate := template.Must(template.New(sociSnapshotterConfigFile).Parse(sociSnapshotterTemplateData))
+	if err := sociSnapshotterTemplate.Execute(&buf, settings); err != nil {
+		return nil, err
 	}
+	return buf.Bytes(), nil
+}
 
-	return nil
+// getSOCIStatus returns whether the SOCI snapshotter is used, along with the
+// settings of the NodeConfig, or else their defaults.
+func getSOCIStatus(cfg *api.NodeConfig, resources system.Resources) api.SOCIStatus {
+	status := resolveSOCISettings(cfg.Spec.Containerd.SOCI)
+	status.Enabled = UseSOCISnapshotter(cfg, resources)
+	return status
+}
+
+func resolveSOCISettings(options api.SOCIOptions) api.SOCIStatus {
+	// the default thresholds should be met by most xlarge instance types. The
+	// memory threshold is lower than 8GiB since an instance will have less
+	// available RAM shown in the kernel than its specs.
+	// e.g. in my test a c6a.2xlarge with 16GiB of memory showed 15.75GiB available.
+	settings := api.SOCIStatus{
+		MinMemory:                      *resource.NewQuantity(7*gibibyte, resource.BinarySI),
+		MinCPU:                         *resource.NewQuantity(4, resource.DecimalSI),
+		MaxConcurrentDownloads:         -1,
+		MaxConcurrentDownloadsPerImage: 20,
+		MaxConcurrentUnpacks:           -1,
+		MaxConcurrentUnpacksPerImage:   12,
+	}
+	if options.MinMemory != nil {
+		settings.MinMemory = options.MinMemory.DeepCopy()
+	}
+	if options.MinCPU != nil {
+		settings.MinCPU = options.MinCPU.DeepCopy()
+	}
+	if options.MaxConcurrentDownloads != 0 {
+		settings.MaxConcurrentDownloads = options.MaxConcurrentDownloads
+	}
+	if options.MaxConcurrentDownloadsPerImage != 0 {
+		settings.MaxConcurrentDownloadsPerImage = options.MaxConcurrentDownloadsPerImage
+	}
+	if options.MaxConcurrentUnpacks != 0 {
+		settings.MaxConcurrentUnpacks = options.MaxConcurrentUnpacks
+	}
+	if options.MaxConcurrentUnpacksPerImage != 0 {
+		settings.MaxConcurrentUnpacksPerImage = options.MaxConcurrentUnpacksPerImage
+	}
+	return settings
+}
+
+// getSnapshotter returns the snapshotter that containerd is configured to
+// unpack images with, which is empty for the default snapshotter.
+func getSnapshotter(cfg *api.NodeConfig, resources system.Resources) string {
+	if UseSOCISnapshotter(cfg, resources) {
+		return sociSnapshotter
+	}
+	return ""
 }
 
 func UseSOCISnapshotter(cfg *api.NodeConfig, resources system.Resources) bool {
@@ -181,8 +256,6 @@ func UseSOCISnapshotter(cfg *api.NodeConfig, resources system.Resources) bool {
 		return false
 	}
 
-	// This should be usable on most xlarge instance types.
-	// Lower the memory threshold since an instance will have less available RAM shown in the kernel than its specs.
-	// e.g. in my test a c6a.2xlarge with 16GiB of memory showed 15.75GiB available.
-	return totalMemory >= 7*gibibyte && totalCPUMillicores >= 4000
+	settings := resolveSOCISettings(cfg.Spec.Containerd.SOCI)
+	return totalMemory >= settings.MinMemory.Value() && int64(totalCPUMillicores) >= settings.MinCPU.MilliValue()
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/system"
 	"github.com/pelletier/go-toml/v2"
 	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 const blockSize int64 = 0x8000000 // 128MB
@@ -31,7 +32,7 @@ func TestContainerdConfigWithUserConfigAndFastImagePullFeature(t *testing.T) {
 	assert.NoError(t, err)
 	containerdConfig, err := generateContainerdConfig(cfg, resources, template)
 	assert.NoError(t, err)
-	containerdConfig, err = combineContainerdConfigs(containerdConfig, cfg.Spec.Containerd.Config)
+	containerdConfig, err = combineTOMLConfigs(containerdConfig, []byte(cfg.Spec.Containerd.Config))
 	assert.NoError(t, err)
 
 	// Parse the containerdConfig
@@ -205,6 +206,60 @@ func TestContainerdConfig(t *testing.T) {
 	}
 }
 
+func TestSOCIStatus(t *testing.T) {
+	cfg := &api.NodeConfig{
+		Spec: api.NodeConfigSpec{
+			FeatureGates: map[api.Feature]bool{api.FastImagePull: true},
+		},
+	}
+	status := getSOCIStatus(cfg, fakeResources(2, 4*1024*1024*1024))
+	assert.False(t, status.Enabled)
+	assert.Equal(t, "7Gi", status.MinMemory.String())
+	assert.Equal(t, "4", status.MinCPU.String())
+	assert.Equal(t, int32(20), status.MaxConcurrentDownloadsPerImage)
+
+	minMemory, minCPU := resource.MustParse("4Gi"), resource.MustParse("2")
+	cfg.Spec.Containerd.SOCI = api.SOCIOptions{
+		MinMemory:              &minMemory,
+		MinCPU:                 &minCPU,
+		MaxConcurrentDownloads: 8,
+		MaxConcurrentUnpacks:   4,
+	}
+	status = getSOCIStatus(cfg, fakeResources(2, 4*1024*1024*1024))
+	assert.True(t, status.Enabled)
+	assert.Equal(t, api.SOCIStatus{
+		Enabled:                        true,
+		MinMemory:                      minMemory,
+		MinCPU:                         minCPU,
+		MaxConcurrentDownloads:         8,
+		MaxConcurrentDownloadsPerImage: 20,
+		MaxConcurrentUnpacks:           4,
+		MaxConcurrentUnpacksPerImage:   12,
+	}, status)
+}
+
+func TestSOCISnapshotterConfig(t *testing.T) {
+	settings := resolveSOCISettings(api.SOCIOptions{MaxConcurrentDownloadsPerImage: 10})
+	sociConfig, err := generateSOCISnapshotterConfig(settings)
+	assert.NoError(t, err)
+	userConfig := []byte("[pull_modes.parallel_pull_unpack]\nconcurrent_download_chunk_size = '32mb'\nmax_concurrent_unpacks = 6\n")
+	sociConfig, err = combineTOMLConfigs(sociConfig, userConfig)
+	assert.NoError(t, err)
+
+	var configMap map[string]any
+	assert.NoError(t, toml.Unmarshal(sociConfig, &configMap))
+	pullModes, ok := configMap["pull_modes"].(map[string]any)
+	assert.True(t, ok)
+	parallelPullUnpack, ok := pullModes["parallel_pull_unpack"].(map[string]any)
+	assert.True(t, ok)
+	assert.Equal(t, "32mb", parallelPullUnpack["concurrent_download_chunk_size"])
+	assert.Equal(t, int64(-1), parallelPullUnpack["max_concurrent_downloads"])
+	assert.Equal(t, int64(10), parallelPullUnpack["max_concurrent_downloads_per_image"])
+	assert.Equal(t, int64(6), parallelPullUnpack["max_concurrent_unpacks"])
+	assert.Equal(t, int64(12), parallelPullUnpack["max_concurrent_unpacks_per_image"])
+	assert.Equal(t, true, parallelPullUnpack["enable"])
+}
+
 func fakeResources(cpuCount int, memoryBytes int64) system.Resources {
 	files := map[string]string{
 		"/sys/devices/system/memory/block_size_bytes": fmt.Sprintf("%x", blockSize),
@@ -26,13 +26,19 @@ func (cd *containerd) Configure(c *api.NodeConfig) error {
 	if err := writeBaseRuntimeSpec(c); err != nil {
 		return err
 	}
-	if err := writeSnapshotterConfig(c, cd.resources); err != nil {
+	// the SOCI settings are recorded in the status, which is cached along with
+	// the rest of the resolved config.
+	c.Status.SOCI = getSOCIStatus(c, cd.resources)
+	if err := writeSnapshotterConfig(c); err != nil {
+		return err
+	}
+	if err := writeRegistryConfigs(c); err != nil {
 		return err
 	}
 	if err := writeContainerdConfig(c, cd.resources); err != nil {
 		return err
 	}
-	if err := writeSOCIServiceDependency(c, cd.resources); err != nil {
+	if err := writeSOCIServiceDependency(c, cd.resources, cd.daemonManager); err != nil {
 		return err
 	}
 	return nil
@@ -43,11 +49,21 @@ func (cd *containerd) EnsureRunning() error {
 }
 
 func (cd *containerd) PostLaunch(c *api.NodeConfig) error {
-	if UseSOCISnapshotter(c, cd.resources) {
+	snapshotter := getSnapshotter(c, cd.resources)
+	if snapshotter == sociSnapshotter {
 		if err := importSandboxImageForSOCI(); err != nil {
 			return err
 		}
 	}
+	for _, archive := range c.Spec.Containerd.ImageArchives {
+		if err := importImageArchive(archive, snapshotter); err != nil {
+			return err
+		}
+	}
+	if err := ensureSandboxImage(c); err != nil {
+		return err
+	}
+	prefetchImages(c)
 	return nil
 }
 
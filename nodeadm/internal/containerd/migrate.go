@@ -0,0 +1,252 @@
+package containerd
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+	"github.com/pelletier/go-toml/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	criPluginV2        = "io.containerd.grpc.v1.cri"
+	criImagesPluginV3  = "io.containerd.cri.v1.images"
+	criRuntimePluginV3 = "io.containerd.cri.v1.runtime"
+)
+
+// configKeyMove moves a dotted key of the CRI plugin in schema version 2 to a
+// dotted key of a plugin in schema version 3.
+type configKeyMove struct {
+	from   string
+	plugin string
+	to     string
+}
+
+// criConfigMovesV2ToV3 are applied in order, so the image keys of the
+// containerd table are moved before the rest of the table.
+// see: https://github.com/containerd/containerd/blob/main/docs/cri/config.md
+var criConfigMovesV2ToV3 = []configKeyMove{
+	{from: "containerd.snapshotter", plugin: criImagesPluginV3, to: "snapshotter"},
+	{from: "containerd.disable_snapshot_annotations", plugin: criImagesPluginV3, to: "disable_snapshot_annotations"},
+	{from: "containerd.discard_unpacked_layers", plugin: criImagesPluginV3, to: "discard_unpacked_layers"},
+	{from: "sandbox_image", plugin: criImagesPluginV3, to: "pinned_images.sandbox"},
+	{from: "registry", plugin: criImagesPluginV3, to: "registry"},
+	{from: "image_decryption", plugin: criImagesPluginV3, to: "image_decryption"},
+	{from: "max_concurrent_downloads", plugin: criImagesPluginV3, to: "max_concurrent_downloads"},
+	{from: "image_pull_progress_timeout", plugin: criImagesPluginV3, to: "image_pull_progress_timeout"},
+	{from: "image_pull_with_sync_fs", plugin: criImagesPluginV3, to: "image_pull_with_sync_fs"},
+	{from: "stats_collect_period", plugin: criImagesPluginV3, to: "stats_collect_period"},
+	{from: "containerd", plugin: criRuntimePluginV3, to: "containerd"},
+	{from: "cni", plugin: criRuntimePluginV3, to: "cni"},
+}
+
+// criConfigKeptV3 stay in the CRI plugin in schema version 3. Every other key
+// of the CRI plugin without a move is moved to the runtime plugin.
+var criConfigKeptV3 = []string{
+	"disable_tcp_service",
+	"stream_server_address",
+	"stream_server_port",
+	"stream_idle_timeout",
+	"enable_tls_streaming",
+	"x509_key_pair_streaming",
+}
+
+// criConfigRemovedV3 are dotted keys of the CRI plugin that containerd 2.x no
+// longer supports.
+var criConfigRemovedV3 = []string{
+	"systemd_cgroup",
+	"containerd.default_runtime",
+	"containerd.untrusted_workload_runtime",
+	"registry.auths",
+}
+
+// runtimeConfigRemovedV3 are keys of each runtime that containerd 2.x no
+// longer supports.
+var runtimeConfigRemovedV3 = []string{
+	"runtime_engine",
+	"runtime_root",
+}
+
+// runtimeConfigRenamedV3 are keys of each runtime that were renamed in schema
+// version 3.
+var runtimeConfigRenamedV3 = map[string]string{
+	"sandbox_mode": "sandboxer",
+}
+
+// detectConfigSchema returns the schema version of a containerd config from
+// its version, or else from the CRI plugins it configures.
+func detectConfigSchema(config map[string]any) ConfigSchema {
+	if version, ok := config["version"].(int64); ok {
+		if version >= 3 {
+			return ConfigSchemaV3
+		}
+		return ConfigSchemaV2
+	}
+	plugins, _ := config["plugins"].(map[string]any)
+	for _, plugin := range []string{criImagesPluginV3, criRuntimePluginV3} {
+		if _, ok := plugins[plugin]; ok {
+			return ConfigSchemaV3
+		}
+	}
+	return ConfigSchemaV2
+}
+
+// migrateUserConfig translates the containerd config of the NodeConfig to the
+// schema version of the template, so it can be merged with the template.
+func migrateUserConfig(config api.ContainerdConfig, templateVersion ConfigSchema) (api.ContainerdConfig, error) {
+	if len(config) == 0 || templateVersion != ConfigSchemaV3 {
+		return config, nil
+	}
+	var configMap map[string]any
+	if err := toml.Unmarshal([]byte(config), &configMap); err != nil {
+		return "", err
+	}
+	if detectConfigSchema(configMap) == ConfigSchemaV3 {
+		return config, nil
+	}
+	zap.L().Info("Migrating containerd config to V3..")
+	for _, warning := range migrateConfigV2ToV3(configMap) {
+		zap.L().Warn(warning)
+	}
+	migratedConfig, err := toml.Marshal(configMap)
+	if err != nil {
+		return "", err
+	}
+	return api.ContainerdConfig(migratedConfig), nil
+}
+
+// migrateConfigV2ToV3 translates the CRI plugin of a containerd config from
+// schema version 2 to 3 in place, and returns a warning for each key that was
+// removed. A key that is already set in schema version 3 is kept over the key
+// it would be migrated from.
+func migrateConfigV2ToV3(config map[string]any) []string {
+	config["version"] = int64(3)
+	plugins, ok := config["plugins"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	cri, ok := plugins[criPluginV2].(map[string]any)
+	if !ok {
+		return nil
+	}
+	var warnings []string
+	for _, key := range criConfigRemovedV3 {
+		if _, ok := deleteConfigKey(cri, key); ok {
+			warnings = append(warnings, fmt.Sprintf("Removed plugins.%q.%s, which is not supported by containerd 2.x", criPluginV2, key))
+		}
+	}
+	for _, move := range criConfigMovesV2ToV3 {
+		if value, ok := deleteConfigKey(cri, move.from); ok {
+			mergeConfigKey(pluginConfig(plugins, move.plugin), move.to, value)
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(cri)) {
+		if !slices.Contains(criConfigKeptV3, key) {
+			mergeConfigKey(pluginConfig(plugins, criRuntimePluginV3), key, cri[key])
+			delete(cri, key)
+		}
+	}
+	for _, plugin := range []string{criPluginV2, criImagesPluginV3, criRuntimePluginV3} {
+		if table, ok := plugins[plugin].(map[string]any); ok && len(table) == 0 {
+			delete(plugins, plugin)
+		}
+	}
+	runtimePlugin, _ := plugins[criRuntimePluginV3].(map[string]any)
+	runtimes, _ := lookupConfigKey(runtimePlugin, "containerd.runtimes").(map[string]any)
+	for _, name := range slices.Sorted(maps.Keys(runtimes)) {
+		runtime, ok := runtimes[name].(map[string]any)
+		if !ok {
+			continue
+		}
+		for _, key := range runtimeConfigRemovedV3 {
+			if _, ok := runtime[key]; ok {
+				delete(runtime, key)
+				warnings = append(warnings, fmt.Sprintf("Removed %s of runtime %s, which is not supported by containerd 2.x", key, name))
+			}
+		}
+		for _, key := range slices.Sorted(maps.Keys(runtimeConfigRenamedV3)) {
+			if value, ok := runtime[key]; ok {
+				delete(runtime, key)
+				if _, ok := runtime[runtimeConfigRenamedV3[key]]; !ok {
+					runtime[runtimeConfigRenamedV3[key]] = value
+				}
+			}
+		}
+	}
+	return warnings
+}
+
+// pluginConfig returns the table of a plugin, creating it if it's not set.
+// Plugin IDs contain dots, so they cannot be part of a dotted key.
+func pluginConfig(plugins map[string]any, plugin string) map[string]any {
+	table, ok := plugins[plugin].(map[string]any)
+	if !ok {
+		table = map[string]any{}
+		plugins[plugin] = table
+	}
+	return table
+}
+
+// lookupConfigKey returns the value of a dotted key, or nil if it's not set.
+func lookupConfigKey(config map[string]any, key string) any {
+	var value any = config
+	for _, part := range strings.Split(key, ".") {
+		table, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = table[part]
+	}
+	return value
+}
+
+// deleteConfigKey removes a dotted key, and returns the value it had.
+func deleteConfigKey(config map[string]any, key string) (any, bool) {
+	table := config
+	parts := strings.Split(key, ".")
+	if len(parts) > 1 {
+		var ok bool
+		if table, ok = lookupConfigKey(config, strings.Join(parts[:len(parts)-1], ".")).(map[string]any); !ok {
+			return nil, false
+		}
+	}
+	value, ok := table[parts[len(parts)-1]]
+	delete(table, parts[len(parts)-1])
+	return value, ok
+}
+
+// mergeConfigKey sets a dotted key to value, creating the tables along the
+// way. Tables are merged recursively, and a value that is already set is kept.
+func mergeConfigKey(config map[string]any, key string, value any) {
+	table := config
+	parts := strings.Split(key, ".")
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := table[part].(map[string]any)
+		if !ok {
+			if _, set := table[part]; set {
+				return
+			}
+			next = map[string]any{}
+			table[part] = next
+		}
+		table = next
+	}
+	last := parts[len(parts)-1]
+	existing, set := table[last]
+	if !set {
+		if valueTable, ok := value.(map[string]any); !ok || len(valueTable) > 0 {
+			table[last] = value
+		}
+		return
+	}
+	existingTable, existingIsTable := existing.(map[string]any)
+	valueTable, valueIsTable := value.(map[string]any)
+	if existingIsTable && valueIsTable {
+		for _, valueKey := range slices.Sorted(maps.Keys(valueTable)) {
+			mergeConfigKey(existingTable, valueKey, valueTable[valueKey])
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package containerd
+
+import (
+	"testing"
+
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+	"github.com/pelletier/go-toml/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectConfigSchema(t *testing.T) {
+	var tests = []struct {
+		name     string
+		config   string
+		expected ConfigSchema
+	}{
+		{name: "version 2", config: "version = 2\n[plugins.'io.containerd.cri.v1.images']\nsnapshotter = 'soci'", expected: ConfigSchemaV2},
+		{name: "version 3", config: "version = 3\n[plugins.'io.containerd.grpc.v1.cri']\ndisable_tcp_service = true", expected: ConfigSchemaV3},
+		{name: "images plugin", config: "[plugins.'io.containerd.cri.v1.images']\nsnapshotter = 'soci'", expected: ConfigSchemaV3},
+		{name: "runtime plugin", config: "[plugins.\"io.containerd.cri.v1.runtime\"]\nenable_cdi = true", expected: ConfigSchemaV3},
+		{name: "cri plugin", config: "[plugins.'io.containerd.grpc.v1.cri']\nsandbox_image = 'pause'", expected: ConfigSchemaV2},
+		{name: "plugin name in a value", config: "[plugins.'io.containerd.grpc.v1.cri'.containerd]\nsnapshotter = 'io.containerd.cri.v1.images'", expected: ConfigSchemaV2},
+		{name: "no plugins", config: "[grpc]\naddress = '/run/foo/foo.sock'", expected: ConfigSchemaV2},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var config map[string]any
+			assert.NoError(t, toml.Unmarshal([]byte(test.config), &config))
+			assert.Equal(t, test.expected, detectConfigSchema(config))
+		})
+	}
+}
+
+func TestMigrateConfigV2ToV3(t *testing.T) {
+	var tests = []struct {
+		name             string
+		config           string
+		expectedConfig   string
+		expectedWarnings []string
+	}{
+		{
+			name: "moves cri plugin keys",
+			config: `
+version = 2
+
+[grpc]
+address = "/run/foo/foo.sock"
+
+[plugins."io.containerd.grpc.v1.cri"]
+sandbox_image = "registry.k8s.io/pause:3.10"
+enable_cdi = true
+max_concurrent_downloads = 5
+disable_tcp_service = false
+
+[plugins."io.containerd.grpc.v1.cri".containerd]
+default_runtime_name = "nvidia"
+snapshotter = "soci"
+discard_unpacked_layers = false
+
+[plugins."io.containerd.grpc.v1.cri".containerd.runtimes.nvidia]
+runtime_type = "io.containerd.runc.v2"
+sandbox_mode = "podsandbox"
+
+[plugins."io.containerd.grpc.v1.cri".containerd.runtimes.nvidia.options]
+BinaryName = "/usr/bin/nvidia-container-runtime"
+
+[plugins."io.containerd.grpc.v1.cri".registry]
+config_path = "/etc/containerd/certs.d"
+
+[plugins."io.containerd.grpc.v1.cri".cni]
+bin_dir = "/opt/cni/bin"
+
+[plugins."io.containerd.internal.v1.opt"]
+path = "/opt/containerd"
+`,
+			expectedConfig: `
+version = 3
+
+[grpc]
+address = "/run/foo/foo.sock"
+
+[plugins."io.containerd.grpc.v1.cri"]
+disable_tcp_service = false
+
+[plugins."io.containerd.cri.v1.images"]
+snapshotter = "soci"
+discard_unpacked_layers = false
+max_concurrent_downloads = 5
+
+[plugins."io.containerd.cri.v1.images".pinned_images]
+sandbox = "registry.k8s.io/pause:3.10"
+
+[plugins."io.containerd.cri.v1.images".registry]
+config_path = "/etc/containerd/certs.d"
+
+[plugins."io.containerd.cri.v1.runtime"]
+enable_cdi = true
+
+[plugins."io.containerd.cri.v1.runtime".containerd]
+default_runtime_name = "nvidia"
+
+[plugins."io.containerd.cri.v1.runtime".containerd.runtimes.nvidia]
+runtime_type = "io.containerd.runc.v2"
+sandboxer = "podsandbox"
+
+[plugins."io.containerd.cri.v1.runtime".containerd.runtimes.nvidia.options]
+BinaryName = "/usr/bin/nvidia-container-runtime"
+
+[plugins."io.containerd.cri.v1.runtime".cni]
+bin_dir = "/opt/cni/bin"
+
+[plugins."io.containerd.internal.v1.opt"]
+path = "/opt/containerd"
+`,
+		},
+		{
+			name: "warns about removed keys",
+			config: `
+[plugins."io.containerd.grpc.v1.cri"]
+systemd_cgroup = true
+
+[plugins."io.containerd.grpc.v1.cri".containerd.default_runtime]
+runtime_type = "io.containerd.runtime.v1.linux"
+
+[plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runc]
+runtime_type = "io.containerd.runc.v2"
+runtime_root = "/run/runc"
+
+[plugins."io.containerd.grpc.v1.cri".registry.auths."https://registry.example.com"]
+username = "user"
+`,
+			expectedConfig: `
+version = 3
+
+[plugins."io.containerd.cri.v1.runtime".containerd.runtimes.runc]
+runtime_type = "io.containerd.runc.v2"
+`,
+			expectedWarnings: []string{
+				`Removed plugins."io.containerd.grpc.v1.cri".systemd_cgroup, which is not supported by containerd 2.x`,
+				`Removed plugins."io.containerd.grpc.v1.cri".containerd.default_runtime, which is not supported by containerd 2.x`,
+				`Removed plugins."io.containerd.grpc.v1.cri".registry.auths, which is not supported by containerd 2.x`,
+				`Removed runtime_root of runtime runc, which is not supported by containerd 2.x`,
+			},
+		},
+		{
+			name: "keeps keys that are already migrated",
+			config: `
+[plugins."io.containerd.grpc.v1.cri"]
+sandbox_image = "localhost/pause:old"
+
+[plugins."io.containerd.grpc.v1.cri".containerd]
+default_runtime_name = "runc"
+
+[plugins."io.containerd.cri.v1.images".pinned_images]
+sandbox = "localhost/pause:new"
+
+[plugins."io.containerd.cri.v1.runtime".containerd]
+default_runtime_name = "nvidia"
+`,
+			expectedConfig: `
+version = 3
+
+[plugins."io.containerd.cri.v1.images".pinned_images]
+sandbox = "localhost/pause:new"
+
+[plugins."io.containerd.cri.v1.runtime".containerd]
+default_runtime_name = "nvidia"
+`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var config, expectedConfig map[string]any
+			assert.NoError(t, toml.Unmarshal([]byte(test.config), &config))
+			assert.NoError(t, toml.Unmarshal([]byte(test.expectedConfig), &expectedConfig))
+			warnings := migrateConfigV2ToV3(config)
+			assert.Equal(t, expectedConfig, config)
+			assert.Equal(t, test.expectedWarnings, warnings)
+		})
+	}
+}
+
+func TestMigrateUserConfig(t *testing.T) {
+	cfg := &api.NodeConfig{
+		Status: api.NodeConfigStatus{
+			KubeletVersion: "v1.32.0",
+			Defaults:       api.DefaultOptions{SandboxImage: "localhost/kubernetes/pause:latest"},
+		},
+		Spec: api.NodeConfigSpec{
+			Containerd: api.ContainerdOptions{
+				Config: api.ContainerdConfig(`
+version = 2
+
+[plugins."io.containerd.grpc.v1.cri".containerd]
+discard_unpacked_layers = false
+
+[plugins.'io.containerd.grpc.v1.cri']
+sandbox_image = "registry.k8s.io/pause:3.10"
+`),
+			},
+		},
+	}
+	templateVersion, err := getConfigTemplateVersion(cfg, true)
+	assert.NoError(t, err)
+	userConfig, err := migrateUserConfig(cfg.Spec.Containerd.Config, templateVersion)
+	assert.NoError(t, err)
+	containerdConfig, err := generateContainerdConfig(cfg, fakeResources(2, 4*1024*1024*1024), templateVersion)
+	assert.NoError(t, err)
+	containerdConfig, err = combineTOMLConfigs(containerdConfig, []byte(userConfig))
+	assert.NoError(t, err)
+
+	var configMap map[string]any
+	assert.NoError(t, toml.Unmarshal(containerdConfig, &configMap))
+	assert.Equal(t, int64(3), configMap["version"])
+	plugins := configMap["plugins"].(map[string]any)
+	assert.NotContains(t, plugins, criPluginV2)
+	images := plugins[criImagesPluginV3].(map[string]any)
+	assert.Equal(t, false, images["discard_unpacked_layers"])
+	assert.Equal(t, "registry.k8s.io/pause:3.10", images["pinned_images"].(map[string]any)["sandbox"])
+
+	// a V3 config is not migrated
+	v3Config := api.ContainerdConfig("[plugins.'io.containerd.cri.v1.images']\ndiscard_unpacked_layers = false\n")
+	userConfig, err = migrateUserConfig(v3Config, ConfigSchemaV3)
+	assert.NoError(t, err)
+	assert.Equal(t, v3Config, userConfig)
+}
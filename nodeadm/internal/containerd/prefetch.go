@@ -0,0 +1,238 @@
+package containerd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+	configv1 "k8s.io/kubelet/config/v1"
+	credentialproviderv1 "k8s.io/kubelet/pkg/apis/credentialprovider/v1"
+
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/util"
+)
+
+const (
+	// defaultPrefetchImagesTimeout bounds how long the start of kubelet is
+	// delayed by pulling images, which kubelet would otherwise pull itself.
+	defaultPrefetchImagesTimeout = 5 * time.Minute
+
+	// the label that the CRI plugin sets on pinned images, which protects them
+	// from the image garbage collection of kubelet.
+	criPinnedImageLabel = "io.cri-containerd.pinned=pinned"
+
+	defaultImageRegistry = "docker.io"
+)
+
+// pinnedImageNamePattern matches the characters that cannot be part of a bare
+// key in the pinned images table of the containerd config.
+var pinnedImageNamePattern = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// prefetchImages pulls the prefetch images of the NodeConfig concurrently
+// through the CRI plugin of containerd, so that they're available before
+// kubelet starts the pods that use them. A failed pull is only logged, as
+// kubelet pulls the image again when it's needed.
+func prefetchImages(cfg *api.NodeConfig) {
+	images := cfg.Spec.Containerd.PrefetchImages
+	if len(images) == 0 {
+		return
+	}
+	timeout := defaultPrefetchImagesTimeout
+	if cfg.Spec.Containerd.PrefetchImagesTimeout != nil {
+		timeout = cfg.Spec.Containerd.PrefetchImagesTimeout.Duration
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	conn, err := newCRIClientConn()
+	if err != nil {
+		zap.L().Warn("Failed to connect to containerd, not prefetching images", zap.Error(err))
+		return
+	}
+	defer conn.Close()
+	imageService := runtimeapi.NewImageServiceClient(conn)
+
+	zap.L().Info("Prefetching images..", zap.Int("count", len(images)), zap.Duration("timeout", timeout))
+	var wg sync.WaitGroup
+	for _, image := range images {
+		wg.Go(func() {
+			start := time.Now()
+			if err := pullImage(ctx, imageService, image); err != nil {
+				zap.L().Warn("Failed to prefetch image", zap.String("reference", image.Reference), zap.Error(err))
+				return
+			}
+			zap.L().Info("Prefetched image", zap.String("reference", image.Reference), zap.Duration("duration", time.Since(start)))
+		})
+	}
+	wg.Wait()
+}
+
+// newCRIClientConn returns a connection to the CRI plugin of containerd on its
+// socket, which kubelet uses as its container runtime endpoint.
+func newCRIClientConn() (*grpc.ClientConn, error) {
+	return grpc.NewClient(ContainerRuntimeEndpoint, grpc.WithTransportCredentials(insecure.NewCredentials()))
+}
+
+// pullImage pulls an image through the image service of the CRI plugin, like
+// kubelet does, so that the registry mirrors and the snapshotter of the
+// containerd config apply to the pull, and the image is labeled as managed by
+// the CRI plugin. The credentials are passed in the request, rather than on
+// the command line of a process.
+func pullImage(ctx context.Context, imageService runtimeapi.ImageServiceClient, image api.PrefetchImage) error {
+	reference := normalizeImageReference(image.Reference)
+	var auth *runtimeapi.AuthConfig
+	username, password, err := getImageCredentials(ctx, reference)
+	if err != nil {
+		// the registry may allow anonymous pulls, or the image may be pulled
+		// from a mirror that does not need credentials.
+		zap.L().Warn("Failed to get image credentials, pulling without them", zap.String("reference", reference), zap.Error(err))
+	} else if username != "" {
+		auth = &runtimeapi.AuthConfig{Username: username, Password: password}
+	}
+	if _, err := imageService.PullImage(ctx, &runtimeapi.PullImageRequest{
+		Image: &runtimeapi.ImageSpec{Image: reference},
+		Auth:  auth,
+	}); err != nil {
+		return fmt.Errorf("pulling image: %w", err)
+	}
+	if !image.Pin {
+		return nil
+	}
+	// containerd 1.7 has no pinned images in its config, so the image is
+	// labeled like the CRI plugin labels the pinned sandbox image.
+	// #nosec G204 // the image is from the NodeConfig, and ctr does not use a shell
+	output, err := exec.CommandContext(ctx, "ctr", "--namespace", "k8s.io", "images", "label", reference, criPinnedImageLabel).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("labeling image: %w, output: %s", err, string(output))
+	}
+	return nil
+}
+
+// getImageCredentials returns the username and password to pull an image
+// with, from the credential provider of kubelet that matches the registry of
+// the image. No credentials are returned for an image that no credential
+// provider matches.
+func getImageCredentials(ctx context.Context, reference string) (string, string, error) {
+	configData, err := os.ReadFile(util.ImageCredentialProviderConfigPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", "", nil
+	} else if err != nil {
+		return "", "", err
+	}
+	var config configv1.CredentialProviderConfig
+	if err := json.Unmarshal(configData, &config); err != nil {
+		return "", "", fmt.Errorf("parsing %s: %w", util.ImageCredentialProviderConfigPath, err)
+	}
+	host := imageRegistryHost(reference)
+	providerIndex := slices.IndexFunc(config.Providers, func(provider configv1.CredentialProvider) bool {
+		return slices.ContainsFunc(provider.MatchImages, func(matchImage string) bool { return util.MatchesImageHost(matchImage, host) })
+	})
+	if providerIndex < 0 {
+		return "", "", nil
+	}
+	provider := config.Providers[providerIndex]
+
+	request, err := json.Marshal(credentialproviderv1.CredentialProviderRequest{
+		TypeMeta: metav1.TypeMeta{Kind: "CredentialProviderRequest", APIVersion: provider.APIVersion},
+		Image:    reference,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	// the binaries of the credential providers are in the same directory, which
+	// kubelet is configured with.
+	binPath := filepath.Join(filepath.Dir(util.ECRCredentialProviderBinPath()), provider.Name)
+	// #nosec G204 // the binary is from the credential provider config written by nodeadm
+	cmd := exec.CommandContext(ctx, binPath, provider.Args...)
+	cmd.Stdin = bytes.NewReader(request)
+	cmd.Env = os.Environ()
+	for _, env := range provider.Env {
+		cmd.Env = append(cmd.Env, env.Name+"="+env.Value)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", "", fmt.Errorf("running credential provider %s: %w, output: %s", provider.Name, err, stderr.String())
+	}
+	var response credentialproviderv1.CredentialProviderResponse
+	if err := json.Unmarshal(output, &response); err != nil {
+		return "", "", fmt.Errorf("parsing response of credential provider %s: %w", provider.Name, err)
+	}
+	for _, key := range slices.Sorted(maps.Keys(response.Auth)) {
+		if util.MatchesImageHost(strings.SplitN(key, "/", 2)[0], host) {
+			auth := response.Auth[key]
+			return auth.Username, auth.Password, nil
+		}
+	}
+	return "", "", nil
+}
+
+// normalizeImageReference returns the fully qualified form of an image
+// reference, which ctr requires, like the CRI plugin does for the images that
+// kubelet pulls. e.g. nginx is normalized to docker.io/library/nginx:latest.
+func normalizeImageReference(reference string) string {
+	name, digest, hasDigest := strings.Cut(reference, "@")
+	if first, _, found := strings.Cut(name, "/"); !found {
+		name = defaultImageRegistry + "/library/" + name
+	} else if !strings.ContainsAny(first, ".:") && first != "localhost" {
+		name = defaultImageRegistry + "/" + name
+	}
+	if hasDigest {
+		return name + "@" + digest
+	}
+	if !strings.Contains(name[strings.LastIndex(name, "/"):], ":") {
+		name += ":latest"
+	}
+	return name
+}
+
+// imageRegistryHost returns the host of the registry of a normalized image
+// reference, without its port.
+func imageRegistryHost(reference string) string {
+	host, _, _ := strings.Cut(reference, "/")
+	if index := strings.LastIndex(host, ":"); index >= 0 {
+		host = host[:index]
+	}
+	return host
+}
+
+// pinnedImages returns the prefetch images to pin, keyed by a name that is
+// unique within the pinned images of the containerd config.
+func pinnedImages(images []api.PrefetchImage) map[string]string {
+	pinned := map[string]string{}
+	names := map[string]bool{"sandbox": true}
+	for _, image := range images {
+		reference := normalizeImageReference(image.Reference)
+		if !image.Pin || slices.Contains(slices.Collect(maps.Values(pinned)), reference) {
+			continue
+		}
+		repository, _, _ := strings.Cut(reference, "@")
+		repository = repository[strings.LastIndex(repository, "/")+1:]
+		repository, _, _ = strings.Cut(repository, ":")
+		baseName := pinnedImageNamePattern.ReplaceAllString(repository, "-")
+		name := baseName
+		for i := 2; names[name]; i++ {
+			name = fmt.Sprintf("%s-%d", baseName, i)
+		}
+		names[name] = true
+		pinned[name] = reference
+	}
+	return pinned
+}
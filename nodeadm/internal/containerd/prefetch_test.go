@@ -0,0 +1,136 @@
+package containerd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/util"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+func TestNormalizeImageReference(t *testing.T) {
+	var tests = []struct {
+		reference string
+		expected  string
+	}{
+		{reference: "nginx", expected: "docker.io/library/nginx:latest"},
+		{reference: "bitnami/nginx:1.25", expected: "docker.io/bitnami/nginx:1.25"},
+		{reference: "localhost/nginx", expected: "localhost/nginx:latest"},
+		{reference: "registry.example.com:5000/team/app", expected: "registry.example.com:5000/team/app:latest"},
+		{reference: "public.ecr.aws/eks/aws-load-balancer-controller:v2.7.1", expected: "public.ecr.aws/eks/aws-load-balancer-controller:v2.7.1"},
+		{reference: "busybox@sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", expected: "docker.io/library/busybox@sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, normalizeImageReference(test.reference), test.reference)
+	}
+}
+
+func TestPinnedImages(t *testing.T) {
+	images := []api.PrefetchImage{
+		{Reference: "public.ecr.aws/eks/aws-load-balancer-controller:v2.7.1", Pin: true},
+		{Reference: "registry.example.com/aws-load-balancer-controller:v2.7.1", Pin: true},
+		{Reference: "registry.example.com/sandbox:v1", Pin: true},
+		{Reference: "registry.example.com/node.exporter", Pin: true},
+		{Reference: "nginx"},
+	}
+	assert.Equal(t, map[string]string{
+		"aws-load-balancer-controller":   "public.ecr.aws/eks/aws-load-balancer-controller:v2.7.1",
+		"aws-load-balancer-controller-2": "registry.example.com/aws-load-balancer-controller:v2.7.1",
+		"sandbox-2":                      "registry.example.com/sandbox:v1",
+		"node-exporter":                  "registry.example.com/node.exporter:latest",
+	}, pinnedImages(images))
+}
+
+func TestGetImageCredentials(t *testing.T) {
+	binDir := t.TempDir()
+	provider := `#!/bin/sh
+cat > /dev/null
+echo '{"kind":"CredentialProviderResponse","apiVersion":"credentialprovider.kubelet.k8s.io/v1","cacheKeyType":"Registry","auth":{"111122223333.dkr.ecr.us-west-2.amazonaws.com":{"username":"AWS","password":"token"}}}'
+`
+	assert.NoError(t, os.WriteFile(filepath.Join(binDir, "ecr-credential-provider"), []byte(provider), 0755))
+	t.Setenv("ECR_CREDENTIAL_PROVIDER_BIN_PATH", filepath.Join(binDir, "ecr-credential-provider"))
+
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	config := `{"kind":"CredentialProviderConfig","apiVersion":"kubelet.config.k8s.io/v1","providers":[{"name":"ecr-credential-provider","matchImages":["*.dkr.ecr.*.amazonaws.com"],"apiVersion":"credentialprovider.kubelet.k8s.io/v1","defaultCacheDuration":"12h0m0s"}]}`
+	assert.NoError(t, os.WriteFile(configPath, []byte(config), 0644))
+	defaultConfigPath := util.ImageCredentialProviderConfigPath
+	util.ImageCredentialProviderConfigPath = configPath
+	t.Cleanup(func() { util.ImageCredentialProviderConfigPath = defaultConfigPath })
+
+	username, password, err := getImageCredentials(context.Background(), "111122223333.dkr.ecr.us-west-2.amazonaws.com/app:v1")
+	assert.NoError(t, err)
+	assert.Equal(t, "AWS", username)
+	assert.Equal(t, "token", password)
+
+	username, password, err = getImageCredentials(context.Background(), "docker.io/library/nginx:latest")
+	assert.NoError(t, err)
+	assert.Empty(t, username)
+	assert.Empty(t, password)
+}
+
+// fakeImageService records the requests to pull images.
+type fakeImageService struct {
+	runtimeapi.ImageServiceClient
+	requests []*runtimeapi.PullImageRequest
+}
+
+func (f *fakeImageService) PullImage(ctx context.Context, in *runtimeapi.PullImageRequest, opts ...grpc.CallOption) (*runtimeapi.PullImageResponse, error) {
+	f.requests = append(f.requests, in)
+	return &runtimeapi.PullImageResponse{ImageRef: "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"}, nil
+}
+
+func TestPullImage(t *testing.T) {
+	binDir := t.TempDir()
+	ctr := `#!/bin/sh
+echo "$@" >> "$(dirname "$0")/args"
+`
+	assert.NoError(t, os.WriteFile(filepath.Join(binDir, "ctr"), []byte(ctr), 0755))
+	provider := `#!/bin/sh
+cat > /dev/null
+echo '{"kind":"CredentialProviderResponse","apiVersion":"credentialprovider.kubelet.k8s.io/v1","cacheKeyType":"Registry","auth":{"111122223333.dkr.ecr.us-west-2.amazonaws.com":{"username":"AWS","password":"token"}}}'
+`
+	assert.NoError(t, os.WriteFile(filepath.Join(binDir, "ecr-credential-provider"), []byte(provider), 0755))
+	t.Setenv("PATH", binDir+":"+os.Getenv("PATH"))
+	t.Setenv("ECR_CREDENTIAL_PROVIDER_BIN_PATH", filepath.Join(binDir, "ecr-credential-provider"))
+
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	config := `{"kind":"CredentialProviderConfig","apiVersion":"kubelet.config.k8s.io/v1","providers":[{"name":"ecr-credential-provider","matchImages":["*.dkr.ecr.*.amazonaws.com"],"apiVersion":"credentialprovider.kubelet.k8s.io/v1","defaultCacheDuration":"12h0m0s"}]}`
+	assert.NoError(t, os.WriteFile(configPath, []byte(config), 0644))
+	defaultConfigPath := util.ImageCredentialProviderConfigPath
+	util.ImageCredentialProviderConfigPath = configPath
+	t.Cleanup(func() { util.ImageCredentialProviderConfigPath = defaultConfigPath })
+
+	imageService := &fakeImageService{}
+	assert.NoError(t, pullImage(context.Background(), imageService, api.PrefetchImage{Reference: "111122223333.dkr.ecr.us-west-2.amazonaws.com/app:v1", Pin: true}))
+	assert.NoError(t, pullImage(context.Background(), imageService, api.PrefetchImage{Reference: "nginx"}))
+	if assert.Len(t, imageService.requests, 2) {
+		assert.Equal(t, "111122223333.dkr.ecr.us-west-2.amazonaws.com/app:v1", imageService.requests[0].GetImage().GetImage())
+		assert.Equal(t, "AWS", imageService.requests[0].GetAuth().GetUsername())
+		assert.Equal(t, "token", imageService.requests[0].GetAuth().GetPassword())
+		assert.Equal(t, "docker.io/library/nginx:latest", imageService.requests[1].GetImage().GetImage())
+		assert.Nil(t, imageService.requests[1].GetAuth())
+	}
+	// only the pinned image is labeled
+	args, err := os.ReadFile(filepath.Join(binDir, "args"))
+	assert.NoError(t, err)
+	assert.Equal(t, "--namespace k8s.io images label 111122223333.dkr.ecr.us-west-2.amazonaws.com/app:v1 io.cri-containerd.pinned=pinned\n", string(args))
+}
+
+func TestPullImageWithoutCredentials(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	assert.NoError(t, os.WriteFile(configPath, []byte("not a credential provider config"), 0644))
+	defaultConfigPath := util.ImageCredentialProviderConfigPath
+	util.ImageCredentialProviderConfigPath = configPath
+	t.Cleanup(func() { util.ImageCredentialProviderConfigPath = defaultConfigPath })
+
+	imageService := &fakeImageService{}
+	assert.NoError(t, pullImage(context.Background(), imageService, api.PrefetchImage{Reference: "111122223333.dkr.ecr.us-west-2.amazonaws.com/app:v1"}))
+	if assert.Len(t, imageService.requests, 1) {
+		assert.Nil(t, imageService.requests[0].GetAuth())
+	}
+}
@@ -0,0 +1,127 @@
+package containerd
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"slices"
+	"text/template"
+
+	"go.uber.org/zap"
+
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/util"
+)
+
+const (
+	// registryConfigRoot is the first directory of the registry config_path in
+	// the containerd config templates.
+	registryConfigRoot = "/etc/containerd/certs.d"
+
+	// registryConfigMarker is written to the directory of each registry that
+	// nodeadm configures, so that the directories of registries which are
+	// removed from the NodeConfig can be told apart from those of others.
+	registryConfigMarker = ".nodeadm"
+)
+
+var (
+	//go:embed hosts.template.toml
+	hostsTemplateData string
+	hostsTemplate     = template.Must(template.New("hosts.toml").Parse(hostsTemplateData))
+
+	defaultMirrorCapabilities = []api.RegistryCapability{api.RegistryCapabilityPull, api.RegistryCapabilityResolve}
+)
+
+// hostsTemplateVars are the settings of the server and mirrors of a registry
+// in hosts.toml, where the CAs are paths of certificate files.
+type hostsTemplateVars struct {
+	Server     string
+	CA         string
+	SkipVerify bool
+	Mirrors    []api.RegistryMirror
+}
+
+// writeRegistryConfigs writes a hosts.toml, along with its certificate
+// authorities, for each registry of the NodeConfig. The hosts.toml format is
+// the same for containerd 1.x and 2.x, which find it through the config_path
+// of the CRI registry config.
+func writeRegistryConfigs(cfg *api.NodeConfig) error {
+	if err := removeStaleRegistryConfigs(cfg.Spec.Containerd.Registries); err != nil {
+		return err
+	}
+	for _, host := range slices.Sorted(maps.Keys(cfg.Spec.Containerd.Registries)) {
+		files, err := generateRegistryConfig(host, cfg.Spec.Containerd.Registries[host])
+		if err != nil {
+			return err
+		}
+		files[path.Join(registryConfigRoot, host, registryConfigMarker)] = nil
+		zap.L().Info("Writing containerd registry config..", zap.String("host", host))
+		for _, filePath := range slices.Sorted(maps.Keys(files)) {
+			if err := util.WriteFileWithDir(filePath, files[filePath], configPerm); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// removeStaleRegistryConfigs removes the directories of the registries that
+// nodeadm configured before, but which are no longer in registries.
+func removeStaleRegistryConfigs(registries map[string]api.RegistryOptions) error {
+	entries, err := os.ReadDir(util.RootedPath(registryConfigRoot))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if _, ok := registries[entry.Name()]; ok || !entry.IsDir() {
+			continue
+		}
+		hostDir := util.RootedPath(path.Join(registryConfigRoot, entry.Name()))
+		if _, err := os.Stat(path.Join(hostDir, registryConfigMarker)); err != nil {
+			continue
+		}
+		zap.L().Info("Removing containerd registry config..", zap.String("host", entry.Name()))
+		if err := os.RemoveAll(hostDir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generateRegistryConfig returns the contents of the hosts.toml of a registry
+// and of its certificate authorities, keyed by path.
+func generateRegistryConfig(host string, registry api.RegistryOptions) (map[string][]byte, error) {
+	hostDir := path.Join(registryConfigRoot, host)
+	files := map[string][]byte{}
+	vars := hostsTemplateVars{
+		Server:     registry.Server,
+		SkipVerify: registry.SkipVerify,
+	}
+	if registry.CA != "" {
+		vars.CA = path.Join(hostDir, "ca.crt")
+		files[vars.CA] = []byte(registry.CA)
+	}
+	for i, mirror := range registry.Mirrors {
+		if len(mirror.Capabilities) == 0 {
+			mirror.Capabilities = defaultMirrorCapabilities
+		}
+		if mirror.CA != "" {
+			caPath := path.Join(hostDir, fmt.Sprintf("mirror-%d-ca.crt", i))
+			files[caPath] = []byte(mirror.CA)
+			mirror.CA = caPath
+		}
+		vars.Mirrors = append(vars.Mirrors, mirror)
+	}
+	var buf bytes.Buffer
+	if err := hostsTemplate.Execute(&buf, vars); err != nil {
+		return nil, err
+	}
+	files[path.Join(hostDir, "hosts.toml")] = append(bytes.TrimSpace(buf.Bytes()), '\n')
+	return files, nil
+}
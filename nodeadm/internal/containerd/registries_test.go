@@ -0,0 +1,141 @@
+package containerd
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/util"
+)
+
+const testCA = "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"
+
+func TestGenerateRegistryConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		host          string
+		registry      api.RegistryOptions
+		expectedFiles map[string]string
+	}{
+		{
+			name: "mirror",
+			host: "docker.io",
+			registry: api.RegistryOptions{
+				Mirrors: []api.RegistryMirror{{URL: "https://mirror.example.com"}},
+			},
+			expectedFiles: map[string]string{
+				"/etc/containerd/certs.d/docker.io/hosts.toml": `
+[host."https://mirror.example.com"]
+capabilities = ["pull", "resolve"]
+`,
+			},
+		},
+		{
+			name: "server and mirrors with certificate authorities",
+			host: "registry.example.com:5000",
+			registry: api.RegistryOptions{
+				Server:     "https://registry.example.com:5000",
+				CA:         testCA,
+				SkipVerify: true,
+				Mirrors: []api.RegistryMirror{
+					{
+						URL:          "https://harbor.example.com/v2/proxy",
+						Capabilities: []api.RegistryCapability{api.RegistryCapabilityPull},
+						CA:           testCA,
+						OverridePath: true,
+					},
+					{
+						URL:        "http://10.0.0.1:5000",
+						SkipVerify: true,
+					},
+				},
+			},
+			expectedFiles: map[string]string{
+				"/etc/containerd/certs.d/registry.example.com:5000/hosts.toml": `server = "https://registry.example.com:5000"
+ca = "/etc/containerd/certs.d/registry.example.com:5000/ca.crt"
+skip_verify = true
+
+[host."https://harbor.example.com/v2/proxy"]
+capabilities = ["pull"]
+ca = "/etc/containerd/certs.d/registry.example.com:5000/mirror-0-ca.crt"
+override_path = true
+
+[host."http://10.0.0.1:5000"]
+capabilities = ["pull", "resolve"]
+skip_verify = true
+`,
+				"/etc/containerd/certs.d/registry.example.com:5000/ca.crt":          testCA,
+				"/etc/containerd/certs.d/registry.example.com:5000/mirror-0-ca.crt": testCA,
+			},
+		},
+		{
+			name:     "default registry",
+			host:     api.DefaultRegistryHost,
+			registry: api.RegistryOptions{SkipVerify: true},
+			expectedFiles: map[string]string{
+				"/etc/containerd/certs.d/_default/hosts.toml": "skip_verify = true\n",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			files, err := generateRegistryConfig(test.host, test.registry)
+			assert.NoError(t, err)
+			actualFiles := map[string]string{}
+			for filePath, data := range files {
+				actualFiles[filePath] = string(data)
+			}
+			expectedFiles := map[string]string{}
+			for filePath, data := range test.expectedFiles {
+				expectedFiles[filePath] = strings.TrimPrefix(data, "\n")
+			}
+			assert.Equal(t, expectedFiles, actualFiles)
+			hostsConfig := files["/etc/containerd/certs.d/"+test.host+"/hosts.toml"]
+			assert.NoError(t, toml.Unmarshal(hostsConfig, &map[string]any{}), "hosts.toml must be valid TOML")
+		})
+	}
+}
+
+func TestRegistryConfigPath(t *testing.T) {
+	cfg := &api.NodeConfig{}
+	resources := fakeResources(2, 4*1024*1024*1024)
+	for schema, plugin := range map[ConfigSchema]string{
+		ConfigSchemaV2: "io.containerd.grpc.v1.cri",
+		ConfigSchemaV3: "io.containerd.cri.v1.images",
+	} {
+		containerdConfig, err := generateContainerdConfig(cfg, resources, schema)
+		assert.NoError(t, err)
+		var configMap map[string]any
+		assert.NoError(t, toml.Unmarshal(containerdConfig, &configMap))
+		registry := configMap["plugins"].(map[string]any)[plugin].(map[string]any)["registry"].(map[string]any)
+		assert.True(t, strings.HasPrefix(registry["config_path"].(string), registryConfigRoot+":"), "config_path of schema %s must include %s", schema, registryConfigRoot)
+	}
+}
+
+func TestWriteRegistryConfigsRemovesStaleRegistries(t *testing.T) {
+	rootDir := t.TempDir()
+	util.SetRootDir(rootDir)
+	t.Cleanup(func() { util.SetRootDir("/") })
+	certsDir := filepath.Join(rootDir, registryConfigRoot)
+	// a registry that nodeadm does not manage, e.g. one configured by the AMI.
+	assert.NoError(t, util.WriteFileWithDir(path.Join(registryConfigRoot, "quay.io", "hosts.toml"), []byte("server = \"https://quay.io\"\n"), 0644))
+
+	cfg := &api.NodeConfig{}
+	cfg.Spec.Containerd.Registries = map[string]api.RegistryOptions{
+		"docker.io": {Server: "https://registry-1.docker.io"},
+		"ghcr.io":   {Server: "https://ghcr.io"},
+	}
+	assert.NoError(t, writeRegistryConfigs(cfg))
+	assert.FileExists(t, filepath.Join(certsDir, "ghcr.io", "hosts.toml"))
+
+	delete(cfg.Spec.Containerd.Registries, "ghcr.io")
+	assert.NoError(t, writeRegistryConfigs(cfg))
+	assert.FileExists(t, filepath.Join(certsDir, "docker.io", "hosts.toml"))
+	assert.FileExists(t, filepath.Join(certsDir, "quay.io", "hosts.toml"))
+	assert.NoDirExists(t, filepath.Join(certsDir, "ghcr.io"))
+}
@@ -1,37 +1,148 @@
 package containerd
 
-import "github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
 
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+	"github.com/pelletier/go-toml/v2"
+	"go.uber.org/zap"
+)
+
+// runtimeConfig is a runtime handler rendered into the containerd config.
 type runtimeConfig struct {
-	RuntimeName       string
-	RuntimeBinaryPath string
+	Name        string
+	RuntimeType string
+	// RuntimePath is the path of the shim binary, which is only set for
+	// runtime types other than runc.
+	RuntimePath string
+	// Options are the TOML key/value pairs of the options table.
+	Options string
 }
 
-type runtimeConfigMixin interface {
-	Apply(*runtimeConfig)
-	Matches(*api.NodeConfig) bool
+type runtimeOptions struct {
+	DefaultRuntimeName string
+	Runtimes           []runtimeConfig
 }
 
 const (
 	defaultRuntimeName       = "runc"
 	defaultRuntimeBinaryPath = "/usr/sbin/runc"
+	runcRuntimeType          = "io.containerd.runc.v2"
 )
 
-var mixins = []runtimeConfigMixin{
-	NewNvidiaRuntimeConfigMixin(),
+var defaultRuntime = api.ContainerdRuntime{
+	Name:       defaultRuntimeName,
+	BinaryPath: defaultRuntimeBinaryPath,
+	Default:    true,
+}
+
+// detectedRuntimes are configured when their binary exists on the instance,
+// before the runtimes of the NodeConfig.
+var detectedRuntimes = []api.ContainerdRuntime{
+	{
+		Name:       "nvidia",
+		BinaryPath: "/usr/bin/nvidia-container-runtime",
+		Default:    true,
+	},
+}
+
+// getRuntimeOptions returns the runtime handlers of the containerd config,
+// based on the available runtime binaries and the runtimes of the NodeConfig.
+func getRuntimeOptions(cfg *api.NodeConfig) (runtimeOptions, error) {
+	return resolveRuntimes(cfg.Spec.Containerd.Runtimes, detectedRuntimes)
+}
+
+func resolveRuntimes(configured []api.ContainerdRuntime, detectable []api.ContainerdRuntime) (runtimeOptions, error) {
+	runtimes := []api.ContainerdRuntime{defaultRuntime}
+	for _, containerdRuntime := range detectable {
+		if _, err := os.Stat(containerdRuntime.BinaryPath); err == nil {
+			zap.L().Info("Configuring detected runtime..", zap.String("name", containerdRuntime.Name), zap.String("binaryPath", containerdRuntime.BinaryPath))
+			runtimes = addRuntime(runtimes, containerdRuntime)
+		}
+	}
+	for _, containerdRuntime := range configured {
+		runtimes = addRuntime(runtimes, containerdRuntime)
+	}
+	var options runtimeOptions
+	for _, containerdRuntime := range runtimes {
+		if containerdRuntime.Default {
+			options.DefaultRuntimeName = containerdRuntime.Name
+		}
+		config, err := newRuntimeConfig(containerdRuntime)
+		if err != nil {
+			return runtimeOptions{}, err
+		}
+		options.Runtimes = append(options.Runtimes, config)
+	}
+	return options, nil
 }
 
-// getRuntimeOptions adds the needed OCI hook options to containerd config.toml
-// based on the instance family and available runtime binaries
-func getRuntimeOptions(cfg *api.NodeConfig) runtimeConfig {
-	options := runtimeConfig{
-		RuntimeName:       defaultRuntimeName,
-		RuntimeBinaryPath: defaultRuntimeBinaryPath,
+// addRuntime replaces the runtime with the same name in place, or appends it.
+// A replaced runtime stays the default, and a new default runtime replaces the
+// previous one.
+func addRuntime(runtimes []api.ContainerdRuntime, containerdRuntime api.ContainerdRuntime) []api.ContainerdRuntime {
+	index := slices.IndexFunc(runtimes, func(existing api.ContainerdRuntime) bool { return existing.Name == containerdRuntime.Name })
+	if index >= 0 && runtimes[index].Default {
+		containerdRuntime.Default = true
 	}
-	for _, mixin := range mixins {
-		if mixin.Matches(cfg) {
-			mixin.Apply(&options)
+	if containerdRuntime.Default {
+		for i := range runtimes {
+			runtimes[i].Default = false
 		}
 	}
-	return options
+	if index < 0 {
+		return append(runtimes, containerdRuntime)
+	}
+	runtimes[index] = containerdRuntime
+	return runtimes
+}
+
+func newRuntimeConfig(containerdRuntime api.ContainerdRuntime) (runtimeConfig, error) {
+	config := runtimeConfig{
+		Name:        containerdRuntime.Name,
+		RuntimeType: runtimeTypeOf(containerdRuntime),
+	}
+	options := map[string]any{}
+	if config.RuntimeType == runcRuntimeType {
+		binaryPath := containerdRuntime.BinaryPath
+		if binaryPath == "" {
+			binaryPath = defaultRuntimeBinaryPath
+		}
+		options["BinaryName"] = binaryPath
+		options["SystemdCgroup"] = true
+	} else {
+		config.RuntimePath = containerdRuntime.BinaryPath
+	}
+	for key, value := range containerdRuntime.Options {
+		decoder := json.NewDecoder(bytes.NewReader(value.Raw))
+		// numbers are kept as written, so that integers stay integers in TOML
+		decoder.UseNumber()
+		var option any
+		if err := decoder.Decode(&option); err != nil {
+			return runtimeConfig{}, fmt.Errorf("failed to decode option %s of runtime %s: %w", key, containerdRuntime.Name, err)
+		}
+		options[key] = option
+	}
+	if len(options) == 0 {
+		return config, nil
+	}
+	var buf bytes.Buffer
+	encoder := toml.NewEncoder(&buf).SetTablesInline(true).SetMarshalJSONNumbers(true)
+	if err := encoder.Encode(options); err != nil {
+		return runtimeConfig{}, fmt.Errorf("failed to encode options of runtime %s: %w", containerdRuntime.Name, err)
+	}
+	config.Options = strings.TrimSpace(buf.String())
+	return config, nil
+}
+
+func runtimeTypeOf(containerdRuntime api.ContainerdRuntime) string {
+	if containerdRuntime.RuntimeType == "" {
+		return runcRuntimeType
+	}
+	return containerdRuntime.RuntimeType
 }
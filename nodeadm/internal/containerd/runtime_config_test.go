@@ -7,32 +7,104 @@ import (
 
 	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
 	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 func TestDefaultRuntimeOptions(t *testing.T) {
-	expectedRuntimeConfig := runtimeConfig{
-		RuntimeName:       defaultRuntimeName,
-		RuntimeBinaryPath: defaultRuntimeBinaryPath,
+	expectedRuntimeOptions := runtimeOptions{
+		DefaultRuntimeName: defaultRuntimeName,
+		Runtimes: []runtimeConfig{{
+			Name:        defaultRuntimeName,
+			RuntimeType: runcRuntimeType,
+			Options:     "BinaryName = '/usr/sbin/runc'\nSystemdCgroup = true",
+		}},
 	}
-	actualRuntimeConfig := getRuntimeOptions(&api.NodeConfig{})
+	actualRuntimeOptions, err := resolveRuntimes(nil, nil)
+	assert.NoError(t, err)
 
-	assert.Equal(t, expectedRuntimeConfig, actualRuntimeConfig)
+	assert.Equal(t, expectedRuntimeOptions, actualRuntimeOptions)
 }
 
-func TestNvidiaRuntimeOptionsMixin(t *testing.T) {
+func TestNvidiaRuntimeOptions(t *testing.T) {
 	mockNvidiaContainerRuntimePath := filepath.Join(t.TempDir(), "nvidia-container-runtime")
 	_, err := os.Create(mockNvidiaContainerRuntimePath)
 	assert.NoError(t, err)
 
-	mixin := nvidiaRuntimeConfigMixin{runtimeBinaryPath: mockNvidiaContainerRuntimePath}
-	expectedRuntimeConfig := runtimeConfig{
-		RuntimeName:       nvidiaRuntimeName,
-		RuntimeBinaryPath: mockNvidiaContainerRuntimePath,
+	detectable := []api.ContainerdRuntime{
+		{Name: "nvidia", BinaryPath: mockNvidiaContainerRuntimePath, Default: true},
+		{Name: "missing", BinaryPath: filepath.Join(t.TempDir(), "missing-runtime"), Default: true},
+	}
+	actualRuntimeOptions, err := resolveRuntimes(nil, detectable)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "nvidia", actualRuntimeOptions.DefaultRuntimeName)
+	assert.Equal(t, []runtimeConfig{
+		{
+			Name:        defaultRuntimeName,
+			RuntimeType: runcRuntimeType,
+			Options:     "BinaryName = '/usr/sbin/runc'\nSystemdCgroup = true",
+		},
+		{
+			Name:        "nvidia",
+			RuntimeType: runcRuntimeType,
+			Options:     "BinaryName = '" + mockNvidiaContainerRuntimePath + "'\nSystemdCgroup = true",
+		},
+	}, actualRuntimeOptions.Runtimes)
+}
+
+func TestConfiguredRuntimeOptions(t *testing.T) {
+	configured := []api.ContainerdRuntime{
+		{
+			Name:        "runsc",
+			RuntimeType: "io.containerd.runsc.v1",
+			Options: api.InlineDocument{
+				"TypeUrl":    runtime.RawExtension{Raw: []byte(`"io.containerd.runsc.v1.options"`)},
+				"ConfigPath": runtime.RawExtension{Raw: []byte(`"/etc/containerd/runsc.toml"`)},
+			},
+		},
+		{
+			Name:        "kata",
+			RuntimeType: "io.containerd.kata.v2",
+			BinaryPath:  "/opt/kata/bin/containerd-shim-kata-v2",
+			Default:     true,
+		},
+		{
+			Name: "runc",
+			Options: api.InlineDocument{
+				"SystemdCgroup": runtime.RawExtension{Raw: []byte(`false`)},
+				"IoUid":         runtime.RawExtension{Raw: []byte(`1000`)},
+			},
+		},
 	}
-	assert.True(t, mixin.Matches(&api.NodeConfig{}))
+	actualRuntimeOptions, err := resolveRuntimes(configured, nil)
+	assert.NoError(t, err)
 
-	var actualRuntimeConfig runtimeConfig
-	mixin.Apply(&actualRuntimeConfig)
+	assert.Equal(t, runtimeOptions{
+		DefaultRuntimeName: "kata",
+		Runtimes: []runtimeConfig{
+			{
+				Name:        defaultRuntimeName,
+				RuntimeType: runcRuntimeType,
+				Options:     "BinaryName = '/usr/sbin/runc'\nIoUid = 1000\nSystemdCgroup = false",
+			},
+			{
+				Name:        "runsc",
+				RuntimeType: "io.containerd.runsc.v1",
+				Options:     "ConfigPath = '/etc/containerd/runsc.toml'\nTypeUrl = 'io.containerd.runsc.v1.options'",
+			},
+			{
+				Name:        "kata",
+				RuntimeType: "io.containerd.kata.v2",
+				RuntimePath: "/opt/kata/bin/containerd-shim-kata-v2",
+			},
+		},
+	}, actualRuntimeOptions)
+}
+
+func TestReplacedRuntimeStaysDefault(t *testing.T) {
+	configured := []api.ContainerdRuntime{{Name: "runc", BinaryPath: "/usr/local/bin/runc"}}
+	actualRuntimeOptions, err := resolveRuntimes(configured, nil)
+	assert.NoError(t, err)
 
-	assert.Equal(t, expectedRuntimeConfig, actualRuntimeConfig)
+	assert.Equal(t, defaultRuntimeName, actualRuntimeOptions.DefaultRuntimeName)
 }
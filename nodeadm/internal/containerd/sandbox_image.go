@@ -1,13 +1,17 @@
 package containerd
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 
 	"go.uber.org/zap"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
 
 	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/daemon"
 	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/system"
 	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/util"
 )
@@ -32,7 +36,7 @@ After=soci-snapshotter.service
 // Type=notify, systemd will not consider it active until its gRPC server sends
 // READY=1. This guarantees that when EnsureRunning() returns after starting
 // containerd, the SOCI snapshotter is fully initialized and ready to serve requests.
-func writeSOCIServiceDependency(cfg *api.NodeConfig, resources system.Resources) error {
+func writeSOCIServiceDependency(cfg *api.NodeConfig, resources system.Resources, daemonManager daemon.DaemonManager) error {
 	if !UseSOCISnapshotter(cfg, resources) {
 		return nil
 	}
@@ -40,8 +44,8 @@ func writeSOCIServiceDependency(cfg *api.NodeConfig, resources system.Resources)
 	if err := util.WriteFileWithDir(sociDependencyDropInPath, []byte(sociDependencyDropIn), configPerm); err != nil {
 		return fmt.Errorf("writing SOCI dependency drop-in: %w", err)
 	}
-	if output, err := exec.Command("systemctl", "daemon-reload").CombinedOutput(); err != nil {
-		return fmt.Errorf("reloading systemd after writing drop-in: %w, output: %s", err, string(output))
+	if err := daemonManager.DaemonReload(); err != nil {
+		return fmt.Errorf("reloading systemd after writing drop-in: %w", err)
 	}
 	return nil
 }
@@ -67,18 +71,64 @@ func importSandboxImageForSOCI() error {
 	}
 
 	zap.L().Info("Importing pause image into SOCI snapshotter", zap.String("path", pauseImageArchive))
-	cmd := exec.Command("ctr",
-		"--namespace", "k8s.io",
-		"images", "import",
-		"--snapshotter", "soci",
-		"--discard-unpacked-layers=false",
-		"--local",
-		pauseImageArchive,
-	)
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("importing pause image into SOCI snapshotter: %w, output: %s", err, string(output))
+	if err := importImageArchive(pauseImageArchive, sociSnapshotter); err != nil {
+		return fmt.Errorf("importing pause image into SOCI snapshotter: %w", err)
 	}
 	zap.L().Info("Successfully imported pause image into SOCI snapshotter")
 	return nil
 }
+
+// importImageArchive imports the images of an archive into the namespace of
+// the CRI plugin, and unpacks them with the snapshotter that containerd is
+// configured with.
+func importImageArchive(archive string, snapshotter string) error {
+	zap.L().Info("Importing image archive", zap.String("path", archive), zap.String("snapshotter", snapshotter))
+	// #nosec G204 // the archive is from the NodeConfig, and ctr does not use a shell
+	output, err := exec.Command("ctr", importImageArchiveArgs(archive, snapshotter)...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("importing image archive %s: %w, output: %s", archive, err, string(output))
+	}
+	return nil
+}
+
+func importImageArchiveArgs(archive string, snapshotter string) []string {
+	args := []string{"--namespace", "k8s.io", "images", "import"}
+	if snapshotter != "" {
+		args = append(args, "--snapshotter", snapshotter)
+	}
+	// the unpacked layers are kept, so that the snapshotter does not have to
+	// fetch them from the registry of the image.
+	return append(args, "--discard-unpacked-layers=false", "--local", archive)
+}
+
+// ensureSandboxImage pulls the sandbox image of the NodeConfig if it does not
+// exist, because containerd cannot create a pod sandbox without it. The image
+// is pinned, like the CRI plugin pins the sandbox image it pulls.
+func ensureSandboxImage(cfg *api.NodeConfig) error {
+	if cfg.Spec.Containerd.SandboxImage == "" {
+		return nil
+	}
+	reference := normalizeImageReference(cfg.Spec.Containerd.SandboxImage)
+	// #nosec G204 // the image is from the NodeConfig, and ctr does not use a shell
+	output, err := exec.Command("ctr", "--namespace", "k8s.io", "images", "list", "--quiet", "name=="+reference).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("listing images: %w, output: %s", err, string(output))
+	}
+	if len(bytes.TrimSpace(output)) > 0 {
+		zap.L().Info("Sandbox image exists", zap.String("reference", reference))
+		return nil
+	}
+	zap.L().Info("Pulling sandbox image", zap.String("reference", reference))
+	ctx, cancel := context.WithTimeout(context.Background(), defaultPrefetchImagesTimeout)
+	defer cancel()
+	conn, err := newCRIClientConn()
+	if err != nil {
+		return fmt.Errorf("connecting to containerd: %w", err)
+	}
+	defer conn.Close()
+	if err := pullImage(ctx, runtimeapi.NewImageServiceClient(conn), api.PrefetchImage{Reference: reference, Pin: true}); err != nil {
+		return fmt.Errorf("sandbox image %s does not exist and could not be pulled: %w", reference, err)
+	}
+	zap.L().Info("Successfully pulled sandbox image", zap.String("reference", reference))
+	return nil
+}
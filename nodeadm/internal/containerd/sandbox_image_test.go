@@ -0,0 +1,26 @@
+package containerd
+
+import (
+	"testing"
+
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportImageArchiveArgs(t *testing.T) {
+	assert.Equal(t,
+		[]string{"--namespace", "k8s.io", "images", "import", "--discard-unpacked-layers=false", "--local", "/opt/images/pause.tar"},
+		importImageArchiveArgs("/opt/images/pause.tar", ""))
+	assert.Equal(t,
+		[]string{"--namespace", "k8s.io", "images", "import", "--snapshotter", "soci", "--discard-unpacked-layers=false", "--local", pauseImageArchive},
+		importImageArchiveArgs(pauseImageArchive, sociSnapshotter))
+}
+
+func TestGetSandboxImage(t *testing.T) {
+	cfg := &api.NodeConfig{
+		Status: api.NodeConfigStatus{Defaults: api.DefaultOptions{SandboxImage: "localhost/kubernetes/pause:latest"}},
+	}
+	assert.Equal(t, "localhost/kubernetes/pause:latest", getSandboxImage(cfg))
+	cfg.Spec.Containerd.SandboxImage = "registry.example.com/eks/pause:3.10"
+	assert.Equal(t, "registry.example.com/eks/pause:3.10", getSandboxImage(cfg))
+}
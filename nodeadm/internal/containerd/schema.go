@@ -0,0 +1,347 @@
+package containerd
+
+// configKind is the TOML type of a value of the containerd config.
+type configKind int
+
+const (
+	kindAny configKind = iota
+	kindString
+	kindBool
+	kindInteger
+	kindArray
+	kindTable
+)
+
+func (k configKind) String() string {
+	switch k {
+	case kindString:
+		return "a string"
+	case kindBool:
+		return "a boolean"
+	case kindInteger:
+		return "an integer"
+	case kindArray:
+		return "an array"
+	case kindTable:
+		return "a table"
+	default:
+		return "any value"
+	}
+}
+
+// configSchema describes the values a key of the containerd config accepts.
+// A table either has known fields, or arbitrary keys whose values all match
+// values. A table with neither is opaque, and its contents are not checked.
+type configSchema struct {
+	kind   configKind
+	fields map[string]*configSchema
+	values *configSchema
+	// runtimeOptions marks the options table of a runtime, whose fields
+	// depend on the runtime type.
+	runtimeOptions bool
+}
+
+var (
+	schemaString  = &configSchema{kind: kindString}
+	schemaBool    = &configSchema{kind: kindBool}
+	schemaInteger = &configSchema{kind: kindInteger}
+	schemaStrings = arrayOf(schemaString)
+	schemaOpaque  = &configSchema{kind: kindTable}
+)
+
+func tableOf(fields map[string]*configSchema) *configSchema {
+	return &configSchema{kind: kindTable, fields: fields}
+}
+
+func mapOf(values *configSchema) *configSchema {
+	return &configSchema{kind: kindTable, values: values}
+}
+
+func arrayOf(values *configSchema) *configSchema {
+	return &configSchema{kind: kindArray, values: values}
+}
+
+// withFields returns a table with the fields of each table, where a field of a
+// later table replaces the same field of an earlier one.
+func withFields(tables ...map[string]*configSchema) map[string]*configSchema {
+	fields := map[string]*configSchema{}
+	for _, table := range tables {
+		for key, value := range table {
+			fields[key] = value
+		}
+	}
+	return fields
+}
+
+// see: https://github.com/containerd/containerd/blob/main/docs/man/containerd-config.toml.5.md
+var topLevelConfigFields = map[string]*configSchema{
+	"version":          schemaInteger,
+	"root":             schemaString,
+	"state":            schemaString,
+	"temp":             schemaString,
+	"plugin_dir":       schemaString,
+	"disabled_plugins": schemaStrings,
+	"required_plugins": schemaStrings,
+	"oom_score":        schemaInteger,
+	"imports":          schemaStrings,
+	"grpc": tableOf(map[string]*configSchema{
+		"address":               schemaString,
+		"tcp_address":           schemaString,
+		"tcp_tls_ca":            schemaString,
+		"tcp_tls_cert":          schemaString,
+		"tcp_tls_key":           schemaString,
+		"tcp_tls_common_name":   schemaString,
+		"uid":                   schemaInteger,
+		"gid":                   schemaInteger,
+		"max_recv_message_size": schemaInteger,
+		"max_send_message_size": schemaInteger,
+	}),
+	"ttrpc": tableOf(map[string]*configSchema{
+		"address": schemaString,
+		"uid":     schemaInteger,
+		"gid":     schemaInteger,
+	}),
+	"debug": tableOf(map[string]*configSchema{
+		"address": schemaString,
+		"uid":     schemaInteger,
+		"gid":     schemaInteger,
+		"level":   schemaString,
+		"format":  schemaString,
+	}),
+	"metrics": tableOf(map[string]*configSchema{
+		"address":        schemaString,
+		"grpc_histogram": schemaBool,
+	}),
+	"cgroup": tableOf(map[string]*configSchema{
+		"path": schemaString,
+	}),
+	"timeouts": mapOf(schemaString),
+	"proxy_plugins": mapOf(tableOf(map[string]*configSchema{
+		"type":         schemaString,
+		"address":      schemaString,
+		"platform":     schemaString,
+		"exports":      mapOf(schemaString),
+		"capabilities": schemaStrings,
+	})),
+	"stream_processors": mapOf(tableOf(map[string]*configSchema{
+		"accepts": schemaStrings,
+		"returns": schemaString,
+		"path":    schemaString,
+		"args":    schemaStrings,
+		"env":     schemaStrings,
+	})),
+}
+
+// the plugins that are configured the same way by containerd 1.7 and 2.x, and
+// whose config is not checked.
+var commonOpaquePlugins = []string{
+	"io.containerd.gc.v1.scheduler",
+	"io.containerd.internal.v1.opt",
+	"io.containerd.internal.v1.tracing",
+	"io.containerd.metadata.v1.bolt",
+	"io.containerd.nri.v1.nri",
+	"io.containerd.runtime.v2.task",
+	"io.containerd.service.v1.diff-service",
+	"io.containerd.service.v1.tasks-service",
+	"io.containerd.snapshotter.v1.blockfile",
+	"io.containerd.snapshotter.v1.btrfs",
+	"io.containerd.snapshotter.v1.devmapper",
+	"io.containerd.snapshotter.v1.native",
+	"io.containerd.snapshotter.v1.overlayfs",
+	"io.containerd.snapshotter.v1.zfs",
+	"io.containerd.tracing.processor.v1.otlp",
+	"io.containerd.transfer.v1.local",
+}
+
+var criStreamingFields = map[string]*configSchema{
+	"disable_tcp_service":   schemaBool,
+	"stream_server_address": schemaString,
+	"stream_server_port":    schemaString,
+	"stream_idle_timeout":   schemaString,
+	"enable_tls_streaming":  schemaBool,
+	"x509_key_pair_streaming": tableOf(map[string]*configSchema{
+		"tls_cert_file": schemaString,
+		"tls_key_file":  schemaString,
+	}),
+}
+
+var criRuntimeFields = map[string]*configSchema{
+	"enable_selinux":                         schemaBool,
+	"selinux_category_range":                 schemaInteger,
+	"max_container_log_line_size":            schemaInteger,
+	"disable_cgroup":                         schemaBool,
+	"disable_apparmor":                       schemaBool,
+	"restrict_oom_score_adj":                 schemaBool,
+	"disable_proc_mount":                     schemaBool,
+	"unset_seccomp_profile":                  schemaString,
+	"tolerate_missing_hugetlb_controller":    schemaBool,
+	"disable_hugetlb_controller":             schemaBool,
+	"device_ownership_from_security_context": schemaBool,
+	"ignore_image_defined_volumes":           schemaBool,
+	"netns_mounts_under_state_dir":           schemaBool,
+	"enable_unprivileged_ports":              schemaBool,
+	"enable_unprivileged_icmp":               schemaBool,
+	"enable_cdi":                             schemaBool,
+	"cdi_spec_dirs":                          schemaStrings,
+	"drain_exec_sync_io_timeout":             schemaString,
+	"ignore_deprecation_warnings":            schemaStrings,
+	"cni": tableOf(map[string]*configSchema{
+		"bin_dir":               schemaString,
+		"bin_dirs":              schemaStrings,
+		"conf_dir":              schemaString,
+		"max_conf_num":          schemaInteger,
+		"conf_template":         schemaString,
+		"ip_pref":               schemaString,
+		"setup_serially":        schemaBool,
+		"use_internal_loopback": schemaBool,
+	}),
+}
+
+var criImageFields = map[string]*configSchema{
+	"max_concurrent_downloads":    schemaInteger,
+	"image_pull_progress_timeout": schemaString,
+	"image_pull_with_sync_fs":     schemaBool,
+	"stats_collect_period":        schemaInteger,
+	"image_decryption": tableOf(map[string]*configSchema{
+		"key_model": schemaString,
+	}),
+}
+
+var criSnapshotFields = map[string]*configSchema{
+	"snapshotter":                  schemaString,
+	"disable_snapshot_annotations": schemaBool,
+	"discard_unpacked_layers":      schemaBool,
+}
+
+var registryAuthSchema = tableOf(map[string]*configSchema{
+	"username":      schemaString,
+	"password":      schemaString,
+	"auth":          schemaString,
+	"identitytoken": schemaString,
+})
+
+var registryFields = map[string]*configSchema{
+	"config_path": schemaString,
+	"mirrors": mapOf(tableOf(map[string]*configSchema{
+		"endpoint": schemaStrings,
+	})),
+	"configs": mapOf(tableOf(map[string]*configSchema{
+		"auth": registryAuthSchema,
+		"tls": tableOf(map[string]*configSchema{
+			"insecure_skip_verify": schemaBool,
+			"ca_file":              schemaString,
+			"cert_file":            schemaString,
+			"key_file":             schemaString,
+		}),
+	})),
+	"headers": mapOf(schemaStrings),
+}
+
+var runtimeFields = map[string]*configSchema{
+	"runtime_type":                    schemaString,
+	"runtime_path":                    schemaString,
+	"pod_annotations":                 schemaStrings,
+	"container_annotations":           schemaStrings,
+	"options":                         {kind: kindTable, runtimeOptions: true},
+	"privileged_without_host_devices": schemaBool,
+	"privileged_without_host_devices_all_devices_allowed": schemaBool,
+	"base_runtime_spec": schemaString,
+	"cni_conf_dir":      schemaString,
+	"cni_max_conf_num":  schemaInteger,
+	"snapshotter":       schemaString,
+}
+
+var runtimeSchemaV2 = tableOf(withFields(runtimeFields, map[string]*configSchema{
+	"runtime_engine": schemaString,
+	"runtime_root":   schemaString,
+	"sandbox_mode":   schemaString,
+}))
+
+var runtimeSchemaV3 = tableOf(withFields(runtimeFields, map[string]*configSchema{
+	"sandboxer":              schemaString,
+	"io_type":                schemaString,
+	"treat_ro_mounts_as_rro": schemaBool,
+}))
+
+var containerdRuntimesFields = map[string]*configSchema{
+	"default_runtime_name":              schemaString,
+	"ignore_blockio_not_enabled_errors": schemaBool,
+	"ignore_rdt_not_enabled_errors":     schemaBool,
+}
+
+// runcOptionsSchema is the options table of the io.containerd.runc.v2 runtime.
+// see: https://github.com/containerd/containerd/blob/main/core/runtime/v2/runc/options/oci.proto
+var runcOptionsSchema = tableOf(map[string]*configSchema{
+	"BinaryName":     schemaString,
+	"CriuImagePath":  schemaString,
+	"CriuPath":       schemaString,
+	"CriuWorkPath":   schemaString,
+	"IoGid":          schemaInteger,
+	"IoUid":          schemaInteger,
+	"NoNewKeyring":   schemaBool,
+	"NoPivotRoot":    schemaBool,
+	"Root":           schemaString,
+	"ShimCgroup":     schemaString,
+	"SystemdCgroup":  schemaBool,
+	"TaskAPIAddress": schemaString,
+	"TaskAPIVersion": schemaInteger,
+})
+
+// configSchemaV2 is the schema of a containerd 1.7 config, version 2.
+// see: https://github.com/containerd/containerd/blob/release/1.7/docs/cri/config.md
+var configSchemaV2 = tableOf(withFields(topLevelConfigFields, map[string]*configSchema{
+	"plugins": tableOf(withFields(opaquePlugins(commonOpaquePlugins...), opaquePlugins(
+		"io.containerd.internal.v1.restart",
+		"io.containerd.monitor.v1.cgroups",
+		"io.containerd.runtime.v1.linux",
+	), map[string]*configSchema{
+		criPluginV2: tableOf(withFields(criStreamingFields, criRuntimeFields, criImageFields, map[string]*configSchema{
+			"sandbox_image":  schemaString,
+			"systemd_cgroup": schemaBool,
+			"registry": tableOf(withFields(registryFields, map[string]*configSchema{
+				"auths": mapOf(registryAuthSchema),
+			})),
+			"containerd": tableOf(withFields(criSnapshotFields, containerdRuntimesFields, map[string]*configSchema{
+				"no_pivot":                   schemaBool,
+				"runtimes":                   mapOf(runtimeSchemaV2),
+				"default_runtime":            runtimeSchemaV2,
+				"untrusted_workload_runtime": runtimeSchemaV2,
+			})),
+		})),
+	})),
+}))
+
+// configSchemaV3 is the schema of a containerd 2.x config, version 3.
+// see: https://github.com/containerd/containerd/blob/main/docs/cri/config.md
+var configSchemaV3 = tableOf(withFields(topLevelConfigFields, map[string]*configSchema{
+	"plugins": tableOf(withFields(opaquePlugins(commonOpaquePlugins...), opaquePlugins(
+		"io.containerd.differ.v1.erofs",
+		"io.containerd.monitor.container.v1.restart",
+		"io.containerd.monitor.task.v1.cgroups",
+		"io.containerd.snapshotter.v1.erofs",
+	), map[string]*configSchema{
+		criPluginV2: tableOf(criStreamingFields),
+		criImagesPluginV3: tableOf(withFields(criSnapshotFields, criImageFields, map[string]*configSchema{
+			"use_local_image_pull": schemaBool,
+			"pinned_images":        mapOf(schemaString),
+			"registry":             tableOf(registryFields),
+			"runtime_platforms": mapOf(tableOf(map[string]*configSchema{
+				"platform":    schemaString,
+				"snapshotter": schemaString,
+			})),
+		})),
+		criRuntimePluginV3: tableOf(withFields(criRuntimeFields, map[string]*configSchema{
+			"containerd": tableOf(withFields(containerdRuntimesFields, map[string]*configSchema{
+				"runtimes": mapOf(runtimeSchemaV3),
+			})),
+		})),
+	})),
+}))
+
+func opaquePlugins(plugins ...string) map[string]*configSchema {
+	fields := map[string]*configSchema{}
+	for _, plugin := range plugins {
+		fields[plugin] = schemaOpaque
+	}
+	return fields
+}
@@ -0,0 +1,191 @@
+package containerd
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+	"github.com/pelletier/go-toml/v2"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// keys that can be written as a field of the path without brackets
+var bareKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// ValidateConfig checks the containerd config of the NodeConfig against the
+// schema of its version, which is either containerd 1.7 or 2.x. It returns
+// errors for values of the wrong type, and warnings for unknown plugins and
+// keys, which containerd ignores.
+func ValidateConfig(cfg *api.NodeConfig) (errs field.ErrorList, warnings field.ErrorList) {
+	if len(cfg.Spec.Containerd.Config) == 0 {
+		return nil, nil
+	}
+	var config map[string]any
+	if err := toml.Unmarshal([]byte(cfg.Spec.Containerd.Config), &config); err != nil {
+		// the syntax of the config is checked by api.ValidateNodeConfig
+		return nil, nil
+	}
+	return validateConfig(config, detectConfigSchema(config), knownRuntimeTypes(cfg), field.NewPath("spec", "containerd", "config"))
+}
+
+// validateMergedConfig checks the containerd config that nodeadm writes, which
+// is the template with the runtimes of the NodeConfig, merged with the
+// containerd config of the NodeConfig, against the schema of the template.
+// The fields of the errors and warnings are the keys of the merged config.
+func validateMergedConfig(cfg *api.NodeConfig, mergedConfig []byte, templateVersion ConfigSchema) (errs field.ErrorList, warnings field.ErrorList) {
+	var config map[string]any
+	if err := toml.Unmarshal(mergedConfig, &config); err != nil {
+		return field.ErrorList{field.Invalid(nil, string(mergedConfig), err.Error())}, nil
+	}
+	return validateConfig(config, templateVersion, knownRuntimeTypes(cfg), nil)
+}
+
+func validateConfig(config map[string]any, schemaVersion ConfigSchema, runtimeTypes map[string]string, fldPath *field.Path) (errs field.ErrorList, warnings field.ErrorList) {
+	schema := configSchemaV2
+	if schemaVersion == ConfigSchemaV3 {
+		schema = configSchemaV3
+	}
+	validator := configValidator{runtimeTypes: runtimeTypes}
+	validator.validate(config, schema, "", fldPath)
+	return validator.errs, validator.warnings
+}
+
+// knownRuntimeTypes returns the runtime type of each runtime that nodeadm
+// configures, because a runtime in the containerd config of the NodeConfig
+// does not need to repeat it.
+func knownRuntimeTypes(cfg *api.NodeConfig) map[string]string {
+	runtimeTypes := map[string]string{defaultRuntimeName: runtimeTypeOf(defaultRuntime)}
+	for _, containerdRuntime := range slices.Concat(detectedRuntimes, cfg.Spec.Containerd.Runtimes) {
+		runtimeTypes[containerdRuntime.Name] = runtimeTypeOf(containerdRuntime)
+	}
+	return runtimeTypes
+}
+
+type configValidator struct {
+	runtimeTypes map[string]string
+	errs         field.ErrorList
+	warnings     field.ErrorList
+}
+
+// validate checks a value against its schema, where key is the key of the
+// value in its parent table.
+func (v *configValidator) validate(value any, schema *configSchema, key string, fldPath *field.Path) {
+	if !matchesKind(value, schema.kind) {
+		var invalidValue any = field.OmitValueType{}
+		if _, ok := value.(map[string]any); !ok {
+			invalidValue = value
+		}
+		v.errs = append(v.errs, field.TypeInvalid(fldPath, invalidValue, "must be "+schema.kind.String()))
+		return
+	}
+	switch schema.kind {
+	case kindArray:
+		if schema.values != nil {
+			for i, element := range value.([]any) {
+				v.validate(element, schema.values, "", fldPath.Index(i))
+			}
+		}
+	case kindTable:
+		table := value.(map[string]any)
+		for _, tableKey := range slices.Sorted(maps.Keys(table)) {
+			keyPath := childPath(fldPath, tableKey)
+			if schema.fields != nil {
+				fieldSchema, ok := schema.fields[tableKey]
+				if !ok {
+					v.warnings = append(v.warnings, unknownKey(keyPath, tableKey, schema.fields))
+					continue
+				}
+				if fieldSchema.runtimeOptions {
+					fieldSchema = v.runtimeOptionsSchema(key, table)
+				}
+				v.validate(table[tableKey], fieldSchema, tableKey, keyPath)
+			} else if schema.values != nil {
+				v.validate(table[tableKey], schema.values, tableKey, keyPath)
+			}
+		}
+	}
+}
+
+// runtimeOptionsSchema returns the schema of the options of a runtime, which
+// is only known for the runc runtime type.
+func (v *configValidator) runtimeOptionsSchema(name string, runtime map[string]any) *configSchema {
+	runtimeType, ok := runtime["runtime_type"].(string)
+	if !ok {
+		runtimeType = v.runtimeTypes[name]
+	}
+	if runtimeType == runcRuntimeType {
+		return runcOptionsSchema
+	}
+	return schemaOpaque
+}
+
+func matchesKind(value any, kind configKind) bool {
+	switch kind {
+	case kindString:
+		_, ok := value.(string)
+		return ok
+	case kindBool:
+		_, ok := value.(bool)
+		return ok
+	case kindInteger:
+		_, ok := value.(int64)
+		return ok
+	case kindArray:
+		_, ok := value.([]any)
+		return ok
+	case kindTable:
+		_, ok := value.(map[string]any)
+		return ok
+	default:
+		return true
+	}
+}
+
+// the largest edit distance of a known key that is suggested for an unknown key
+const maxSuggestionDistance = 2
+
+func unknownKey(fldPath *field.Path, key string, fields map[string]*configSchema) *field.Error {
+	detail := "unknown key, which containerd ignores"
+	suggestionDistance := maxSuggestionDistance + 1
+	for _, known := range slices.Sorted(maps.Keys(fields)) {
+		if distance := editDistance(strings.ToLower(known), strings.ToLower(key)); distance < suggestionDistance {
+			detail = fmt.Sprintf("unknown key, did you mean %s?", known)
+			suggestionDistance = distance
+		}
+	}
+	return field.Invalid(fldPath, key, detail)
+}
+
+// editDistance returns the Levenshtein distance between a and b, which is the
+// number of single character insertions, deletions and substitutions that
+// turn a into b.
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	prev := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		curr := make([]int, len(t)+1)
+		curr[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev = curr
+	}
+	return prev[len(t)]
+}
+
+func childPath(fldPath *field.Path, key string) *field.Path {
+	if bareKeyPattern.MatchString(key) {
+		return fldPath.Child(key)
+	}
+	return fldPath.Key(key)
+}
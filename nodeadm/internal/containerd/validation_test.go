@@ -0,0 +1,260 @@
+package containerd
+
+import (
+	"testing"
+
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestValidateConfig(t *testing.T) {
+	var tests = []struct {
+		name             string
+		config           string
+		runtimes         []api.ContainerdRuntime
+		expectedErrs     []string
+		expectedWarnings []string
+	}{
+		{
+			name: "valid v2 config",
+			config: `
+version = 2
+
+[grpc]
+address = "/run/foo/foo.sock"
+
+[plugins."io.containerd.grpc.v1.cri"]
+sandbox_image = "registry.k8s.io/pause:3.10"
+
+[plugins."io.containerd.grpc.v1.cri".containerd]
+discard_unpacked_layers = false
+
+[plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runc.options]
+SystemdCgroup = true
+
+[plugins."io.containerd.grpc.v1.cri".registry.mirrors."docker.io"]
+endpoint = ["https://mirror.example.com"]
+
+[plugins."io.containerd.gc.v1.scheduler"]
+pause_threshold = 0.02
+`,
+		},
+		{
+			name: "valid v3 config",
+			config: `
+version = 3
+
+[plugins.'io.containerd.cri.v1.images'.pinned_images]
+sandbox = 'registry.k8s.io/pause:3.10'
+
+[plugins.'io.containerd.cri.v1.runtime'.containerd.runtimes.runsc]
+runtime_type = 'io.containerd.runsc.v1'
+sandboxer = 'podsandbox'
+
+[plugins.'io.containerd.cri.v1.runtime'.containerd.runtimes.runsc.options]
+TypeUrl = 'io.containerd.runsc.v1.options'
+`,
+		},
+		{
+			name: "unknown keys",
+			config: `
+version = 2
+
+[plugins."io.containerd.grpc.v1.cri".containerd.runtimes.nvidia.options]
+SystemdCGroup = true
+
+[plugins."io.containerd.grpc.v1.cri".containerd]
+snapshoter = "soci"
+
+[plugins."io.containerd.cri.v1.runtime"]
+enable_cdi = true
+
+[plugins."io.containerd.example.v1.unknown"]
+enabled = true
+
+[unknown]
+`,
+			expectedWarnings: []string{
+				"spec.containerd.config.plugins[io.containerd.cri.v1.runtime]",
+				"spec.containerd.config.plugins[io.containerd.example.v1.unknown]",
+				"spec.containerd.config.plugins[io.containerd.grpc.v1.cri].containerd.runtimes.nvidia.options.SystemdCGroup",
+				"spec.containerd.config.plugins[io.containerd.grpc.v1.cri].containerd.snapshoter",
+				"spec.containerd.config.unknown",
+			},
+		},
+		{
+			name: "v2 keys in v3 config",
+			config: `
+version = 3
+
+[plugins."io.containerd.grpc.v1.cri"]
+sandbox_image = "registry.k8s.io/pause:3.10"
+disable_tcp_service = true
+`,
+			expectedWarnings: []string{
+				"spec.containerd.config.plugins[io.containerd.grpc.v1.cri].sandbox_image",
+			},
+		},
+		{
+			name: "invalid types",
+			config: `
+version = "3"
+oom_score = 1.5
+imports = ["/etc/containerd/conf.d/a.toml", 1]
+
+[plugins.'io.containerd.cri.v1.images']
+pinned_images = "registry.k8s.io/pause:3.10"
+
+[plugins.'io.containerd.cri.v1.runtime'.containerd.runtimes.runc.options]
+SystemdCgroup = "true"
+
+[plugins.'io.containerd.cri.v1.runtime'.containerd.runtimes.runc-debug.options]
+IoUid = "1000"
+
+[plugins.'io.containerd.cri.v1.runtime'.containerd.runtimes.kata.options]
+IoUid = "1000"
+`,
+			runtimes: []api.ContainerdRuntime{
+				{Name: "runc-debug"},
+				{Name: "kata", RuntimeType: "io.containerd.kata.v2"},
+			},
+			expectedErrs: []string{
+				"spec.containerd.config.imports[1]",
+				"spec.containerd.config.oom_score",
+				"spec.containerd.config.plugins[io.containerd.cri.v1.images].pinned_images",
+				"spec.containerd.config.plugins[io.containerd.cri.v1.runtime].containerd.runtimes.runc.options.SystemdCgroup",
+				"spec.containerd.config.plugins[io.containerd.cri.v1.runtime].containerd.runtimes.runc-debug.options.IoUid",
+				"spec.containerd.config.version",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := &api.NodeConfig{
+				Spec: api.NodeConfigSpec{
+					Containerd: api.ContainerdOptions{
+						Config:   api.ContainerdConfig(test.config),
+						Runtimes: test.runtimes,
+					},
+				},
+			}
+			errs, warnings := ValidateConfig(cfg)
+			assert.Equal(t, test.expectedErrs, fieldsOf(errs))
+			assert.Equal(t, test.expectedWarnings, fieldsOf(warnings))
+		})
+	}
+}
+
+func TestValidateConfigSuggestsKey(t *testing.T) {
+	tests := []struct {
+		name           string
+		config         string
+		expectedDetail string
+	}{
+		{
+			name: "case",
+			config: `[plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runc.options]
+SystemdCGroup = true`,
+			expectedDetail: "unknown key, did you mean SystemdCgroup?",
+		},
+		{
+			name: "typo",
+			config: `[plugins."io.containerd.grpc.v1.cri".containerd]
+snapshoter = "overlayfs"`,
+			expectedDetail: "unknown key, did you mean snapshotter?",
+		},
+		{
+			name: "no similar key",
+			config: `[plugins."io.containerd.grpc.v1.cri".containerd]
+imaginary = true`,
+			expectedDetail: "unknown key, which containerd ignores",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := &api.NodeConfig{
+				Spec: api.NodeConfigSpec{
+					Containerd: api.ContainerdOptions{
+						Config: api.ContainerdConfig(test.config),
+					},
+				},
+			}
+			_, warnings := ValidateConfig(cfg)
+			if assert.Len(t, warnings, 1) {
+				assert.Equal(t, test.expectedDetail, warnings[0].Detail)
+			}
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("snapshotter", "snapshotter"))
+	assert.Equal(t, 1, editDistance("snapshotter", "snapshoter"))
+	assert.Equal(t, 2, editDistance("sandbox_image", "sandbox_imaeg"))
+	assert.Equal(t, 3, editDistance("", "abc"))
+}
+
+func TestValidateTemplates(t *testing.T) {
+	cfg := &api.NodeConfig{
+		Spec: api.NodeConfigSpec{
+			FeatureGates: map[api.Feature]bool{api.FastImagePull: true},
+			Containerd: api.ContainerdOptions{
+				Runtimes:       []api.ContainerdRuntime{{Name: "kata", RuntimeType: "io.containerd.kata.v2", BinaryPath: "/opt/kata/bin/containerd-shim-kata-v2"}},
+				PrefetchImages: []api.PrefetchImage{{Reference: "public.ecr.aws/eks/aws-load-balancer-controller:v2.7.1", Pin: true}},
+			},
+		},
+		Status: api.NodeConfigStatus{KubeletVersion: "v1.32.0"},
+	}
+	for _, templateVersion := range []ConfigSchema{ConfigSchemaV2, ConfigSchemaV3} {
+		config, err := generateContainerdConfig(cfg, fakeResources(8, 16*1024*1024*1024), templateVersion)
+		assert.NoError(t, err)
+		templateCfg := cfg.DeepCopy()
+		templateCfg.Spec.Containerd.Config = api.ContainerdConfig(config)
+		errs, warnings := ValidateConfig(templateCfg)
+		assert.Empty(t, errs, "template version %s", templateVersion)
+		assert.Empty(t, warnings, "template version %s", templateVersion)
+	}
+}
+
+func TestValidateMergedConfig(t *testing.T) {
+	cfg := &api.NodeConfig{
+		Spec: api.NodeConfigSpec{
+			Containerd: api.ContainerdOptions{
+				Config: `[plugins."io.containerd.grpc.v1.cri".containerd]
+discard_unpacked_layers = false`,
+				Runtimes: []api.ContainerdRuntime{{
+					Name:        "custom",
+					RuntimeType: "io.containerd.runc.v2",
+					Options:     api.InlineDocument{"SystemdCgroup": {Raw: []byte(`"yes"`)}},
+				}},
+			},
+		},
+		Status: api.NodeConfigStatus{KubeletVersion: "v1.32.0"},
+	}
+	errs, _ := ValidateConfig(cfg)
+	assert.Empty(t, errs)
+
+	for templateVersion, expectedField := range map[ConfigSchema]string{
+		ConfigSchemaV2: `plugins[io.containerd.grpc.v1.cri].containerd.runtimes.custom.options.SystemdCgroup`,
+		ConfigSchemaV3: `plugins[io.containerd.cri.v1.runtime].containerd.runtimes.custom.options.SystemdCgroup`,
+	} {
+		template, err := generateContainerdConfig(cfg, fakeResources(8, 16*1024*1024*1024), templateVersion)
+		assert.NoError(t, err)
+		userConfig, err := migrateUserConfig(cfg.Spec.Containerd.Config, templateVersion)
+		assert.NoError(t, err)
+		mergedConfig, err := combineTOMLConfigs(template, []byte(userConfig))
+		assert.NoError(t, err)
+		errs, warnings := validateMergedConfig(cfg, mergedConfig, templateVersion)
+		assert.Equal(t, []string{expectedField}, fieldsOf(errs), "template version %s", templateVersion)
+		assert.Empty(t, warnings, "template version %s", templateVersion)
+	}
+}
+
+func fieldsOf(errs field.ErrorList) []string {
+	var fields []string
+	for _, err := range errs {
+		fields = append(fields, err.Field)
+	}
+	return fields
+}
@@ -0,0 +1,75 @@
+package daemon
+
+import (
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+var _ DaemonManager = &DryRunDaemonManager{}
+
+// DryRunDaemonManager records the actions that would be taken on daemons
+// instead of performing them, so that the effects of a command can be
+// inspected without changing the state of the host.
+type DryRunDaemonManager struct {
+	mu      sync.Mutex
+	actions []string
+}
+
+func NewDryRunDaemonManager() *DryRunDaemonManager {
+	return &DryRunDaemonManager{}
+}
+
+// Actions returns the recorded actions in the order they were requested.
+func (m *DryRunDaemonManager) Actions() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.actions...)
+}
+
+func (m *DryRunDaemonManager) record(action string, name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(name) > 0 {
+		action = fmt.Sprintf("%s %s", action, getServiceUnitName(name))
+	}
+	zap.L().Info("Skipping daemon action in dry-run mode", zap.String("action", action))
+	m.actions = append(m.actions, action)
+}
+
+func (m *DryRunDaemonManager) StartDaemon(name string) error {
+	m.record("start", name)
+	return nil
+}
+
+func (m *DryRunDaemonManager) StopDaemon(name string) error {
+	m.record("stop", name)
+	return nil
+}
+
+func (m *DryRunDaemonManager) RestartDaemon(name string) error {
+	m.record("restart", name)
+	return nil
+}
+
+func (m *DryRunDaemonManager) GetDaemonStatus(name string) (DaemonStatus, error) {
+	return DaemonStatusUnknown, nil
+}
+
+func (m *DryRunDaemonManager) EnableDaemon(name string) error {
+	m.record("enable", name)
+	return nil
+}
+
+func (m *DryRunDaemonManager) DisableDaemon(name string) error {
+	m.record("disable", name)
+	return nil
+}
+
+func (m *DryRunDaemonManager) DaemonReload() error {
+	m.record("daemon-reload", "")
+	return nil
+}
+
+func (m *DryRunDaemonManager) Close() {}
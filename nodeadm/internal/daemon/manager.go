@@ -1,5 +1,7 @@
 package daemon
 
+import "fmt"
+
 type DaemonStatus string
 
 const (
@@ -26,6 +28,13 @@ type DaemonManager interface {
 	// DisableDaemon disables the daemon with the given name.
 	// If the daemon is not enabled, this is a no-op.
 	DisableDaemon(name string) error
+	// DaemonReload reloads the unit configuration of the service manager so
+	// that changes to unit files and drop-ins take effect.
+	DaemonReload() error
 	// Close cleans up any underlying resources used by the daemon manager.
 	Close()
 }
+
+func getServiceUnitName(name string) string {
+	return fmt.Sprintf("%s.service", name)
+}
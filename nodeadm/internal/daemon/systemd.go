@@ -100,12 +100,12 @@ func (m *systemdDaemonManager) DisableDaemon(name string) error {
 	return nil
 }
 
-func (m *systemdDaemonManager) Close() {
-	m.conn.Close()
+func (m *systemdDaemonManager) DaemonReload() error {
+	return m.conn.ReloadContext(context.TODO())
 }
 
-func getServiceUnitName(name string) string {
-	return fmt.Sprintf("%s.service", name)
+func (m *systemdDaemonManager) Close() {
+	m.conn.Close()
 }
 
 func (m *systemdDaemonManager) waitForStatus(ctx context.Context, name string, targetStatus DaemonStatus) error {
@@ -5,10 +5,11 @@ import (
 	_ "embed"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"net"
 	"net/url"
-	"os"
 	"path"
+	"slices"
 	"strings"
 	"time"
 
@@ -16,6 +17,7 @@ import (
 	"golang.org/x/mod/semver"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8skubelet "k8s.io/kubelet/config/v1beta1"
 
@@ -66,6 +68,7 @@ type kubeletConfig struct {
 	ServerTLSBootstrap              bool                             `json:"serverTLSBootstrap"`
 	ShutdownGracePeriod             *metav1.Duration                 `json:"shutdownGracePeriod,omitempty"`
 	ShutdownGracePeriodCriticalPods *metav1.Duration                 `json:"shutdownGracePeriodCriticalPods,omitempty"`
+	SystemReserved                  map[string]string                `json:"systemReserved,omitempty"`
 	SystemReservedCgroup            *string                          `json:"systemReservedCgroup,omitempty"`
 	TLSCipherSuites                 []string                         `json:"tlsCipherSuites"`
 	metav1.TypeMeta                 `json:",inline"`
@@ -191,20 +194,17 @@ func (ksc *kubeletConfig) withOutpostSetup(cfg *api.NodeConfig) error {
 		}
 		output := strings.Join(ipHostMappings, "\n") + "\n"
 		// append to /etc/hosts file with shuffled mappings of "IP address to API server domain name"
-		f, err := os.OpenFile("/etc/hosts", os.O_APPEND|os.O_WRONLY, kubeletConfigPerm)
-		if err != nil {
-			return err
-		}
-		defer f.Close()
-		if _, err := f.WriteString(output); err != nil {
+		if err := util.AppendFileWithDir("/etc/hosts", []byte(output), kubeletConfigPerm); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (ksc *kubeletConfig) withNodeLabels(flags map[string]string, nodeLabelFuncs map[string]LabelProvider) {
-	var nodeLabels []string
+// withNodeLabels adds the labels from the label providers and the labels of
+// the NodeConfig, which take precedence, to the kubelet's node-labels flag.
+func (ksc *kubeletConfig) withNodeLabels(cfg *api.NodeConfig, flags map[string]string, nodeLabelFuncs map[string]LabelProvider) {
+	labels := make(map[string]string)
 	for nodeLabelKey, provider := range nodeLabelFuncs {
 		nodeLabelValue, ok, err := provider.Get()
 		if err != nil {
@@ -214,7 +214,12 @@ func (ksc *kubeletConfig) withNodeLabels(flags map[string]string, nodeLabelFuncs
 		if !ok {
 			continue
 		}
-		nodeLabel := fmt.Sprintf("%s=%s", nodeLabelKey, nodeLabelValue)
+		labels[nodeLabelKey] = nodeLabelValue
+	}
+	maps.Copy(labels, cfg.Spec.Kubelet.Labels)
+	var nodeLabels []string
+	for _, nodeLabelKey := range slices.Sorted(maps.Keys(labels)) {
+		nodeLabel := fmt.Sprintf("%s=%s", nodeLabelKey, labels[nodeLabelKey])
 		zap.L().Info("Adding node label", zap.String("label", nodeLabel))
 		nodeLabels = append(nodeLabels, nodeLabel)
 	}
@@ -223,6 +228,17 @@ func (ksc *kubeletConfig) withNodeLabels(flags map[string]string, nodeLabelFuncs
 	}
 }
 
+// withNodeTaints registers the node with the taints of the NodeConfig.
+func (ksc *kubeletConfig) withNodeTaints(cfg *api.NodeConfig) {
+	for _, taint := range cfg.Spec.Kubelet.Taints {
+		ksc.RegisterWithTaints = append(ksc.RegisterWithTaints, v1.Taint{
+			Key:    taint.Key,
+			Value:  taint.Value,
+			Effect: v1.TaintEffect(taint.Effect),
+		})
+	}
+}
+
 func (ksc *kubeletConfig) withNodeIp(cfg *api.NodeConfig, flags map[string]string, imdsClient imds.IMDSClient) error {
 	nodeIp, err := getNodeIp(context.TODO(), cfg, imdsClient)
 	if err != nil {
@@ -271,20 +287,51 @@ func (ksc *kubeletConfig) withCloudProvider(cfg *api.NodeConfig, flags map[strin
 }
 
 // Override the kubelet config with reserved cgroup values on behalf of the user
-func (ksc *kubeletConfig) withDefaultReservedResources(cfg *api.NodeConfig, resources system.Resources) {
+// The instanceInfo is nil when the instance type is not known.
+func (ksc *kubeletConfig) withDefaultReservedResources(cfg *api.NodeConfig, resources system.Resources, instanceInfo *util.InstanceInfo) {
 	ksc.SystemReservedCgroup = ptr.String("/system")
 	ksc.KubeReservedCgroup = ptr.String("/runtime")
-	if instanceInfo, err := GetInstanceInfo(context.TODO(), cfg.Status.Instance.Region, cfg.Status.Instance.Type); err != nil {
-		zap.L().Warn("Failed to retrieve instance info, falling back to default", zap.Error(err))
+	if instanceInfo == nil {
 		ksc.MaxPods = defaultMaxPods
+		instanceInfo = &util.InstanceInfo{}
 	} else {
-		ksc.MaxPods = CalcMaxPods(instanceInfo, cfg.Spec.Kubelet.MaxPodsExpression)
+		milliCores, err := resources.GetMilliNumCores()
+		if err != nil {
+			zap.L().Warn("Failed to get vCPUs for max pods", zap.Error(err))
+		}
+		vcpus := milliCores / 1000
+		ksc.MaxPods = CalcMaxPodsWithStrategy(*instanceInfo, cfg.Spec.Kubelet.MaxPodsStrategy, vcpus, cfg.Spec.Kubelet.MaxPodsExpression)
 	}
-	ksc.KubeReserved = map[string]string{
-		"cpu":               fmt.Sprintf("%dm", getCPUMillicoresToReserve(resources)),
-		"ephemeral-storage": "1Gi",
-		"memory":            fmt.Sprintf("%dMi", getMemoryMebibytesToReserve(ksc.MaxPods)),
+	vars := reservedExpressionVars(cfg, resources, *instanceInfo, ksc.MaxPods)
+	ksc.KubeReserved = evaluateReservedExpressions(cfg.Spec.Kubelet.KubeReservedExpressions, vars, defaultKubeReserved(cfg, resources, ksc.MaxPods)).toResourceList()
+	ksc.SystemReserved = evaluateReservedExpressions(cfg.Spec.Kubelet.SystemReservedExpressions, vars, reservedAmounts{}).toResourceList()
+}
+
+// withHugepages checks that the memory the kubelet reserves still fits in the
+// memory left after the huge pages of the NodeConfig are pre-allocated. The
+// kubelet subtracts pre-allocated huge pages from the allocatable memory, so
+// pods could otherwise not be scheduled on the node.
+func (ksc *kubeletConfig) withHugepages(cfg *api.NodeConfig, resources system.Resources) error {
+	hugepagesBytes, err := plannedHugepagesBytes(cfg, resources)
+	if err != nil || hugepagesBytes == 0 {
+		return err
+	}
+	onlineMemory, err := resources.GetOnlineMemory()
+	if err != nil {
+		return err
 	}
+	reservedBytes := int64(0)
+	for _, reserved := range []string{ksc.KubeReserved[reservedMemory], ksc.SystemReserved[reservedMemory], ksc.EvictionHard["memory.available"]} {
+		if quantity, err := resource.ParseQuantity(reserved); err == nil {
+			reservedBytes += quantity.Value()
+		}
+	}
+	allocatableBytes := onlineMemory - hugepagesBytes - reservedBytes
+	if allocatableBytes <= 0 {
+		return fmt.Errorf("huge pages of %d bytes leave no allocatable memory of the %d bytes online, with %d bytes reserved", hugepagesBytes, onlineMemory, reservedBytes)
+	}
+	zap.L().Info("Reserved memory for huge pages", zap.Int64("hugepagesBytes", hugepagesBytes), zap.Int64("allocatableBytes", allocatableBytes))
+	return nil
 }
 
 func (ksc *kubeletConfig) withImageServiceEndpoint(cfg *api.NodeConfig, resources system.Resources) {
@@ -299,6 +346,33 @@ func (ksc *kubeletConfig) withRuntimeCgroups(flags map[string]string) {
 	flags["runtime-cgroups"] = "/runtime.slice/containerd.service"
 }
 
+// usesInstanceTypeDetails returns true if the NodeConfig uses details of the
+// instance type beyond those that partial instance info has. Expressions only
+// use them when they reference one of the instance type variables.
+func usesInstanceTypeDetails(cfg *api.NodeConfig) bool {
+	kubelet := cfg.Spec.Kubelet
+	if kubelet.MaxPodsStrategy.PrefixDelegation || slices.Contains(kubelet.AutoLabels, api.AutoLabelInstanceType) {
+		return true
+	}
+	if len(kubelet.MaxPodsExpression) > 0 {
+		if env, err := api.NewMaxPodsExpressionEnv(); err == nil && api.ReferencesInstanceTypeVariables(env, kubelet.MaxPodsExpression) {
+			return true
+		}
+	}
+	env, err := api.NewReservedExpressionEnv()
+	if err != nil {
+		return false
+	}
+	for _, expressions := range []api.ReservedResourceExpressions{kubelet.KubeReservedExpressions, kubelet.SystemReservedExpressions} {
+		for _, expression := range []string{expressions.CPU, expressions.Memory, expressions.EphemeralStorage} {
+			if len(expression) > 0 && api.ReferencesInstanceTypeVariables(env, expression) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (k *kubelet) generateKubeletConfig(cfg *api.NodeConfig) (*kubeletConfig, error) {
 	kubeletConfig := defaultKubeletSubConfig()
 
@@ -314,16 +388,26 @@ func (k *kubelet) generateKubeletConfig(cfg *api.NodeConfig) (*kubeletConfig, er
 
 	kubeletConfig.withVersionToggles(cfg)
 	kubeletConfig.withCloudProvider(cfg, k.flags)
-	kubeletConfig.withDefaultReservedResources(cfg, k.resources)
+	var instanceInfo *util.InstanceInfo
+	if info, err := GetInstanceInfo(context.TODO(), cfg.Status.Instance.Region, cfg.Status.Instance.Type, usesInstanceTypeDetails(cfg)); err != nil {
+		zap.L().Warn("Failed to retrieve instance info, falling back to default", zap.Error(err))
+	} else {
+		instanceInfo = &info
+	}
+	kubeletConfig.withDefaultReservedResources(cfg, k.resources, instanceInfo)
+	if err := kubeletConfig.withHugepages(cfg, k.resources); err != nil {
+		return nil, err
+	}
 	kubeletConfig.withImageServiceEndpoint(cfg, k.resources)
 	kubeletConfig.withRuntimeCgroups(k.flags)
 
-	nodeLabelFuncs := map[string]LabelProvider{}
+	nodeLabelFuncs := autoLabelProviders(cfg.Spec.Kubelet.AutoLabels, system.RealFileSystem{}, k.imdsClient, instanceInfo)
 	if semver.Compare(cfg.Status.KubeletVersion, "v1.35.0") >= 0 {
 		// see: https://github.com/NVIDIA/gpu-operator/commit/e25291b86cf4542ac62d8635cda4bd653c4face3
 		nodeLabelFuncs["nvidia.com/gpu.present"] = NvidiaGPULabel{fs: system.RealFileSystem{}}
 	}
-	kubeletConfig.withNodeLabels(k.flags, nodeLabelFuncs)
+	kubeletConfig.withNodeLabels(cfg, k.flags, nodeLabelFuncs)
+	kubeletConfig.withNodeTaints(cfg)
 
 	return &kubeletConfig, nil
 }
@@ -340,6 +424,11 @@ func (k *kubelet) writeKubeletConfig(cfg *api.NodeConfig) error {
 	if err != nil {
 		return err
 	}
+	if len(cfg.Status.KubeletConfigOverrides) > 0 {
+		if kubeletConfigBytes, err = removeOverriddenDefaults(kubeletConfigBytes, cfg.Status.KubeletConfigOverrides); err != nil {
+			return err
+		}
+	}
 
 	configPath := path.Join(kubeletConfigRoot, kubeletConfigFile)
 	k.flags["config"] = configPath
@@ -350,6 +439,10 @@ func (k *kubelet) writeKubeletConfig(cfg *api.NodeConfig) error {
 	}
 
 	if len(cfg.Spec.Kubelet.Config) > 0 {
+		if _, ok := cfg.Spec.Kubelet.Config["registerWithTaints"]; ok && len(cfg.Spec.Kubelet.Taints) > 0 {
+			// lists in drop-in configs replace those of the base config.
+			zap.L().Warn("registerWithTaints in the kubelet config replaces the taints in spec.kubelet.taints")
+		}
 		dirPath := path.Join(kubeletConfigRoot, kubeletConfigDir)
 		k.flags["config-dir"] = dirPath
 		if semver.Compare(cfg.Status.KubeletVersion, "v1.30.0") < 0 {
@@ -378,6 +471,30 @@ func (k *kubelet) writeKubeletConfig(cfg *api.NodeConfig) error {
 	return nil
 }
 
+// removeOverriddenDefaults removes the maps of the generated kubelet config
+// that the user config replaces or removes with a patch directive. The kubelet
+// merges the maps of drop-in configs into those of the base config, so the
+// user config could otherwise only add to them.
+func removeOverriddenDefaults(kubeletConfigBytes []byte, overrides [][]string) ([]byte, error) {
+	var kubeletConfigMap map[string]interface{}
+	if err := json.Unmarshal(kubeletConfigBytes, &kubeletConfigMap); err != nil {
+		return nil, err
+	}
+	for _, keys := range overrides {
+		m := kubeletConfigMap
+		for _, key := range keys[:len(keys)-1] {
+			if m, _ = m[key].(map[string]interface{}); m == nil {
+				break
+			}
+		}
+		if m != nil {
+			zap.L().Info("Removing default of kubelet config overridden by user config", zap.Strings("path", keys))
+			delete(m, keys[len(keys)-1])
+		}
+	}
+	return json.MarshalIndent(kubeletConfigMap, "", strings.Repeat(" ", 4))
+}
+
 func getProviderId(availabilityZone, instanceId string) string {
 	return fmt.Sprintf("aws:///%s/%s", availabilityZone, instanceId)
 }
@@ -2,13 +2,19 @@ package kubelet
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"os"
 	"testing"
 
 	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
 	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/aws/imds"
 	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/containerd"
 	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/system"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/util"
 	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 func TestKubeletCredentialProvidersFeatureFlag(t *testing.T) {
@@ -108,6 +114,7 @@ func TestMutableCSINodeAllocatableCountFeatureGate(t *testing.T) {
 }
 
 func TestGenerateKubeletConfig(t *testing.T) {
+	withFailingEC2(t)
 	mockIMDS := &imds.FakeIMDSClient{
 		GetPropertyFunc: func(ctx context.Context, prop imds.IMDSProperty) (string, error) {
 			if prop == imds.LocalIPv4 {
@@ -145,3 +152,218 @@ func TestGenerateKubeletConfig(t *testing.T) {
 	assert.Equal(t, "external", k.flags["cloud-provider"])
 	assert.Equal(t, "aws:///us-west-2a/i-1234567890abcdef0", *cfg.ProviderID)
 }
+
+type fakeLabelProvider struct {
+	value string
+	ok    bool
+}
+
+func (p fakeLabelProvider) Get() (string, bool, error) {
+	return p.value, p.ok, nil
+}
+
+func TestNodeLabels(t *testing.T) {
+	kubeletConfig := defaultKubeletSubConfig()
+	nodeConfig := api.NodeConfig{
+		Spec: api.NodeConfigSpec{
+			Kubelet: api.KubeletOptions{
+				Labels: map[string]string{
+					"example.com/team": "a",
+					"example.com/gpu":  "override",
+				},
+			},
+		},
+	}
+	flags := make(map[string]string)
+	kubeletConfig.withNodeLabels(&nodeConfig, flags, map[string]LabelProvider{
+		"nvidia.com/gpu.present": fakeLabelProvider{value: "true", ok: true},
+		"example.com/gpu":        fakeLabelProvider{value: "auto", ok: true},
+		"example.com/absent":     fakeLabelProvider{},
+	})
+	assert.Equal(t, "example.com/gpu=override,example.com/team=a,nvidia.com/gpu.present=true", flags["node-labels"])
+
+	flags = make(map[string]string)
+	kubeletConfig.withNodeLabels(&api.NodeConfig{}, flags, nil)
+	assert.NotContains(t, flags, "node-labels")
+}
+
+func TestNodeTaints(t *testing.T) {
+	kubeletConfig := defaultKubeletSubConfig()
+	nodeConfig := api.NodeConfig{
+		Spec: api.NodeConfigSpec{
+			Kubelet: api.KubeletOptions{
+				Taints: []api.Taint{
+					{Key: "dedicated", Value: "gpu", Effect: api.TaintEffectNoSchedule},
+					{Key: "example.com/draining", Effect: api.TaintEffectNoExecute},
+				},
+			},
+		},
+	}
+	kubeletConfig.withNodeTaints(&nodeConfig)
+	assert.Equal(t, []v1.Taint{
+		{Key: "dedicated", Value: "gpu", Effect: v1.TaintEffectNoSchedule},
+		{Key: "example.com/draining", Effect: v1.TaintEffectNoExecute},
+	}, kubeletConfig.RegisterWithTaints)
+}
+
+func TestRemoveOverriddenDefaults(t *testing.T) {
+	kubeletConfig := defaultKubeletSubConfig()
+	kubeletConfig.FeatureGates = map[string]bool{"Foo": true}
+	kubeletConfigBytes, err := json.Marshal(kubeletConfig)
+	if !assert.NoError(t, err) {
+		return
+	}
+	kubeletConfigBytes, err = removeOverriddenDefaults(kubeletConfigBytes, [][]string{{"evictionHard"}, {"featureGates", "Foo"}, {"missing", "key"}})
+	if !assert.NoError(t, err) {
+		return
+	}
+	var kubeletConfigMap map[string]interface{}
+	if assert.NoError(t, json.Unmarshal(kubeletConfigBytes, &kubeletConfigMap)) {
+		assert.NotContains(t, kubeletConfigMap, "evictionHard")
+		assert.Equal(t, map[string]interface{}{}, kubeletConfigMap["featureGates"])
+		assert.Equal(t, "KubeletConfiguration", kubeletConfigMap["kind"])
+	}
+}
+
+func TestHugepages(t *testing.T) {
+	// 8 memory blocks of 128Mi online, for 1Gi of memory.
+	files := map[string]string{"/sys/devices/system/memory/block_size_bytes": "8000000"}
+	for i := 0; i < 8; i++ {
+		files[fmt.Sprintf("/sys/devices/system/memory/memory%d/online", i)] = "1"
+	}
+	resources := system.NewResources(system.FakeFileSystem{Files: files})
+
+	kubeletConfig := defaultKubeletSubConfig()
+	kubeletConfig.KubeReserved = map[string]string{"memory": "255Mi"}
+	nodeConfig := api.NodeConfig{
+		Spec: api.NodeConfigSpec{
+			Instance: api.InstanceOptions{
+				Hugepages: map[string]intstr.IntOrString{"2Mi": intstr.FromString("50%")},
+			},
+		},
+	}
+	assert.NoError(t, kubeletConfig.withHugepages(&nodeConfig, resources))
+	vars := reservedExpressionVars(&nodeConfig, resources, util.InstanceInfo{}, 58)
+	assert.Equal(t, int64(512), vars[api.ReservedExpressionMemoryMebibytesVar])
+
+	// the default reservation of 893Mi for 58 pods is limited to a quarter of
+	// the 512Mi left over.
+	assert.Equal(t, int64(128), defaultKubeReserved(&nodeConfig, resources, 58).memory)
+	assert.Equal(t, int64(893), defaultKubeReserved(&api.NodeConfig{}, resources, 58).memory)
+
+	nodeConfig.Spec.Instance.Hugepages["2Mi"] = intstr.FromString("80%")
+	assert.ErrorContains(t, kubeletConfig.withHugepages(&nodeConfig, resources), "leave no allocatable memory")
+}
+
+func TestReservedExpressions(t *testing.T) {
+	vars := map[string]interface{}{
+		api.ReservedExpressionVCPUMillicoresVar:  8000,
+		api.ReservedExpressionMemoryMebibytesVar: int64(32768),
+		api.ReservedExpressionMaxPodsVar:         int32(58),
+		api.ReservedExpressionInstanceTypeVar:    "m5.2xlarge",
+	}
+	defaults := reservedAmounts{cpu: 90, memory: 893, ephemeralStorage: 1024}
+	tests := []struct {
+		name        string
+		expressions api.ReservedResourceExpressions
+		expected    map[string]string
+	}{
+		{
+			name:        "defaults",
+			expressions: api.ReservedResourceExpressions{},
+			expected:    map[string]string{"cpu": "90m", "memory": "893Mi", "ephemeral-storage": "1Gi"},
+		},
+		{
+			name: "custom expressions",
+			expressions: api.ReservedResourceExpressions{
+				CPU:              "default_reserved + vcpu_millicores / 100",
+				Memory:           "instance_type.startsWith('m5.') ? memory_mebibytes / 32 + max_pods : default_reserved",
+				EphemeralStorage: "2048",
+			},
+			expected: map[string]string{"cpu": "170m", "memory": "1082Mi", "ephemeral-storage": "2Gi"},
+		},
+		{
+			name: "invalid expressions fall back to defaults",
+			expressions: api.ReservedResourceExpressions{
+				CPU:    "true",
+				Memory: "-1",
+			},
+			expected: map[string]string{"cpu": "90m", "memory": "893Mi", "ephemeral-storage": "1Gi"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, evaluateReservedExpressions(test.expressions, vars, defaults).toResourceList())
+		})
+	}
+
+	systemReserved := evaluateReservedExpressions(api.ReservedResourceExpressions{Memory: "memory_mebibytes / 64"}, vars, reservedAmounts{})
+	assert.Equal(t, map[string]string{"memory": "512Mi"}, systemReserved.toResourceList())
+	assert.Nil(t, reservedAmounts{}.toResourceList())
+}
+
+func TestUsesInstanceTypeDetails(t *testing.T) {
+	tests := []struct {
+		name     string
+		kubelet  api.KubeletOptions
+		expected bool
+	}{
+		{
+			name:     "defaults",
+			kubelet:  api.KubeletOptions{},
+			expected: false,
+		},
+		{
+			name:     "max pods expression without instance type variables",
+			kubelet:  api.KubeletOptions{MaxPodsExpression: "min(max_pods, vcpus * 10)"},
+			expected: false,
+		},
+		{
+			name:     "max pods expression with instance type variables",
+			kubelet:  api.KubeletOptions{MaxPodsExpression: "hypervisor == 'nitro' ? max_pods : standard_max_pods"},
+			expected: true,
+		},
+		{
+			name:     "reserved expression without instance type variables",
+			kubelet:  api.KubeletOptions{KubeReservedExpressions: api.ReservedResourceExpressions{Memory: "memory_mebibytes / 32"}},
+			expected: false,
+		},
+		{
+			name:     "reserved expression with instance type variables",
+			kubelet:  api.KubeletOptions{SystemReservedExpressions: api.ReservedResourceExpressions{CPU: "gpu_count > 0 ? 500 : 0"}},
+			expected: true,
+		},
+		{
+			name:     "invalid expression",
+			kubelet:  api.KubeletOptions{MaxPodsExpression: "gpu_count +"},
+			expected: false,
+		},
+		{
+			name:     "instance type auto labels",
+			kubelet:  api.KubeletOptions{AutoLabels: []api.AutoLabel{api.AutoLabelInstanceType}},
+			expected: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, usesInstanceTypeDetails(&api.NodeConfig{Spec: api.NodeConfigSpec{Kubelet: test.kubelet}}))
+		})
+	}
+}
+
+func TestWriteKubeletEnvironmentIsSorted(t *testing.T) {
+	util.SetRootDir(t.TempDir())
+	t.Cleanup(func() { util.SetRootDir("/") })
+
+	k := &kubelet{
+		environment: map[string]string{"KUBELET_CONFIG_DROPIN_DIR_ALPHA": "on", "AWS_REGION": "us-west-2"},
+		flags:       map[string]string{"node-ip": "10.0.0.1", "config": "/etc/kubernetes/kubelet/config.json", "hostname-override": "ip-10-0-0-1"},
+	}
+	cfg := &api.NodeConfig{Spec: api.NodeConfigSpec{Kubelet: api.KubeletOptions{Flags: []string{"--v=2"}}}}
+	assert.NoError(t, k.writeKubeletEnvironment(cfg))
+	environment, err := os.ReadFile(util.RootedPath(kubeletEnvironmentFilePath))
+	assert.NoError(t, err)
+	assert.Equal(t, `AWS_REGION=us-west-2
+KUBELET_CONFIG_DROPIN_DIR_ALPHA=on
+NODEADM_KUBELET_ARGS=--config=/etc/kubernetes/kubelet/config.json --hostname-override=ip-10-0-0-1 --node-ip=10.0.0.1 --v=2`, string(environment))
+}
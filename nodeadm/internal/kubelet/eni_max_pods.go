@@ -11,18 +11,13 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
 	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/util"
 	"github.com/google/cel-go/cel"
 	"github.com/google/cel-go/common/types"
 	"go.uber.org/zap"
 )
 
-const (
-	defaultENIsVar = "default_enis"
-	ipsPerENIVar   = "ips_per_eni"
-	maxPodsVar     = "max_pods"
-)
-
 // default value from kubelet
 // https://kubernetes.io/docs/reference/config-api/kubelet-config.v1beta1/#kubelet-config-k8s-io-v1beta1-KubeletConfiguration
 const defaultMaxPods = 110
@@ -30,25 +25,74 @@ const defaultMaxPods = 110
 //go:embed instance-info.jsonl
 var cachedInstanceInfoBytes []byte
 
-func GetInstanceInfo(ctx context.Context, awsRegion string, instanceType string) (util.InstanceInfo, error) {
+// GetInstanceInfo returns the instance info of the instance type from the
+// embedded cache, or from the EC2 API when it is not cached. When details is
+// true, the EC2 API is also called for partial cached instance info, which is
+// returned as it is if the call fails.
+func GetInstanceInfo(ctx context.Context, awsRegion string, instanceType string, details bool) (util.InstanceInfo, error) {
 	// try to read it from the cached file first
-	for s := bufio.NewScanner(bytes.NewReader(cachedInstanceInfoBytes)); s.Scan(); {
+	cachedInstanceInfo, ok := findCachedInstanceInfo(cachedInstanceInfoBytes, instanceType)
+	if ok && (!cachedInstanceInfo.Partial || !details) {
+		return cachedInstanceInfo, nil
+	}
+	if ok {
+		zap.L().Info("Cached instance info is partial, making EC2 API call for details...", zap.String("instanceType", instanceType), zap.String("region", awsRegion))
+	} else {
+		zap.L().Warn("Could not find instance info locally, making EC2 API call...", zap.String("instanceType", instanceType), zap.String("region", awsRegion))
+	}
+	instanceInfo, err := describeInstanceInfo(ctx, awsRegion, instanceType)
+	if err != nil && ok {
+		zap.L().Warn("Failed to get details of instance type, using partial cached instance info", zap.String("instanceType", instanceType), zap.Error(err))
+		return cachedInstanceInfo, nil
+	}
+	return instanceInfo, err
+}
+
+// newEC2Client creates the client that describes the instance types which are
+// not in the cache. Tests replace it to avoid calls to the EC2 API.
+var newEC2Client = func(ctx context.Context, awsRegion string) (util.EC2API, error) {
+	cfg, err := util.LoadAWSConfig(ctx, config.WithRegion(awsRegion))
+	if err != nil {
+		return nil, err
+	}
+	return &util.EC2Client{Client: ec2.NewFromConfig(cfg)}, nil
+}
+
+func describeInstanceInfo(ctx context.Context, awsRegion string, instanceType string) (util.InstanceInfo, error) {
+	ec2Client, err := newEC2Client(ctx, awsRegion)
+	if err != nil {
+		return util.InstanceInfo{}, err
+	}
+	return util.GetInstanceInfo(ctx, ec2Client, instanceType)
+}
+
+// findCachedInstanceInfo searches the instance info lines of cache for the
+// instance type. The first line is a util.InstanceInfoHeader, unless the cache
+// predates schema versions, in which case the instance info is partial.
+func findCachedInstanceInfo(cache []byte, instanceType string) (util.InstanceInfo, bool) {
+	schemaVersion := 1
+	for line, s := 0, bufio.NewScanner(bytes.NewReader(cache)); s.Scan(); line++ {
+		if line == 0 {
+			var header util.InstanceInfoHeader
+			if err := json.Unmarshal(s.Bytes(), &header); err == nil && header.SchemaVersion > 0 {
+				if header.SchemaVersion > util.InstanceInfoSchemaVersion {
+					zap.L().Warn("Cached instance info has a newer schema version, ignoring unknown fields", zap.Int("schemaVersion", header.SchemaVersion), zap.Int("supportedSchemaVersion", util.InstanceInfoSchemaVersion))
+				}
+				schemaVersion = header.SchemaVersion
+				continue
+			}
+		}
 		var instanceInfo util.InstanceInfo
 		if err := json.Unmarshal(s.Bytes(), &instanceInfo); err != nil {
 			zap.L().Warn("Failed to read instance info line as json, searching in next line...", zap.Error(err))
 			continue
 		}
 		if instanceInfo.InstanceType == instanceType {
-			return instanceInfo, nil
+			instanceInfo.Partial = schemaVersion < 2
+			return instanceInfo, true
 		}
 	}
-	zap.L().Warn("Could not find instance info locally, making EC2 API call...", zap.String("instanceType", instanceType), zap.String("region", awsRegion))
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(awsRegion))
-	if err != nil {
-		return util.InstanceInfo{}, err
-	}
-	ec2Client := &util.EC2Client{Client: ec2.NewFromConfig(cfg)}
-	return util.GetInstanceInfo(ctx, ec2Client, instanceType)
+	return util.InstanceInfo{}, false
 }
 
 // CalcMaxPods calcaultes a max pods value based on the provided instanceInfo and customExpression.
@@ -59,15 +103,22 @@ func GetInstanceInfo(ctx context.Context, awsRegion string, instanceType string)
 //
 // TODO: isolate this into a public-facing package for external use by other projects
 func CalcMaxPods(instanceInfo util.InstanceInfo, customExpression string) int32 {
-	standardMaxPods := calculateStandardMaxPods(instanceInfo)
+	return CalcMaxPodsWithStrategy(instanceInfo, api.MaxPodsStrategy{}, 0, customExpression)
+}
+
+// CalcMaxPodsWithStrategy calculates a max pods value like CalcMaxPods, using the
+// max pods calculation of the VPC CNI selected by strategy instead of the AL2
+// default. The vcpus are only used for a CapByVCPU strategy and the expression.
+func CalcMaxPodsWithStrategy(instanceInfo util.InstanceInfo, strategy api.MaxPodsStrategy, vcpus int, customExpression string) int32 {
+	strategyMaxPods := calculateStrategyMaxPods(instanceInfo, strategy, vcpus)
 	if len(customExpression) == 0 {
-		return standardMaxPods
+		return strategyMaxPods
 	}
 	zap.L().Info("Applying custom max pods expression", zap.String("expression", customExpression))
-	customMaxPods, err := evaluateCustomMaxPodsExpression(customExpression, instanceInfo, standardMaxPods)
+	customMaxPods, err := evaluateCustomMaxPodsExpression(customExpression, instanceInfo, vcpus, strategyMaxPods)
 	if err != nil {
 		zap.L().Warn("Failed to evaluate custom expression, using standard max pods value", zap.Error(err))
-		return standardMaxPods
+		return strategyMaxPods
 	}
 	return customMaxPods
 }
@@ -76,39 +127,118 @@ func calculateStandardMaxPods(instanceInfo util.InstanceInfo) int32 {
 	return instanceInfo.DefaultMaxENIs*(instanceInfo.Ipv4AddressesPerInterface-1) + 2
 }
 
-func evaluateCustomMaxPodsExpression(expression string, instanceInfo util.InstanceInfo, standardMaxPods int32) (int32, error) {
-	env, err := cel.NewEnv(
-		cel.Variable(defaultENIsVar, cel.IntType),
-		cel.Variable(ipsPerENIVar, cel.IntType),
-		cel.Variable(maxPodsVar, cel.IntType),
-	)
+const (
+	// prefix delegation assigns a /28 prefix in place of each secondary IPv4 address
+	ipsPerPrefix = 16
+
+	// ceilings of the VPC CNI max pods calculator, by the number of vCPUs
+	maxPodsCeilingVCPUs        = 30
+	maxPodsCeilingForLowVCPUs  = 110
+	maxPodsCeilingForHighVCPUs = 250
+)
+
+// calculateStrategyMaxPods calculates the max pods value in the same way as
+// the max pods calculator of the VPC CNI:
+// https://github.com/aws/amazon-vpc-cni-k8s/blob/master/misc/max-pods-calculator.sh
+func calculateStrategyMaxPods(instanceInfo util.InstanceInfo, strategy api.MaxPodsStrategy, vcpus int) int32 {
+	enis := instanceInfo.DefaultMaxENIs
+	if strategy.CustomNetworking && enis > 1 {
+		// the primary ENI is not used for pods
+		enis--
+	}
+	ipsPerENI := instanceInfo.Ipv4AddressesPerInterface - 1
+	if strategy.PrefixDelegation {
+		if instanceInfo.Partial {
+			// the Xen hypervisor does not support prefixes, so without knowing
+			// the hypervisor, the pods could not all be given an IP address.
+			zap.L().Warn("Hypervisor of instance type is not known, using secondary IPs to calculate max pods", zap.String("instanceType", instanceInfo.InstanceType))
+		} else if instanceInfo.Hypervisor == util.HypervisorXen {
+			zap.L().Warn("Prefix delegation is not supported on the Xen hypervisor, using secondary IPs to calculate max pods", zap.String("instanceType", instanceInfo.InstanceType))
+		} else {
+			ipsPerENI *= ipsPerPrefix
+		}
+	}
+	maxPods := enis*ipsPerENI + 2
+	if strategy.CapByVCPU {
+		ceiling := int32(maxPodsCeilingForLowVCPUs)
+		if vcpus > maxPodsCeilingVCPUs {
+			ceiling = maxPodsCeilingForHighVCPUs
+		}
+		maxPods = min(maxPods, ceiling)
+	}
+	return maxPods
+}
+
+func evaluateCustomMaxPodsExpression(expression string, instanceInfo util.InstanceInfo, vcpus int, strategyMaxPods int32) (int32, error) {
+	env, err := api.NewMaxPodsExpressionEnv()
 	if err != nil {
 		return -1, fmt.Errorf("failed to create environment for custom max pods expression: %w", err)
 	}
+	vars := instanceTypeExpressionVars(instanceInfo)
+	vars[api.MaxPodsExpressionDefaultENIsVar] = instanceInfo.DefaultMaxENIs
+	vars[api.MaxPodsExpressionIPsPerENIVar] = instanceInfo.Ipv4AddressesPerInterface
+	vars[api.MaxPodsExpressionMaxPodsVar] = strategyMaxPods
+	vars[api.MaxPodsExpressionStandardMaxPodsVar] = calculateStandardMaxPods(instanceInfo)
+	vars[api.MaxPodsExpressionVCPUsVar] = vcpus
+	int64Value, err := evaluateIntExpression(env, "max pods", expression, vars)
+	if err != nil {
+		return -1, err
+	}
+	if int64Value > math.MaxInt32 || int64Value <= 0 {
+		return -1, fmt.Errorf("max pods value %d from custom expression evaluation is invalid: value must be a positive integer less than %d", int64Value, math.MaxInt32)
+	}
+	// #nosec G115 // value must fit into an int32 based on the above check
+	return int32(int64Value), nil
+}
+
+// instanceTypeExpressionVars returns the instance type variables of every CEL
+// environment in the api package from instanceInfo. The variables are left
+// out when instanceInfo is partial, and trunking_supported when it is not
+// known, so that expressions which use them fail to evaluate instead of using
+// zero values.
+func instanceTypeExpressionVars(instanceInfo util.InstanceInfo) map[string]interface{} {
+	if instanceInfo.Partial {
+		return map[string]interface{}{}
+	}
+	vars := map[string]interface{}{
+		api.InstanceTypeMaxENIsVar:           instanceInfo.MaxENIs,
+		api.InstanceTypeHypervisorVar:        instanceInfo.Hypervisor,
+		api.InstanceTypeIPv6SupportedVar:     instanceInfo.IPv6Supported,
+		api.InstanceTypeGPUCountVar:          instanceInfo.GPUCount(),
+		api.InstanceTypeNeuronDeviceCountVar: instanceInfo.NeuronDeviceCount(),
+		api.InstanceTypeEFASupportedVar:      instanceInfo.EFASupported,
+		api.InstanceTypeMaxEFAInterfacesVar:  instanceInfo.MaxEFAInterfaces,
+		api.InstanceTypeNetworkCardsVar:      len(instanceInfo.NetworkCards),
+	}
+	if instanceInfo.TrunkingSupported != nil {
+		vars[api.InstanceTypeTrunkingSupportedVar] = *instanceInfo.TrunkingSupported
+	}
+	return vars
+}
+
+// evaluateIntExpression compiles the CEL expression in env and evaluates it
+// with vars to an integer. The name describes the expression in errors.
+func evaluateIntExpression(env *cel.Env, name string, expression string, vars map[string]interface{}) (int64, error) {
 	ast, issues := env.Compile(expression)
 	if issues != nil {
 		if issues.Err() != nil {
-			return -1, fmt.Errorf("failed to compile custom max pods expression: %w", issues.Err())
+			return -1, fmt.Errorf("failed to compile custom %s expression: %w", name, issues.Err())
 		}
-		zap.L().Warn("Encountered non-fatal issues compiling max pods expression", zap.String("issues", issues.String()))
+		zap.L().Warn("Encountered non-fatal issues compiling expression", zap.String("name", name), zap.String("issues", issues.String()))
 	}
 	program, err := env.Program(ast)
 	if err != nil {
-		return -1, fmt.Errorf("failed to form program from custom max pods expression: %w", err)
+		return -1, fmt.Errorf("failed to form program from custom %s expression: %w", name, err)
+	}
+	rawVal, _, err := program.Eval(vars)
+	if err != nil {
+		return -1, fmt.Errorf("failed to evaluate custom %s expression: %w", name, err)
 	}
-	rawVal, _, err := program.Eval(map[string]interface{}{
-		defaultENIsVar: instanceInfo.DefaultMaxENIs,
-		ipsPerENIVar:   instanceInfo.Ipv4AddressesPerInterface,
-		maxPodsVar:     standardMaxPods,
-	})
 	if castVal := rawVal.ConvertToType(cel.IntType); types.IsError(castVal) {
-		return -1, fmt.Errorf("could not interpret result \"%v\" from evaluation of custom max pods expression as an integer: %v", rawVal.Value(), castVal.Value())
+		return -1, fmt.Errorf("could not interpret result \"%v\" from evaluation of custom %s expression as an integer: %v", rawVal.Value(), name, castVal.Value())
 	} else if int64Value, castOk := castVal.Value().(int64); !castOk {
-		return -1, fmt.Errorf("could not cast %v from evaluation of custom max pods expression to an integer", int64Value)
-	} else if int64Value > math.MaxInt32 || int64Value <= 0 {
-		return -1, fmt.Errorf("max pods value %d from custom expression evaluation is invalid: value must be a positive integer less than %d", int64Value, math.MaxInt32)
+		return -1, fmt.Errorf("could not cast %v from evaluation of custom %s expression to an integer", castVal.Value(), name)
 	} else {
-		// #nosec G115 // value must fit into an int32 based on the above check
-		return int32(int64Value), nil
+		return int64Value, nil
 	}
 }
@@ -6,17 +6,44 @@ import (
 	"context"
 	_ "embed"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"strings"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/ptr"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
 	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/util"
 	"github.com/stretchr/testify/assert"
 )
 
 var initialCacheContents = cachedInstanceInfoBytes
 
+// failingEC2Client fails every call like the EC2 API does in a region that
+// does not exist.
+type failingEC2Client struct{}
+
+func (failingEC2Client) DescribeInstanceTypes(ctx context.Context, params *ec2.DescribeInstanceTypesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstanceTypesOutput, error) {
+	return nil, &smithy.OperationError{ServiceID: "EC2", OperationName: "DescribeInstanceTypes", Err: errors.New("no such host")}
+}
+
+func (failingEC2Client) DescribeRegions(ctx context.Context) ([]string, error) {
+	return nil, &smithy.OperationError{ServiceID: "EC2", OperationName: "DescribeRegions", Err: errors.New("no such host")}
+}
+
+// withFailingEC2 makes the instance types that are not in the cache fail to be
+// described for the rest of the test, instead of calling the EC2 API.
+func withFailingEC2(t *testing.T) {
+	previous := newEC2Client
+	t.Cleanup(func() { newEC2Client = previous })
+	newEC2Client = func(ctx context.Context, awsRegion string) (util.EC2API, error) {
+		return failingEC2Client{}, nil
+	}
+}
+
 func TestCalcMaxPods(t *testing.T) {
 	var tests = []struct {
 		customExpression string
@@ -70,6 +97,162 @@ func TestCalcMaxPods(t *testing.T) {
 	cachedInstanceInfoBytes = initialCacheContents
 }
 
+func TestCalcMaxPodsWithStrategy(t *testing.T) {
+	// a t3.medium with 2 vCPUs
+	t3Medium := util.InstanceInfo{
+		InstanceType:              "t3.medium",
+		DefaultMaxENIs:            3,
+		Ipv4AddressesPerInterface: 6,
+		MaxENIs:                   3,
+		IPv6Supported:             true,
+		Hypervisor:                util.HypervisorNitro,
+	}
+	// a m5.24xlarge with 96 vCPUs
+	m524xlarge := util.InstanceInfo{
+		InstanceType:              "m5.24xlarge",
+		DefaultMaxENIs:            15,
+		Ipv4AddressesPerInterface: 50,
+		Hypervisor:                util.HypervisorNitro,
+	}
+	// a m4.large on the Xen hypervisor
+	m4Large := util.InstanceInfo{
+		InstanceType:              "m4.large",
+		DefaultMaxENIs:            2,
+		Ipv4AddressesPerInterface: 10,
+		Hypervisor:                util.HypervisorXen,
+	}
+	var tests = []struct {
+		name             string
+		instanceInfo     util.InstanceInfo
+		strategy         api.MaxPodsStrategy
+		vcpus            int
+		customExpression string
+		expectedValue    int32
+	}{
+		{
+			name:          "standard",
+			instanceInfo:  t3Medium,
+			vcpus:         2,
+			expectedValue: 17,
+		},
+		{
+			name:          "prefix delegation",
+			instanceInfo:  t3Medium,
+			strategy:      api.MaxPodsStrategy{PrefixDelegation: true},
+			vcpus:         2,
+			expectedValue: 242,
+		},
+		{
+			name:          "prefix delegation capped by vcpu",
+			instanceInfo:  t3Medium,
+			strategy:      api.MaxPodsStrategy{PrefixDelegation: true, CapByVCPU: true},
+			vcpus:         2,
+			expectedValue: 110,
+		},
+		{
+			name:          "prefix delegation capped by vcpu on a large instance",
+			instanceInfo:  m524xlarge,
+			strategy:      api.MaxPodsStrategy{PrefixDelegation: true, CapByVCPU: true},
+			vcpus:         96,
+			expectedValue: 250,
+		},
+		{
+			name:          "custom networking",
+			instanceInfo:  t3Medium,
+			strategy:      api.MaxPodsStrategy{CustomNetworking: true},
+			vcpus:         2,
+			expectedValue: 12,
+		},
+		{
+			name:          "custom networking with prefix delegation",
+			instanceInfo:  t3Medium,
+			strategy:      api.MaxPodsStrategy{CustomNetworking: true, PrefixDelegation: true},
+			vcpus:         2,
+			expectedValue: 162,
+		},
+		{
+			name:          "capped by vcpu without reaching the cap",
+			instanceInfo:  t3Medium,
+			strategy:      api.MaxPodsStrategy{CapByVCPU: true},
+			vcpus:         2,
+			expectedValue: 17,
+		},
+		{
+			name:          "prefix delegation is not supported on xen",
+			instanceInfo:  m4Large,
+			strategy:      api.MaxPodsStrategy{PrefixDelegation: true},
+			vcpus:         2,
+			expectedValue: 20,
+		},
+		{
+			name:             "expression of the strategy result",
+			instanceInfo:     t3Medium,
+			strategy:         api.MaxPodsStrategy{PrefixDelegation: true},
+			vcpus:            2,
+			customExpression: "max_pods - standard_max_pods",
+			expectedValue:    225,
+		},
+		{
+			name:             "expression of instance variables",
+			instanceInfo:     t3Medium,
+			vcpus:            2,
+			customExpression: "ipv6_supported && hypervisor == 'nitro' ? max_enis * vcpus : max_pods",
+			expectedValue:    6,
+		},
+		{
+			name: "expression of accelerators and network cards",
+			instanceInfo: util.InstanceInfo{
+				InstanceType:              "p5.48xlarge",
+				DefaultMaxENIs:            2,
+				Ipv4AddressesPerInterface: 50,
+				GPUs:                      []util.AcceleratorInfo{{Name: "H100", Manufacturer: "NVIDIA", Count: 8}},
+				EFASupported:              true,
+				MaxEFAInterfaces:          32,
+				TrunkingSupported:         ptr.Bool(true),
+				NetworkCards:              make([]util.NetworkCardInfo, 32),
+			},
+			customExpression: "efa_supported && trunking_supported && neuron_device_count == 0 ? gpu_count * network_cards + max_efa_interfaces : max_pods",
+			expectedValue:    288,
+		},
+		{
+			name:             "trunking support is unknown without the limits of the VPC resource controller",
+			instanceInfo:     t3Medium,
+			vcpus:            2,
+			customExpression: "trunking_supported ? 1 : 2",
+			expectedValue:    17,
+		},
+		{
+			name: "instance variables are unknown for partial instance info",
+			instanceInfo: util.InstanceInfo{
+				InstanceType:              "m4.large",
+				DefaultMaxENIs:            2,
+				Ipv4AddressesPerInterface: 10,
+				Partial:                   true,
+			},
+			customExpression: "max_enis",
+			expectedValue:    20,
+		},
+		{
+			name: "prefix delegation uses secondary IPs for partial instance info",
+			instanceInfo: util.InstanceInfo{
+				InstanceType:              "t3.medium",
+				DefaultMaxENIs:            3,
+				Ipv4AddressesPerInterface: 6,
+				Partial:                   true,
+			},
+			strategy:      api.MaxPodsStrategy{PrefixDelegation: true},
+			vcpus:         2,
+			expectedValue: 17,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			val := CalcMaxPodsWithStrategy(test.instanceInfo, test.strategy, test.vcpus, test.customExpression)
+			assert.Equal(t, test.expectedValue, val)
+		})
+	}
+}
+
 func TestEvaluateCustomMaxPodsExpression(t *testing.T) {
 	var tests = []struct {
 		expression          string
@@ -174,7 +357,7 @@ func TestEvaluateCustomMaxPodsExpression(t *testing.T) {
 			InstanceType:              "fake-type1.xlarge",
 			DefaultMaxENIs:            int32(test.defaultENIs),
 			Ipv4AddressesPerInterface: int32(test.ipsPerENI),
-		}, test.standardMaxPods)
+		}, 0, test.standardMaxPods)
 		if test.expectErr {
 			assert.Error(t, err)
 			assert.ErrorContains(t, err, test.expectedErrContents)
@@ -188,6 +371,7 @@ func TestEvaluateCustomMaxPodsExpression(t *testing.T) {
 func TestGetInstanceInfo(t *testing.T) {
 	var tests = []struct {
 		instanceType            string
+		details                 bool
 		cacheContentString      string
 		expectedInfo            util.InstanceInfo
 		expectErr               bool
@@ -204,6 +388,7 @@ func TestGetInstanceInfo(t *testing.T) {
 				InstanceType:              "fake-type1.xlarge",
 				DefaultMaxENIs:            1,
 				Ipv4AddressesPerInterface: 1,
+				Partial:                   true,
 			},
 		},
 		{
@@ -216,6 +401,7 @@ func TestGetInstanceInfo(t *testing.T) {
 				InstanceType:              "fake-type2.xlarge",
 				DefaultMaxENIs:            99,
 				Ipv4AddressesPerInterface: 99,
+				Partial:                   true,
 			},
 		},
 		{
@@ -228,6 +414,7 @@ func TestGetInstanceInfo(t *testing.T) {
 				InstanceType:              "fake-type3.xlarge",
 				DefaultMaxENIs:            3,
 				Ipv4AddressesPerInterface: 10,
+				Partial:                   true,
 			},
 		},
 		{
@@ -265,13 +452,55 @@ func TestGetInstanceInfo(t *testing.T) {
 				InstanceType:              "fake-type3.xlarge",
 				DefaultMaxENIs:            3,
 				Ipv4AddressesPerInterface: 10,
+				Partial:                   true,
+			},
+		},
+		{
+			// partial instance info is used when the details are undiscoverable
+			instanceType:       "fake-type1.xlarge",
+			details:            true,
+			cacheContentString: `{"instanceType":"fake-type1.xlarge","defaultMaxENIs":1,"ipv4AddressesPerInterface":1}`,
+			expectedInfo: util.InstanceInfo{
+				InstanceType:              "fake-type1.xlarge",
+				DefaultMaxENIs:            1,
+				Ipv4AddressesPerInterface: 1,
+				Partial:                   true,
+			},
+		},
+		{
+			// versioned cache with a header
+			instanceType: "fake-type2.xlarge",
+			cacheContentString: `{"schemaVersion":2}
+			{"instanceType":"fake-type1.xlarge","defaultMaxENIs":1,"ipv4AddressesPerInterface":1}
+			{"instanceType":"fake-type2.xlarge","defaultMaxENIs":4,"ipv4AddressesPerInterface":15,"vcpus":4,"memoryMiB":16384,"neuronDevices":[{"name":"Inferentia2","count":1,"memoryMiB":32768}],"efaSupported":true,"trunkingSupported":true,"networkCards":[{"index":0,"maxENIs":4}]}`,
+			expectedInfo: util.InstanceInfo{
+				InstanceType:              "fake-type2.xlarge",
+				DefaultMaxENIs:            4,
+				Ipv4AddressesPerInterface: 15,
+				VCPUs:                     4,
+				MemoryMiB:                 16384,
+				NeuronDevices:             []util.AcceleratorInfo{{Name: "Inferentia2", Count: 1, MemoryMiB: 32768}},
+				EFASupported:              true,
+				TrunkingSupported:         ptr.Bool(true),
+				NetworkCards:              []util.NetworkCardInfo{{Index: 0, MaxENIs: 4}},
+			},
+		},
+		{
+			// cache with a newer schema version ignores unknown fields
+			instanceType: "fake-type1.xlarge",
+			cacheContentString: `{"schemaVersion":99}
+			{"instanceType":"fake-type1.xlarge","defaultMaxENIs":1,"ipv4AddressesPerInterface":1,"futureField":true}`,
+			expectedInfo: util.InstanceInfo{
+				InstanceType:              "fake-type1.xlarge",
+				DefaultMaxENIs:            1,
+				Ipv4AddressesPerInterface: 1,
 			},
 		},
 	}
+	withFailingEC2(t)
 	for _, test := range tests {
 		cachedInstanceInfoBytes = []byte(test.cacheContentString)
-		// use a fake region to force a consistent EC2 API call failure mode regardless of environment
-		info, err := GetInstanceInfo(context.Background(), "fake-region-1", test.instanceType)
+		info, err := GetInstanceInfo(context.Background(), "fake-region-1", test.instanceType, test.details)
 		if test.expectErr {
 			assert.Error(t, err)
 			assert.ErrorContains(t, err, test.expectedErrContents)
@@ -286,11 +515,36 @@ func TestGetInstanceInfo(t *testing.T) {
 	cachedInstanceInfoBytes = initialCacheContents
 }
 
+func TestCachedInstanceInfoDetails(t *testing.T) {
+	var header util.InstanceInfoHeader
+	firstLine, _, _ := bytes.Cut(cachedInstanceInfoBytes, []byte("\n"))
+	if err := json.Unmarshal(firstLine, &header); err != nil || header.SchemaVersion < util.InstanceInfoSchemaVersion {
+		t.Skipf("instance-info.jsonl predates schema version %d, regenerate it with make generate-instance-info", util.InstanceInfoSchemaVersion)
+	}
+	// the details of a common instance type are cached, so EC2 is not called.
+	withFailingEC2(t)
+	info, err := GetInstanceInfo(context.Background(), "us-west-2", "m5.large", true)
+	if assert.NoError(t, err) {
+		assert.False(t, info.Partial)
+		assert.Equal(t, util.HypervisorNitro, info.Hypervisor)
+		assert.Equal(t, int32(2), info.VCPUs)
+		assert.Equal(t, int64(8192), info.MemoryMiB)
+		assert.Equal(t, ptr.Bool(true), info.TrunkingSupported)
+	}
+}
+
 func TestInstanceInfoLoadable(t *testing.T) {
 	if (len(cachedInstanceInfoBytes) == 0) || string(cachedInstanceInfoBytes) != string(initialCacheContents) {
 		assert.FailNow(t, "instance info cache is missing or incorrectly set")
 	}
-	for s := bufio.NewScanner(bytes.NewReader(cachedInstanceInfoBytes)); s.Scan(); {
+	for line, s := 0, bufio.NewScanner(bytes.NewReader(cachedInstanceInfoBytes)); s.Scan(); line++ {
+		if line == 0 {
+			var header util.InstanceInfoHeader
+			if err := json.Unmarshal(s.Bytes(), &header); err == nil && header.SchemaVersion > 0 {
+				assert.LessOrEqual(t, header.SchemaVersion, util.InstanceInfoSchemaVersion)
+				continue
+			}
+		}
 		var instanceInfo util.InstanceInfo
 		if err := json.Unmarshal(s.Bytes(), &instanceInfo); err != nil {
 			assert.NoError(t, err)
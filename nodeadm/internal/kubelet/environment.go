@@ -2,6 +2,8 @@ package kubelet
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
@@ -19,9 +21,11 @@ const (
 func (k *kubelet) writeKubeletEnvironment(cfg *api.NodeConfig) error {
 	// transform kubelet flags into a single string and write them to the
 	// kubelet environment variable
+	// the flags are sorted so that the file only changes with the flags, which
+	// keeps the files rendered by `config diff` comparable.
 	var kubeletFlags []string
-	for flag, value := range k.flags {
-		kubeletFlags = append(kubeletFlags, fmt.Sprintf("--%s=%s", flag, value))
+	for _, flag := range slices.Sorted(maps.Keys(k.flags)) {
+		kubeletFlags = append(kubeletFlags, fmt.Sprintf("--%s=%s", flag, k.flags[flag]))
 	}
 	// append user-provided flags at the end to give them precedence
 	kubeletFlags = append(kubeletFlags, cfg.Spec.Kubelet.Flags...)
@@ -29,8 +33,8 @@ func (k *kubelet) writeKubeletEnvironment(cfg *api.NodeConfig) error {
 	k.environment[kubeletArgsEnvironmentName] = strings.Join(kubeletFlags, " ")
 	// write additional environment variables
 	var kubeletEnvironment []string
-	for eKey, eValue := range k.environment {
-		kubeletEnvironment = append(kubeletEnvironment, fmt.Sprintf(`%s=%s`, eKey, eValue))
+	for _, eKey := range slices.Sorted(maps.Keys(k.environment)) {
+		kubeletEnvironment = append(kubeletEnvironment, fmt.Sprintf(`%s=%s`, eKey, k.environment[eKey]))
 	}
 	return util.WriteFileWithDir(kubeletEnvironmentFilePath, []byte(strings.Join(kubeletEnvironment, "\n")), kubeletConfigPerm)
 }
@@ -3,15 +3,18 @@ package kubelet
 import (
 	"bytes"
 	"fmt"
+	"maps"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
 	"time"
 
 	"golang.org/x/mod/semver"
 
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
 	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/util"
-	"go.uber.org/zap"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
@@ -20,41 +23,26 @@ import (
 	configv1 "k8s.io/kubelet/config/v1"
 )
 
-const (
-	// #nosec G101 //constant path, not credential
-	imageCredentialProviderRoot = "/etc/eks/image-credential-provider"
-	// #nosec G101 //constant path, not credential
-	imageCredentialProviderConfig = "config.json"
-	imageCredentialProviderPerm   = 0644
-	// #nosec G101 //constant path, not credential
-	ecrCredentialProviderBinPathEnvironmentName = "ECR_CREDENTIAL_PROVIDER_BIN_PATH"
-)
-
-var imageCredentialProviderConfigPath = path.Join(imageCredentialProviderRoot, imageCredentialProviderConfig)
+const imageCredentialProviderPerm = 0644
 
-func (k *kubelet) writeImageCredentialProviderConfig(kubeletVersion string) error {
-	// fallback default for image credential provider binary if not overridden
-	ecrCredentialProviderBinPath := path.Join(imageCredentialProviderRoot, "ecr-credential-provider")
-	if binPath, set := os.LookupEnv(ecrCredentialProviderBinPathEnvironmentName); set {
-		zap.L().Info("picked up image credential provider binary path from environment", zap.String("bin-path", binPath))
-		ecrCredentialProviderBinPath = binPath
-	}
+func (k *kubelet) writeImageCredentialProviderConfig(cfg *api.NodeConfig) error {
+	ecrCredentialProviderBinPath := util.ECRCredentialProviderBinPath()
 	if err := ensureCredentialProviderBinaryExists(ecrCredentialProviderBinPath); err != nil {
 		return err
 	}
 
-	config, err := generateImageCredentialProviderConfig(kubeletVersion, ecrCredentialProviderBinPath)
+	config, err := generateImageCredentialProviderConfig(cfg.Status.KubeletVersion, ecrCredentialProviderBinPath, cfg.Spec.Containerd.Registries)
 	if err != nil {
 		return err
 	}
 
 	k.flags["image-credential-provider-bin-dir"] = path.Dir(ecrCredentialProviderBinPath)
-	k.flags["image-credential-provider-config"] = imageCredentialProviderConfigPath
+	k.flags["image-credential-provider-config"] = util.ImageCredentialProviderConfigPath
 
-	return util.WriteFileWithDir(imageCredentialProviderConfigPath, config, imageCredentialProviderPerm)
+	return util.WriteFileWithDir(util.ImageCredentialProviderConfigPath, config, imageCredentialProviderPerm)
 }
 
-func generateImageCredentialProviderConfig(kubeletVersion, ecrCredentialProviderBinPath string) ([]byte, error) {
+func generateImageCredentialProviderConfig(kubeletVersion, ecrCredentialProviderBinPath string, registries map[string]api.RegistryOptions) ([]byte, error) {
 	ecrMatchImages := []string{
 		"*.dkr.ecr.*.amazonaws.com",
 		"*.dkr-ecr.*.on.aws",
@@ -77,6 +65,9 @@ func generateImageCredentialProviderConfig(kubeletVersion, ecrCredentialProvider
 		// when it is known not to work adds unnecessary latency (albeit slight)
 		ecrMatchImages = append(ecrMatchImages, "ecr-public.aws.com")
 	}
+	// containerd pulls the images of a registry mirrored by ECR, such as with
+	// a pull through cache, using the credentials for the image's registry.
+	ecrMatchImages = append(ecrMatchImages, ecrMirroredRegistries(registries, ecrMatchImages)...)
 	cfg := configv1.CredentialProviderConfig{
 		Providers: []configv1.CredentialProvider{
 			{
@@ -99,6 +90,29 @@ func generateImageCredentialProviderConfig(kubeletVersion, ecrCredentialProvider
 	return buf.Bytes(), nil
 }
 
+// ecrMirroredRegistries returns the hosts of the registries with a mirror that
+// matches one of the ECR match images. The default registry is left out, as it
+// has no host to match.
+func ecrMirroredRegistries(registries map[string]api.RegistryOptions, ecrMatchImages []string) []string {
+	var hosts []string
+	for _, host := range slices.Sorted(maps.Keys(registries)) {
+		if host == api.DefaultRegistryHost || slices.ContainsFunc(ecrMatchImages, func(matchImage string) bool { return util.MatchesImageHost(matchImage, host) }) {
+			continue
+		}
+		for _, mirror := range registries[host].Mirrors {
+			mirrorURL, err := url.Parse(mirror.URL)
+			if err != nil {
+				continue
+			}
+			if slices.ContainsFunc(ecrMatchImages, func(matchImage string) bool { return util.MatchesImageHost(matchImage, mirrorURL.Hostname()) }) {
+				hosts = append(hosts, host)
+				break
+			}
+		}
+	}
+	return hosts
+}
+
 func ensureCredentialProviderBinaryExists(binPath string) error {
 	if _, err := os.Stat(binPath); err != nil {
 		return fmt.Errorf("image credential provider binary was not found on path %s. error: %s", binPath, err)
@@ -0,0 +1,68 @@
+package kubelet
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+	"github.com/stretchr/testify/assert"
+	configv1 "k8s.io/kubelet/config/v1"
+)
+
+func TestECRMirroredRegistries(t *testing.T) {
+	ecrMatchImages := []string{
+		"*.dkr.ecr.*.amazonaws.com",
+		"public.ecr.aws",
+	}
+	registries := map[string]api.RegistryOptions{
+		"docker.io": {
+			Mirrors: []api.RegistryMirror{
+				{URL: "https://111122223333.dkr.ecr.us-west-2.amazonaws.com/v2/docker-hub", OverridePath: true},
+			},
+		},
+		"ghcr.io": {
+			Mirrors: []api.RegistryMirror{
+				{URL: "https://mirror.example.com"},
+				{URL: "https://111122223333.dkr.ecr.us-west-2.amazonaws.com:443"},
+			},
+		},
+		"quay.io": {
+			Mirrors: []api.RegistryMirror{
+				{URL: "https://mirror.example.com"},
+			},
+		},
+		"registry.k8s.io": {
+			Mirrors: []api.RegistryMirror{
+				{URL: "https://public.ecr.aws"},
+			},
+		},
+		"111122223333.dkr.ecr.us-east-1.amazonaws.com": {
+			Mirrors: []api.RegistryMirror{
+				{URL: "https://111122223333.dkr.ecr.us-west-2.amazonaws.com"},
+			},
+		},
+		api.DefaultRegistryHost: {
+			Mirrors: []api.RegistryMirror{
+				{URL: "https://111122223333.dkr.ecr.us-west-2.amazonaws.com"},
+			},
+		},
+	}
+	assert.Equal(t, []string{"docker.io", "ghcr.io", "registry.k8s.io"}, ecrMirroredRegistries(registries, ecrMatchImages))
+}
+
+func TestGenerateImageCredentialProviderConfigMatchesECRMirroredRegistries(t *testing.T) {
+	registries := map[string]api.RegistryOptions{
+		"docker.io": {
+			Mirrors: []api.RegistryMirror{
+				{URL: "https://111122223333.dkr.ecr.us-west-2.amazonaws.com/v2/docker-hub", OverridePath: true},
+			},
+		},
+	}
+	config, err := generateImageCredentialProviderConfig("v1.33.0", "/etc/eks/image-credential-provider/ecr-credential-provider", registries)
+	if assert.NoError(t, err) {
+		var providerConfig configv1.CredentialProviderConfig
+		if assert.NoError(t, json.Unmarshal(config, &providerConfig)) && assert.Len(t, providerConfig.Providers, 1) {
+			assert.Contains(t, providerConfig.Providers[0].MatchImages, "docker.io")
+		}
+	}
+}
@@ -1,13 +1,70 @@
 package kubelet
 
 import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/aws/smithy-go/ptr"
+
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/aws/imds"
 	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/system"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/util"
+)
+
+const (
+	neuronDeviceCountLabel      = "node.eks.aws/neuron-device-count"
+	efaInterfaceCountLabel      = "node.eks.aws/efa-interface-count"
+	instanceStoreNVMeCountLabel = "node.eks.aws/instance-store-nvme-count"
+	cpuVendorLabel              = "node.eks.aws/cpu-vendor"
+	cpuFeatureAVX512Label       = "node.eks.aws/cpu-feature.avx512"
+	cpuFeatureAMXLabel          = "node.eks.aws/cpu-feature.amx"
+	outpostIDLabel              = "node.eks.aws/outpost-id"
+	gpuCountLabel               = "node.eks.aws/gpu-count"
+	efaSupportedLabel           = "node.eks.aws/efa-supported"
+	trunkingSupportedLabel      = "node.eks.aws/trunking-supported"
 )
 
 type LabelProvider interface {
 	Get() (string, bool, error)
 }
 
+// autoLabelProviders returns the label providers enabled by the auto labels of
+// the NodeConfig, keyed by label. The instanceInfo is nil when the instance
+// type is not known.
+func autoLabelProviders(autoLabels []api.AutoLabel, fs system.FileSystem, imdsClient imds.IMDSClient, instanceInfo *util.InstanceInfo) map[string]LabelProvider {
+	providers := map[string]LabelProvider{}
+	for _, autoLabel := range autoLabels {
+		switch autoLabel {
+		case api.AutoLabelNeuron:
+			providers[neuronDeviceCountLabel] = PCIDeviceCountLabel{fs: fs, vendorId: system.AMAZON_VENDOR_ID, deviceIds: system.NeuronDeviceIDs}
+		case api.AutoLabelEFA:
+			providers[efaInterfaceCountLabel] = PCIDeviceCountLabel{fs: fs, vendorId: system.AMAZON_VENDOR_ID, deviceIds: system.EFADeviceIDs}
+		case api.AutoLabelInstanceStore:
+			providers[instanceStoreNVMeCountLabel] = InstanceStoreNVMeCountLabel{fs: fs}
+		case api.AutoLabelCPU:
+			providers[cpuVendorLabel] = CPUVendorLabel{fs: fs}
+			providers[cpuFeatureAVX512Label] = CPUFeatureLabel{fs: fs, feature: "avx512f"}
+			providers[cpuFeatureAMXLabel] = CPUFeatureLabel{fs: fs, feature: "amx_tile"}
+		case api.AutoLabelOutpost:
+			providers[outpostIDLabel] = OutpostIDLabel{imdsClient: imdsClient}
+		case api.AutoLabelInstanceType:
+			providers[gpuCountLabel] = InstanceInfoLabel{instanceInfo: instanceInfo, value: func(info util.InstanceInfo) (string, bool) {
+				return strconv.Itoa(int(info.GPUCount())), info.GPUCount() > 0
+			}}
+			providers[efaSupportedLabel] = InstanceInfoLabel{instanceInfo: instanceInfo, value: func(info util.InstanceInfo) (string, bool) {
+				return "true", info.EFASupported
+			}}
+			providers[trunkingSupportedLabel] = InstanceInfoLabel{instanceInfo: instanceInfo, value: func(info util.InstanceInfo) (string, bool) {
+				return "true", ptr.ToBool(info.TrunkingSupported)
+			}}
+		}
+	}
+	return providers
+}
+
 type NvidiaGPULabel struct {
 	fs system.FileSystem
 }
@@ -22,3 +79,120 @@ func (n NvidiaGPULabel) Get() (string, bool, error) {
 	}
 	return "true", true, nil
 }
+
+// PCIDeviceCountLabel is the number of attached pcie devices of a kind, such
+// as Neuron devices or EFA interfaces, when there are any.
+type PCIDeviceCountLabel struct {
+	fs        system.FileSystem
+	vendorId  string
+	deviceIds []string
+}
+
+func (p PCIDeviceCountLabel) Get() (string, bool, error) {
+	count, err := system.CountPCIDevices(p.fs, p.vendorId, p.deviceIds)
+	if err != nil {
+		return "", false, err
+	}
+	if count == 0 {
+		return "", false, nil
+	}
+	return strconv.Itoa(count), true, nil
+}
+
+// InstanceStoreNVMeCountLabel is the number of NVMe instance store volumes,
+// when there are any.
+type InstanceStoreNVMeCountLabel struct {
+	fs system.FileSystem
+}
+
+func (i InstanceStoreNVMeCountLabel) Get() (string, bool, error) {
+	count, err := system.CountInstanceStoreNVMeDevices(i.fs)
+	if err != nil {
+		return "", false, err
+	}
+	if count == 0 {
+		return "", false, nil
+	}
+	return strconv.Itoa(count), true, nil
+}
+
+// the label values of well-known CPU vendors. Graviton processors report the
+// implementer of their Neoverse cores, Arm Limited.
+var cpuVendors = map[string]string{
+	"GenuineIntel": "intel",
+	"AuthenticAMD": "amd",
+	"0x41":         "arm",
+}
+
+// CPUVendorLabel is the vendor of the CPU, e.g. `intel`, `amd`, or `arm`.
+type CPUVendorLabel struct {
+	fs system.FileSystem
+}
+
+func (c CPUVendorLabel) Get() (string, bool, error) {
+	info, err := system.GetCPUInfo(c.fs)
+	if err != nil {
+		return "", false, err
+	}
+	vendor, ok := cpuVendors[info.Vendor]
+	if !ok {
+		return "", false, nil
+	}
+	return vendor, true, nil
+}
+
+// CPUFeatureLabel is whether the CPU supports a feature, and is only set when
+// it does.
+type CPUFeatureLabel struct {
+	fs      system.FileSystem
+	feature string
+}
+
+func (c CPUFeatureLabel) Get() (string, bool, error) {
+	info, err := system.GetCPUInfo(c.fs)
+	if err != nil {
+		return "", false, err
+	}
+	if !slices.Contains(info.Features, c.feature) {
+		return "", false, nil
+	}
+	return "true", true, nil
+}
+
+// OutpostIDLabel is the ID of the Outpost that the instance runs on, when it
+// runs on one.
+type OutpostIDLabel struct {
+	imdsClient imds.IMDSClient
+}
+
+func (o OutpostIDLabel) Get() (string, bool, error) {
+	arn, err := o.imdsClient.GetProperty(context.TODO(), imds.OutpostARN)
+	if err != nil {
+		if imds.IsNotFound(err) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	// e.g. arn:aws:outposts:us-west-2:123456789012:outpost/op-0123456789abcdef0
+	_, id, ok := strings.Cut(strings.TrimSpace(arn), ":outpost/")
+	if !ok {
+		return "", false, nil
+	}
+	return id, true, nil
+}
+
+// InstanceInfoLabel is a value from the instance info of the instance type,
+// when the instance type is known and the value applies to it. No value is
+// known from partial instance info.
+type InstanceInfoLabel struct {
+	instanceInfo *util.InstanceInfo
+	value        func(util.InstanceInfo) (string, bool)
+}
+
+func (i InstanceInfoLabel) Get() (string, bool, error) {
+	if i.instanceInfo == nil || i.instanceInfo.Partial {
+		return "", false, nil
+	}
+	value, ok := i.value(*i.instanceInfo)
+	return value, ok, nil
+}
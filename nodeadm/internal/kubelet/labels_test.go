@@ -1,9 +1,17 @@
 package kubelet
 
 import (
+	"context"
+	"net/http"
 	"testing"
 
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/ptr"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/aws/imds"
 	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/system"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/util"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -50,3 +58,195 @@ func TestNvidiaGPULabel(t *testing.T) {
 		})
 	}
 }
+
+const (
+	intelCPUInfo = `processor	: 0
+vendor_id	: GenuineIntel
+model name	: Intel(R) Xeon(R) Platinum 8488C
+flags		: fpu vme avx2 avx512f avx512dq amx_bf16 amx_tile amx_int8
+
+processor	: 1
+vendor_id	: GenuineIntel
+flags		: fpu vme avx2 avx512f avx512dq amx_bf16 amx_tile amx_int8
+`
+	gravitonCPUInfo = `processor	: 0
+BogoMIPS	: 243.75
+Features	: fp asimd evtstrm aes pmull sha1 sha2 crc32 atomics sve
+CPU implementer	: 0x41
+CPU part	: 0xd40
+`
+)
+
+func TestAutoLabels(t *testing.T) {
+	tests := []struct {
+		name           string
+		autoLabels     []api.AutoLabel
+		files          map[string]string
+		outpostARN     string
+		instanceInfo   *util.InstanceInfo
+		expectedLabels map[string]string
+	}{
+		{
+			name:       "neuron devices",
+			autoLabels: []api.AutoLabel{api.AutoLabelNeuron},
+			files: map[string]string{
+				"/sys/bus/pci/devices/0000:00:1e.0/vendor": "0x1d0f",
+				"/sys/bus/pci/devices/0000:00:1e.0/device": "0x7264",
+				"/sys/bus/pci/devices/0000:00:1f.0/vendor": "0x1d0f",
+				"/sys/bus/pci/devices/0000:00:1f.0/device": "0x7264",
+				"/sys/bus/pci/devices/0000:00:05.0/vendor": "0x1d0f",
+				"/sys/bus/pci/devices/0000:00:05.0/device": "0xec20",
+			},
+			expectedLabels: map[string]string{neuronDeviceCountLabel: "2"},
+		},
+		{
+			name:       "efa interfaces",
+			autoLabels: []api.AutoLabel{api.AutoLabelEFA, api.AutoLabelNeuron},
+			files: map[string]string{
+				"/sys/bus/pci/devices/0000:00:06.0/vendor": "0x1d0f\n",
+				"/sys/bus/pci/devices/0000:00:06.0/device": "0xefa1\n",
+			},
+			expectedLabels: map[string]string{efaInterfaceCountLabel: "1"},
+		},
+		{
+			name:       "instance store volumes",
+			autoLabels: []api.AutoLabel{api.AutoLabelInstanceStore},
+			files: map[string]string{
+				"/sys/class/nvme/nvme0/model": "Amazon Elastic Block Store              ",
+				"/sys/class/nvme/nvme1/model": "Amazon EC2 NVMe Instance Storage        ",
+				"/sys/class/nvme/nvme2/model": "Amazon EC2 NVMe Instance Storage        ",
+			},
+			expectedLabels: map[string]string{instanceStoreNVMeCountLabel: "2"},
+		},
+		{
+			name:       "intel cpu",
+			autoLabels: []api.AutoLabel{api.AutoLabelCPU},
+			files:      map[string]string{"/proc/cpuinfo": intelCPUInfo},
+			expectedLabels: map[string]string{
+				cpuVendorLabel:        "intel",
+				cpuFeatureAVX512Label: "true",
+				cpuFeatureAMXLabel:    "true",
+			},
+		},
+		{
+			name:           "graviton cpu",
+			autoLabels:     []api.AutoLabel{api.AutoLabelCPU},
+			files:          map[string]string{"/proc/cpuinfo": gravitonCPUInfo},
+			expectedLabels: map[string]string{cpuVendorLabel: "arm"},
+		},
+		{
+			name:           "outpost",
+			autoLabels:     []api.AutoLabel{api.AutoLabelOutpost},
+			outpostARN:     "arn:aws:outposts:us-west-2:123456789012:outpost/op-0123456789abcdef0",
+			expectedLabels: map[string]string{outpostIDLabel: "op-0123456789abcdef0"},
+		},
+		{
+			name:           "not on an outpost",
+			autoLabels:     []api.AutoLabel{api.AutoLabelOutpost},
+			expectedLabels: map[string]string{},
+		},
+		{
+			name:       "instance type",
+			autoLabels: []api.AutoLabel{api.AutoLabelInstanceType},
+			instanceInfo: &util.InstanceInfo{
+				InstanceType:      "p4d.24xlarge",
+				GPUs:              []util.AcceleratorInfo{{Name: "A100", Manufacturer: "NVIDIA", Count: 8}},
+				EFASupported:      true,
+				TrunkingSupported: ptr.Bool(true),
+			},
+			expectedLabels: map[string]string{
+				gpuCountLabel:          "8",
+				efaSupportedLabel:      "true",
+				trunkingSupportedLabel: "true",
+			},
+		},
+		{
+			name:       "instance type without accelerators",
+			autoLabels: []api.AutoLabel{api.AutoLabelInstanceType},
+			instanceInfo: &util.InstanceInfo{
+				InstanceType:      "m5.large",
+				TrunkingSupported: ptr.Bool(true),
+			},
+			expectedLabels: map[string]string{trunkingSupportedLabel: "true"},
+		},
+		{
+			name:       "partial instance info",
+			autoLabels: []api.AutoLabel{api.AutoLabelInstanceType},
+			instanceInfo: &util.InstanceInfo{
+				InstanceType: "m5.large",
+				Partial:      true,
+			},
+			expectedLabels: map[string]string{},
+		},
+		{
+			name:           "unknown instance type",
+			autoLabels:     []api.AutoLabel{api.AutoLabelInstanceType},
+			expectedLabels: map[string]string{},
+		},
+		{
+			name:       "all auto labels",
+			autoLabels: []api.AutoLabel{api.AutoLabelNeuron, api.AutoLabelEFA, api.AutoLabelInstanceStore, api.AutoLabelCPU, api.AutoLabelOutpost, api.AutoLabelInstanceType},
+			files: map[string]string{
+				"/sys/bus/pci/devices/0000:00:1e.0/vendor": "0x1d0f",
+				"/sys/bus/pci/devices/0000:00:1e.0/device": "0x7264",
+				"/sys/bus/pci/devices/0000:00:06.0/vendor": "0x1d0f",
+				"/sys/bus/pci/devices/0000:00:06.0/device": "0xefa1",
+				"/sys/bus/pci/devices/0000:00:07.0/vendor": "0x1d0f",
+				"/sys/bus/pci/devices/0000:00:07.0/device": "0xefa1",
+				"/sys/class/nvme/nvme1/model":              "Amazon EC2 NVMe Instance Storage        ",
+				"/proc/cpuinfo":                            intelCPUInfo,
+			},
+			outpostARN: "arn:aws:outposts:us-west-2:123456789012:outpost/op-0123456789abcdef0",
+			instanceInfo: &util.InstanceInfo{
+				InstanceType:      "trn1.32xlarge",
+				EFASupported:      true,
+				TrunkingSupported: ptr.Bool(true),
+			},
+			expectedLabels: map[string]string{
+				neuronDeviceCountLabel:      "1",
+				efaInterfaceCountLabel:      "2",
+				instanceStoreNVMeCountLabel: "1",
+				cpuVendorLabel:              "intel",
+				cpuFeatureAVX512Label:       "true",
+				cpuFeatureAMXLabel:          "true",
+				outpostIDLabel:              "op-0123456789abcdef0",
+				efaSupportedLabel:           "true",
+				trunkingSupportedLabel:      "true",
+			},
+		},
+		{
+			name:       "not enabled",
+			autoLabels: nil,
+			files: map[string]string{
+				"/proc/cpuinfo": intelCPUInfo,
+			},
+			expectedLabels: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			imdsClient := &imds.FakeIMDSClient{
+				GetPropertyFunc: func(ctx context.Context, prop imds.IMDSProperty) (string, error) {
+					if prop == imds.OutpostARN && tt.outpostARN != "" {
+						return tt.outpostARN, nil
+					}
+					return "", &smithyhttp.ResponseError{
+						Response: &smithyhttp.Response{Response: &http.Response{StatusCode: http.StatusNotFound}},
+						Err:      &smithy.GenericAPIError{Code: "NotFound"},
+					}
+				},
+			}
+			providers := autoLabelProviders(tt.autoLabels, system.FakeFileSystem{Files: tt.files}, imdsClient, tt.instanceInfo)
+			labels := map[string]string{}
+			for key, provider := range providers {
+				value, ok, err := provider.Get()
+				assert.NoError(t, err, key)
+				if ok {
+					labels[key] = value
+				}
+			}
+			assert.Equal(t, tt.expectedLabels, labels)
+		})
+	}
+}
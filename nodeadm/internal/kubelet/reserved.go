@@ -0,0 +1,155 @@
+package kubelet
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/system"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/util"
+)
+
+const (
+	reservedCPU              = "cpu"
+	reservedMemory           = "memory"
+	reservedEphemeralStorage = "ephemeral-storage"
+
+	// default ephemeral storage reserved for kubernetes daemons
+	defaultKubeReservedEphemeralStorageMebibytes = 1024
+
+	// the default memory reserved for kubernetes daemons is limited to this
+	// fraction of the memory left over after huge pages are pre-allocated.
+	defaultKubeReservedHugepagesMemoryDivisor = 4
+)
+
+// reservedAmounts holds the millicores of CPU, and the mebibytes of memory and
+// ephemeral storage to reserve.
+type reservedAmounts struct {
+	cpu              int64
+	memory           int64
+	ephemeralStorage int64
+}
+
+// toResourceList formats the non-zero amounts for kubeReserved or
+// systemReserved of the kubelet config.
+func (r reservedAmounts) toResourceList() map[string]string {
+	resources := map[string]string{}
+	if r.cpu > 0 {
+		resources[reservedCPU] = fmt.Sprintf("%dm", r.cpu)
+	}
+	if r.memory > 0 {
+		resources[reservedMemory] = fmt.Sprintf("%dMi", r.memory)
+	}
+	if r.ephemeralStorage > 0 {
+		// formatted as a quantity to keep the default of 1Gi
+		resources[reservedEphemeralStorage] = resource.NewQuantity(r.ephemeralStorage*1024*1024, resource.BinarySI).String()
+	}
+	if len(resources) == 0 {
+		return nil
+	}
+	return resources
+}
+
+// defaultKubeReserved returns the resources nodeadm reserves for kubernetes
+// daemons, which align with AL2. When huge pages are pre-allocated, the memory
+// reservation is limited to a quarter of the memory left over, so that the
+// reservation does not take the memory the huge pages already use.
+func defaultKubeReserved(cfg *api.NodeConfig, resources system.Resources, maxPods int32) reservedAmounts {
+	memory := int64(getMemoryMebibytesToReserve(maxPods))
+	if hugepagesBytes, err := plannedHugepagesBytes(cfg, resources); err != nil {
+		zap.L().Warn("Failed to get huge pages for default reserved memory", zap.Error(err))
+	} else if hugepagesBytes > 0 {
+		if onlineMemory, err := resources.GetOnlineMemory(); err != nil {
+			zap.L().Warn("Failed to get online memory for default reserved memory", zap.Error(err))
+		} else {
+			leftoverMebibytes := (onlineMemory - hugepagesBytes) / (1024 * 1024)
+			memory = max(min(memory, leftoverMebibytes/defaultKubeReservedHugepagesMemoryDivisor), 0)
+		}
+	}
+	return reservedAmounts{
+		cpu:              int64(getCPUMillicoresToReserve(resources)),
+		memory:           memory,
+		ephemeralStorage: defaultKubeReservedEphemeralStorageMebibytes,
+	}
+}
+
+// plannedHugepagesBytes returns the bytes of memory the huge pages of the
+// NodeConfig pre-allocate.
+func plannedHugepagesBytes(cfg *api.NodeConfig, resources system.Resources) (int64, error) {
+	plan, err := system.PlanHugepages(cfg.Spec.Instance.Hugepages, resources)
+	if err != nil {
+		return 0, err
+	}
+	var hugepagesBytes int64
+	for _, hugepages := range plan {
+		hugepagesBytes += hugepages.Bytes()
+	}
+	return hugepagesBytes, nil
+}
+
+// reservedExpressionVars returns the variables of the CEL environment from
+// api.NewReservedExpressionEnv, without default_reserved.
+func reservedExpressionVars(cfg *api.NodeConfig, resources system.Resources, instanceInfo util.InstanceInfo, maxPods int32) map[string]interface{} {
+	vcpuMillicores, err := resources.GetMilliNumCores()
+	if err != nil {
+		zap.L().Warn("Failed to get vCPU millicores for reserved expressions", zap.Error(err))
+	}
+	onlineMemory, err := resources.GetOnlineMemory()
+	if err != nil {
+		zap.L().Warn("Failed to get online memory for reserved expressions", zap.Error(err))
+	}
+	// pre-allocated huge pages are not available to the kubelet, pods or the
+	// system daemons, so the reservations are based on the memory left over.
+	if hugepagesBytes, err := plannedHugepagesBytes(cfg, resources); err != nil {
+		zap.L().Warn("Failed to get huge pages for reserved expressions", zap.Error(err))
+	} else {
+		onlineMemory -= hugepagesBytes
+	}
+	vars := instanceTypeExpressionVars(instanceInfo)
+	vars[api.ReservedExpressionVCPUMillicoresVar] = vcpuMillicores
+	vars[api.ReservedExpressionMemoryMebibytesVar] = onlineMemory / (1024 * 1024)
+	vars[api.ReservedExpressionMaxPodsVar] = maxPods
+	vars[api.ReservedExpressionInstanceTypeVar] = cfg.Status.Instance.Type
+	return vars
+}
+
+// evaluateReservedExpressions evaluates each expression that is set with vars,
+// and falls back to the amount in defaults when the expression cannot be
+// evaluated to a non-negative integer.
+func evaluateReservedExpressions(expressions api.ReservedResourceExpressions, vars map[string]interface{}, defaults reservedAmounts) reservedAmounts {
+	if expressions == (api.ReservedResourceExpressions{}) {
+		return defaults
+	}
+	env, err := api.NewReservedExpressionEnv()
+	if err != nil {
+		zap.L().Warn("Failed to create environment for reserved expressions, using default reserved resources", zap.Error(err))
+		return defaults
+	}
+	evaluate := func(resourceName string, expression string, defaultValue int64) int64 {
+		if len(expression) == 0 {
+			return defaultValue
+		}
+		exprVars := map[string]interface{}{api.ReservedExpressionDefaultVar: defaultValue}
+		for name, value := range vars {
+			exprVars[name] = value
+		}
+		zap.L().Info("Applying custom reserved expression", zap.String("resource", resourceName), zap.String("expression", expression))
+		value, err := evaluateIntExpression(env, resourceName+" reserved", expression, exprVars)
+		if err == nil && value < 0 {
+			err = fmt.Errorf("%s reserved value %d from custom expression evaluation is invalid: value must not be negative", resourceName, value)
+		}
+		if err != nil {
+			zap.L().Warn("Failed to evaluate custom expression, using default reserved value", zap.String("resource", resourceName), zap.Int64("default", defaultValue), zap.Error(err))
+			return defaultValue
+		}
+		return value
+	}
+	return reservedAmounts{
+		cpu:              evaluate(reservedCPU, expressions.CPU, defaults.cpu),
+		memory:           evaluate(reservedMemory, expressions.Memory, defaults.memory),
+		ephemeralStorage: evaluate(reservedEphemeralStorage, expressions.EphemeralStorage, defaults.ephemeralStorage),
+	}
+}
@@ -0,0 +1,49 @@
+package system
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+)
+
+const cpuInfoPath = "/proc/cpuinfo"
+
+// CPUInfo describes the CPU of the instance, from the first processor in
+// /proc/cpuinfo.
+type CPUInfo struct {
+	// Vendor is the vendor id of x86 processors, e.g. GenuineIntel, or the
+	// implementer of ARM processors, e.g. 0x41.
+	Vendor string
+	// Features are the flags of x86 processors, or the features of ARM
+	// processors.
+	Features []string
+}
+
+// GetCPUInfo reads the vendor and features of the CPU from /proc/cpuinfo.
+func GetCPUInfo(fs FileSystem) (CPUInfo, error) {
+	data, err := fs.ReadFile(cpuInfoPath)
+	if err != nil {
+		return CPUInfo{}, err
+	}
+	var info CPUInfo
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		// processors are separated by an empty line, and are all the same.
+		if len(strings.TrimSpace(line)) == 0 && (info.Vendor != "" || info.Features != nil) {
+			break
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "vendor_id", "CPU implementer":
+			info.Vendor = value
+		case "flags", "Features":
+			info.Features = strings.Fields(value)
+		}
+	}
+	return info, scanner.Err()
+}
@@ -1,12 +1,27 @@
 package system
 
 import (
+	"slices"
 	"strings"
 
 	"go.uber.org/zap"
 )
 
-const NVIDIA_VENDOR_ID = "0x10de"
+const (
+	NVIDIA_VENDOR_ID = "0x10de"
+	AMAZON_VENDOR_ID = "0x1d0f"
+)
+
+var (
+	// NeuronDeviceIDs are the PCI device ids of AWS Neuron devices, from
+	// Inferentia and Trainium instances.
+	NeuronDeviceIDs = []string{"0x7064", "0x7164", "0x7264", "0x7364"}
+	// EFADeviceIDs are the PCI device ids of Elastic Fabric Adapter interfaces.
+	EFADeviceIDs = []string{"0xefa0", "0xefa1", "0xefa2", "0xefa3"}
+)
+
+// the model of the NVMe controller of each instance store volume.
+const instanceStoreNVMeModel = "Amazon EC2 NVMe Instance Storage"
 
 // IsPCIVendorAttached returns whether any pcie devices with a given vendor id
 // are attached to the instance.
@@ -28,3 +43,57 @@ func IsPCIVendorAttached(fs FileSystem, vendorId string) (bool, error) {
 	}
 	return false, nil
 }
+
+// CountPCIDevices returns the number of pcie devices with a given vendor id
+// and one of the given device ids that are attached to the instance.
+func CountPCIDevices(fs FileSystem, vendorId string, deviceIds []string) (int, error) {
+	vendorPaths, err := fs.Glob("/sys/bus/pci/devices/*/vendor")
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, vendorPath := range vendorPaths {
+		// #nosec G304 // read only operation on sysfs path
+		vendorIdBytes, err := fs.ReadFile(vendorPath)
+		if err != nil {
+			zap.L().Warn("failed to read vendor id", zap.Error(err))
+			continue
+		}
+		if strings.TrimSpace(string(vendorIdBytes)) != vendorId {
+			continue
+		}
+		devicePath := strings.TrimSuffix(vendorPath, "vendor") + "device"
+		// #nosec G304 // read only operation on sysfs path
+		deviceIdBytes, err := fs.ReadFile(devicePath)
+		if err != nil {
+			zap.L().Warn("failed to read device id", zap.Error(err))
+			continue
+		}
+		if slices.Contains(deviceIds, strings.TrimSpace(string(deviceIdBytes))) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// CountInstanceStoreNVMeDevices returns the number of NVMe instance store
+// volumes attached to the instance.
+func CountInstanceStoreNVMeDevices(fs FileSystem) (int, error) {
+	modelPaths, err := fs.Glob("/sys/class/nvme/nvme*/model")
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, modelPath := range modelPaths {
+		// #nosec G304 // read only operation on sysfs path
+		model, err := fs.ReadFile(modelPath)
+		if err != nil {
+			zap.L().Warn("failed to read nvme model", zap.Error(err))
+			continue
+		}
+		if strings.TrimSpace(string(model)) == instanceStoreNVMeModel {
+			count++
+		}
+	}
+	return count, nil
+}
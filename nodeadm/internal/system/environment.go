@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"fmt"
 	"os"
-	"os/exec"
 	"sort"
 	"strconv"
 	"strings"
@@ -13,6 +12,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/daemon"
 	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/util"
 )
 
@@ -37,13 +37,17 @@ func NewNodeadmEnvironmentAspect() SystemAspect {
 	return &nodeadmEnvironmentAspect{}
 }
 
-func NewInstanceEnvironmentAspect() SystemAspect {
-	return &instanceEnvironmentAspect{}
+func NewInstanceEnvironmentAspect(daemonManager daemon.DaemonManager) SystemAspect {
+	return &instanceEnvironmentAspect{
+		daemonManager: daemonManager,
+	}
 }
 
 type nodeadmEnvironmentAspect struct{}
 
-type instanceEnvironmentAspect struct{}
+type instanceEnvironmentAspect struct {
+	daemonManager daemon.DaemonManager
+}
 
 func (a *nodeadmEnvironmentAspect) Name() string {
 	return "nodeadm-environment"
@@ -110,8 +114,8 @@ func (a *instanceEnvironmentAspect) configureInstanceEnvironment(envOpts api.Env
 	// Reload systemd configuration if any config files are written
 	if configWritten {
 		zap.L().Info("Reloading systemd configuration")
-		if output, err := exec.Command("systemctl", "daemon-reload").CombinedOutput(); err != nil {
-			return fmt.Errorf("failed to reload systemd configuration: %w, output: %s", err, string(output))
+		if err := a.daemonManager.DaemonReload(); err != nil {
+			return fmt.Errorf("failed to reload systemd configuration: %w", err)
 		}
 	}
 
@@ -0,0 +1,140 @@
+package system
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+)
+
+const hugepagesPath = "/sys/kernel/mm/hugepages"
+
+// Hugepages is a number of huge pages of one size to pre-allocate.
+type Hugepages struct {
+	// SizeBytes is the size of each page.
+	SizeBytes int64
+	// Pages is the number of pages across all NUMA nodes.
+	Pages int64
+}
+
+// Bytes returns the memory taken by the pages.
+func (h Hugepages) Bytes() int64 {
+	return h.SizeBytes * h.Pages
+}
+
+// PlanHugepages returns the huge pages to pre-allocate for the hugepages of
+// the NodeConfig, sorted by size. Percentages are of the online memory.
+func PlanHugepages(hugepages map[string]intstr.IntOrString, resources Resources) ([]Hugepages, error) {
+	if len(hugepages) == 0 {
+		return nil, nil
+	}
+	var onlineMemory int64
+	var plan []Hugepages
+	for size, value := range hugepages {
+		quantity, err := resource.ParseQuantity(size)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hugepage size %s: %w", size, err)
+		}
+		sizeBytes := quantity.Value()
+		var pages int64
+		if value.Type == intstr.String {
+			percent, err := strconv.ParseInt(strings.TrimSuffix(value.StrVal, "%"), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid hugepages %s for size %s: %w", value.StrVal, size, err)
+			}
+			if onlineMemory == 0 {
+				if onlineMemory, err = resources.GetOnlineMemory(); err != nil {
+					return nil, err
+				}
+			}
+			pages = onlineMemory * percent / 100 / sizeBytes
+		} else {
+			pages = int64(value.IntVal)
+		}
+		plan = append(plan, Hugepages{SizeBytes: sizeBytes, Pages: pages})
+	}
+	slices.SortFunc(plan, func(a, b Hugepages) int { return cmp.Compare(a.SizeBytes, b.SizeBytes) })
+	return plan, nil
+}
+
+func NewHugepagesAspect(resources Resources) SystemAspect {
+	return &hugepagesAspect{
+		resources:     resources,
+		hugepagesPath: hugepagesPath,
+		readFile:      os.ReadFile,
+		writeFile: func(path string, data []byte) error {
+			return os.WriteFile(path, data, 0644)
+		},
+	}
+}
+
+type hugepagesAspect struct {
+	resources     Resources
+	hugepagesPath string
+	readFile      func(path string) ([]byte, error)
+	writeFile     func(path string, data []byte) error
+}
+
+func (a *hugepagesAspect) Name() string {
+	return "hugepages"
+}
+
+func (a *hugepagesAspect) Setup(cfg *api.NodeConfig) error {
+	plan, err := PlanHugepages(cfg.Spec.Instance.Hugepages, a.resources)
+	if err != nil || len(plan) == 0 {
+		return err
+	}
+	numaNodes, err := a.resources.GetNUMANodes()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, hugepages := range plan {
+		pagesDir := fmt.Sprintf("hugepages-%dkB", hugepages.SizeBytes/1024)
+		if len(numaNodes) == 0 {
+			errs = append(errs, a.allocate(filepath.Join(a.hugepagesPath, pagesDir, "nr_hugepages"), hugepages.Pages))
+			continue
+		}
+		// the pages are spread evenly, with any remainder on the first nodes.
+		for i, numaNode := range numaNodes {
+			pages := hugepages.Pages / int64(len(numaNodes))
+			if int64(i) < hugepages.Pages%int64(len(numaNodes)) {
+				pages++
+			}
+			errs = append(errs, a.allocate(filepath.Join(numaNode, "hugepages", pagesDir, "nr_hugepages"), pages))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (a *hugepagesAspect) allocate(path string, pages int64) error {
+	if err := a.writeFile(path, []byte(strconv.FormatInt(pages, 10))); err != nil {
+		return fmt.Errorf("failed to allocate huge pages: %w", err)
+	}
+	// the kernel allocates as many of the pages as it can, which may be fewer
+	// when memory is fragmented.
+	data, err := a.readFile(path)
+	if err != nil {
+		return err
+	}
+	allocated, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return err
+	}
+	if allocated < pages {
+		zap.L().Warn("Allocated fewer huge pages than requested", zap.String("path", path), zap.Int64("requested", pages), zap.Int64("allocated", allocated))
+	} else {
+		zap.L().Info("Allocated huge pages", zap.String("path", path), zap.Int64("pages", allocated))
+	}
+	return nil
+}
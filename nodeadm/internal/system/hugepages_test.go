@@ -0,0 +1,102 @@
+package system
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+)
+
+// 8 memory blocks of 128Mi online, for 1Gi of memory.
+var hugepagesTestMemory = map[string]string{
+	"/sys/devices/system/memory/block_size_bytes": "8000000",
+	"/sys/devices/system/memory/memory0/online":   "1",
+	"/sys/devices/system/memory/memory1/online":   "1",
+	"/sys/devices/system/memory/memory2/online":   "1",
+	"/sys/devices/system/memory/memory3/online":   "1",
+	"/sys/devices/system/memory/memory4/online":   "1",
+	"/sys/devices/system/memory/memory5/online":   "1",
+	"/sys/devices/system/memory/memory6/online":   "1",
+	"/sys/devices/system/memory/memory7/online":   "1",
+}
+
+func TestPlanHugepages(t *testing.T) {
+	resources := NewResources(FakeFileSystem{Files: hugepagesTestMemory})
+	plan, err := PlanHugepages(map[string]intstr.IntOrString{
+		"1Gi": intstr.FromInt32(1),
+		"2Mi": intstr.FromString("25%"),
+	}, resources)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []Hugepages{
+			{SizeBytes: 2 << 20, Pages: 128},
+			{SizeBytes: 1 << 30, Pages: 1},
+		}, plan)
+		assert.Equal(t, int64(256<<20), plan[0].Bytes())
+	}
+
+	_, err = PlanHugepages(map[string]intstr.IntOrString{"huge": intstr.FromInt32(1)}, resources)
+	assert.Error(t, err)
+}
+
+func newTestHugepagesAspect(files map[string]string, written map[string]string) *hugepagesAspect {
+	return &hugepagesAspect{
+		resources:     NewResources(FakeFileSystem{Files: files}),
+		hugepagesPath: hugepagesPath,
+		readFile: func(path string) ([]byte, error) {
+			data, ok := written[path]
+			if !ok {
+				return nil, os.ErrNotExist
+			}
+			return []byte(data), nil
+		},
+		writeFile: func(path string, data []byte) error {
+			written[path] = string(data)
+			return nil
+		},
+	}
+}
+
+func TestHugepagesAspect(t *testing.T) {
+	cfg := &api.NodeConfig{Spec: api.NodeConfigSpec{Instance: api.InstanceOptions{
+		Hugepages: map[string]intstr.IntOrString{
+			"2Mi": intstr.FromInt32(5),
+			"1Gi": intstr.FromString("0%"),
+		},
+	}}}
+
+	t.Run("NUMANodes", func(t *testing.T) {
+		files := map[string]string{
+			"/sys/devices/system/node/node0/cpu0": EmptyDirectoryMarker,
+			"/sys/devices/system/node/node1/cpu1": EmptyDirectoryMarker,
+		}
+		for path, content := range hugepagesTestMemory {
+			files[path] = content
+		}
+		written := map[string]string{}
+		assert.NoError(t, newTestHugepagesAspect(files, written).Setup(cfg))
+		assert.Equal(t, map[string]string{
+			"/sys/devices/system/node/node0/hugepages/hugepages-2048kB/nr_hugepages":    "3",
+			"/sys/devices/system/node/node1/hugepages/hugepages-2048kB/nr_hugepages":    "2",
+			"/sys/devices/system/node/node0/hugepages/hugepages-1048576kB/nr_hugepages": "0",
+			"/sys/devices/system/node/node1/hugepages/hugepages-1048576kB/nr_hugepages": "0",
+		}, written)
+	})
+
+	t.Run("NoNUMATopology", func(t *testing.T) {
+		written := map[string]string{}
+		assert.NoError(t, newTestHugepagesAspect(hugepagesTestMemory, written).Setup(cfg))
+		assert.Equal(t, map[string]string{
+			"/sys/kernel/mm/hugepages/hugepages-2048kB/nr_hugepages":    "5",
+			"/sys/kernel/mm/hugepages/hugepages-1048576kB/nr_hugepages": "0",
+		}, written)
+	})
+
+	t.Run("NoHugepages", func(t *testing.T) {
+		written := map[string]string{}
+		assert.NoError(t, newTestHugepagesAspect(hugepagesTestMemory, written).Setup(&api.NodeConfig{}))
+		assert.Empty(t, written)
+	})
+}
@@ -0,0 +1,175 @@
+package system
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/util"
+)
+
+const (
+	kernelModulesLoadPath = "/etc/modules-load.d/nodeadm.conf"
+	kernelCmdlinePath     = "/proc/cmdline"
+	grubDefaultsPath      = "/etc/default/grub"
+)
+
+// ErrRebooting is returned once the instance is being rebooted to apply kernel
+// arguments, after which nothing else should be set up.
+var ErrRebooting = errors.New("rebooting to apply kernel arguments")
+
+// NewKernelModulesDropInAspect writes the kernel modules of the NodeConfig to
+// a drop-in, so that they are loaded on every boot. The drop-in is removed
+// when there are no modules.
+func NewKernelModulesDropInAspect() SystemAspect {
+	return &kernelModulesDropInAspect{
+		modulesLoadPath: kernelModulesLoadPath,
+	}
+}
+
+type kernelModulesDropInAspect struct {
+	modulesLoadPath string
+}
+
+func (a *kernelModulesDropInAspect) Name() string {
+	return "kernel-modules-drop-in"
+}
+
+func (a *kernelModulesDropInAspect) Setup(cfg *api.NodeConfig) error {
+	modules := cfg.Spec.Instance.Kernel.Modules
+	if len(modules) == 0 {
+		return removeDropIn(a.modulesLoadPath)
+	}
+	zap.L().Info("Writing kernel modules to load on boot..", zap.String("path", a.modulesLoadPath))
+	return util.WriteFileWithDir(a.modulesLoadPath, []byte(strings.Join(modules, "\n")+"\n"), 0644)
+}
+
+// NewKernelAspect loads the kernel modules and sets the kernel arguments of
+// the NodeConfig. Before rebooting, the config is saved with saveConfig, so
+// that the reboot is recorded and only happens once. If saveConfig is nil,
+// the instance is never rebooted.
+func NewKernelAspect(saveConfig func(*api.NodeConfig) error) SystemAspect {
+	return &kernelAspect{
+		cmdlinePath:      kernelCmdlinePath,
+		grubDefaultsPath: grubDefaultsPath,
+		saveConfig:       saveConfig,
+		run:              runCommand,
+	}
+}
+
+type kernelAspect struct {
+	cmdlinePath      string
+	grubDefaultsPath string
+	saveConfig       func(*api.NodeConfig) error
+	run              func(name string, args ...string) error
+}
+
+func (a *kernelAspect) Name() string {
+	return "kernel"
+}
+
+func (a *kernelAspect) Setup(cfg *api.NodeConfig) error {
+	if err := a.loadModules(cfg.Spec.Instance.Kernel.Modules); err != nil {
+		return err
+	}
+	return a.setArgs(cfg)
+}
+
+func (a *kernelAspect) loadModules(modules []string) error {
+	var errs []error
+	for _, module := range modules {
+		if err := a.run("modprobe", module); err != nil {
+			errs = append(errs, fmt.Errorf("failed to load kernel module %s: %w", module, err))
+			continue
+		}
+		zap.L().Info("Loaded kernel module", zap.String("module", module))
+	}
+	return errors.Join(errs...)
+}
+
+func (a *kernelAspect) setArgs(cfg *api.NodeConfig) error {
+	args := cfg.Spec.Instance.Kernel.Args
+	if len(args) == 0 {
+		return nil
+	}
+	cmdline, err := os.ReadFile(a.cmdlinePath)
+	if err != nil {
+		return err
+	}
+	bootArgs := strings.Fields(string(cmdline))
+	var missingArgs []string
+	for _, arg := range args {
+		if !slices.Contains(bootArgs, arg) {
+			missingArgs = append(missingArgs, arg)
+		}
+	}
+	if len(missingArgs) == 0 {
+		zap.L().Info("Kernel was booted with the kernel arguments", zap.Strings("args", args))
+		return nil
+	}
+
+	grubArgs, err := a.grubArgs()
+	if err != nil {
+		return err
+	}
+	zap.L().Info("Adding kernel arguments to the boot entries..", zap.Strings("args", missingArgs))
+	if err := a.run("set-kernel-arg", strings.Join(missingArgs, " ")); err != nil {
+		return fmt.Errorf("failed to set kernel arguments: %w", err)
+	}
+
+	// the config cache may not survive the reboot, so arguments that were
+	// already in the boot entries are taken as having been rebooted for.
+	if !slices.ContainsFunc(missingArgs, func(arg string) bool { return !slices.Contains(grubArgs, arg) }) {
+		zap.L().Error("Kernel arguments are in the boot entries but did not take effect, not rebooting", zap.Strings("missingArgs", missingArgs))
+		return nil
+	}
+	if rebootedForArgs := cfg.Status.Kernel.RebootedForArgs; len(rebootedForArgs) > 0 {
+		zap.L().Error("Kernel arguments did not take effect after rebooting, not rebooting again",
+			zap.Strings("missingArgs", missingArgs), zap.Strings("rebootedForArgs", rebootedForArgs))
+		return nil
+	}
+	if a.saveConfig == nil {
+		zap.L().Warn("Kernel arguments take effect after the instance is rebooted, which needs a config cache to only happen once", zap.Strings("args", missingArgs))
+		return nil
+	}
+	cfg.Status.Kernel.RebootedForArgs = missingArgs
+	if err := a.saveConfig(cfg); err != nil {
+		return fmt.Errorf("failed to record reboot for kernel arguments: %w", err)
+	}
+	zap.L().Info("Rebooting to apply kernel arguments..", zap.Strings("args", missingArgs))
+	if err := a.run("systemctl", "reboot"); err != nil {
+		return fmt.Errorf("failed to reboot: %w", err)
+	}
+	return ErrRebooting
+}
+
+// grubArgs returns the kernel arguments in GRUB_CMDLINE_LINUX, which
+// set-kernel-arg keeps in sync with the boot entries.
+func (a *kernelAspect) grubArgs() ([]string, error) {
+	data, err := os.ReadFile(a.grubDefaultsPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(line), "GRUB_CMDLINE_LINUX="); ok {
+			return strings.Fields(strings.Trim(value, `"'`)), nil
+		}
+	}
+	return nil, nil
+}
+
+func runCommand(name string, args ...string) error {
+	// #nosec G204 Subprocess launched with variable
+	cmd := exec.Command(name, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
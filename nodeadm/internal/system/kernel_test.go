@@ -0,0 +1,144 @@
+package system
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/util"
+)
+
+type fakeKernel struct {
+	commands    []string
+	savedConfig *api.NodeConfig
+	failModules []string
+}
+
+func newTestKernelAspect(t *testing.T, cmdline string, kernel *fakeKernel) *kernelAspect {
+	root := t.TempDir()
+	cmdlinePath := filepath.Join(root, "proc", "cmdline")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(cmdlinePath), 0755))
+	assert.NoError(t, os.WriteFile(cmdlinePath, []byte(cmdline+"\n"), 0644))
+	grubDefaultsPath := filepath.Join(root, "etc", "default", "grub")
+	assert.NoError(t, os.MkdirAll(filepath.Dir(grubDefaultsPath), 0755))
+	assert.NoError(t, os.WriteFile(grubDefaultsPath, []byte(`GRUB_TIMEOUT=0
+GRUB_CMDLINE_LINUX="console=ttyS0 nvme_core.io_timeout=4294967295"
+`), 0644))
+	return &kernelAspect{
+		cmdlinePath:      cmdlinePath,
+		grubDefaultsPath: grubDefaultsPath,
+		saveConfig: func(cfg *api.NodeConfig) error {
+			kernel.savedConfig = cfg.DeepCopy()
+			return nil
+		},
+		run: func(name string, args ...string) error {
+			if name == "set-kernel-arg" {
+				grub, _ := os.ReadFile(grubDefaultsPath)
+				grub = []byte(strings.Replace(string(grub), `"
+`, " "+args[0]+`"
+`, 1))
+				_ = os.WriteFile(grubDefaultsPath, grub, 0644)
+			}
+			kernel.commands = append(kernel.commands, strings.Join(append([]string{name}, args...), " "))
+			if name == "modprobe" && len(args) > 0 && slices.Contains(kernel.failModules, args[0]) {
+				return errors.New("exit status 1")
+			}
+			return nil
+		},
+	}
+}
+
+const testCmdline = "BOOT_IMAGE=(hd0,gpt1)/boot/vmlinuz root=UUID=1234 ro console=ttyS0 nvme_core.io_timeout=4294967295"
+
+func TestKernelAspect(t *testing.T) {
+	t.Run("Modules", func(t *testing.T) {
+		kernel := &fakeKernel{}
+		aspect := newTestKernelAspect(t, testCmdline, kernel)
+		cfg := &api.NodeConfig{Spec: api.NodeConfigSpec{Instance: api.InstanceOptions{
+			Kernel: api.KernelOptions{Modules: []string{"ip_vs", "br_netfilter"}},
+		}}}
+		assert.NoError(t, aspect.Setup(cfg))
+		assert.Equal(t, []string{"modprobe ip_vs", "modprobe br_netfilter"}, kernel.commands)
+
+		kernel.failModules = []string{"ip_vs"}
+		assert.ErrorContains(t, aspect.Setup(cfg), "failed to load kernel module ip_vs")
+	})
+
+	t.Run("ArgsPresent", func(t *testing.T) {
+		kernel := &fakeKernel{}
+		aspect := newTestKernelAspect(t, testCmdline+" transparent_hugepage=never", kernel)
+		cfg := &api.NodeConfig{Spec: api.NodeConfigSpec{Instance: api.InstanceOptions{
+			Kernel: api.KernelOptions{Args: []string{"transparent_hugepage=never", "console=ttyS0"}},
+		}}}
+		assert.NoError(t, aspect.Setup(cfg))
+		assert.Empty(t, kernel.commands)
+		assert.Nil(t, kernel.savedConfig)
+	})
+
+	t.Run("RebootOnce", func(t *testing.T) {
+		kernel := &fakeKernel{}
+		aspect := newTestKernelAspect(t, testCmdline, kernel)
+		cfg := &api.NodeConfig{Spec: api.NodeConfigSpec{Instance: api.InstanceOptions{
+			Kernel: api.KernelOptions{Args: []string{"transparent_hugepage=never", "console=ttyS0", "mitigations=off"}},
+		}}}
+		assert.ErrorIs(t, aspect.Setup(cfg), ErrRebooting)
+		assert.Equal(t, []string{"set-kernel-arg transparent_hugepage=never mitigations=off", "systemctl reboot"}, kernel.commands)
+		if assert.NotNil(t, kernel.savedConfig) {
+			assert.Equal(t, []string{"transparent_hugepage=never", "mitigations=off"}, kernel.savedConfig.Status.Kernel.RebootedForArgs)
+		}
+
+		// after the reboot, the arguments are still missing from the command-line.
+		kernel.commands = nil
+		assert.NoError(t, aspect.Setup(kernel.savedConfig))
+		assert.Equal(t, []string{"set-kernel-arg transparent_hugepage=never mitigations=off"}, kernel.commands)
+	})
+
+	t.Run("ArgsInBootEntries", func(t *testing.T) {
+		// the config cache did not survive the reboot.
+		kernel := &fakeKernel{}
+		aspect := newTestKernelAspect(t, testCmdline, kernel)
+		cfg := &api.NodeConfig{Spec: api.NodeConfigSpec{Instance: api.InstanceOptions{
+			Kernel: api.KernelOptions{Args: []string{"transparent_hugepage=never"}},
+		}}}
+		assert.ErrorIs(t, aspect.Setup(cfg), ErrRebooting)
+		kernel.commands = nil
+		assert.NoError(t, aspect.Setup(cfg.DeepCopy()))
+		cfg.Status.Kernel = api.KernelStatus{}
+		assert.NoError(t, aspect.Setup(cfg))
+		assert.Equal(t, []string{"set-kernel-arg transparent_hugepage=never", "set-kernel-arg transparent_hugepage=never"}, kernel.commands)
+	})
+
+	t.Run("NoConfigCache", func(t *testing.T) {
+		kernel := &fakeKernel{}
+		aspect := newTestKernelAspect(t, testCmdline, kernel)
+		aspect.saveConfig = nil
+		cfg := &api.NodeConfig{Spec: api.NodeConfigSpec{Instance: api.InstanceOptions{
+			Kernel: api.KernelOptions{Args: []string{"transparent_hugepage=never"}},
+		}}}
+		assert.NoError(t, aspect.Setup(cfg))
+		assert.Equal(t, []string{"set-kernel-arg transparent_hugepage=never"}, kernel.commands)
+	})
+}
+
+func TestKernelModulesDropInAspect(t *testing.T) {
+	util.SetRootDir(t.TempDir())
+	t.Cleanup(func() { util.SetRootDir("/") })
+	aspect := NewKernelModulesDropInAspect()
+
+	cfg := &api.NodeConfig{Spec: api.NodeConfigSpec{Instance: api.InstanceOptions{
+		Kernel: api.KernelOptions{Modules: []string{"ip_vs", "br_netfilter"}},
+	}}}
+	assert.NoError(t, aspect.Setup(cfg))
+	modulesLoad, err := os.ReadFile(util.RootedPath(kernelModulesLoadPath))
+	assert.NoError(t, err)
+	assert.Equal(t, "ip_vs\nbr_netfilter\n", string(modulesLoad))
+
+	assert.NoError(t, aspect.Setup(&api.NodeConfig{}))
+	assert.NoFileExists(t, util.RootedPath(kernelModulesLoadPath))
+}
@@ -27,11 +27,15 @@ var (
 
 // NewResolveAspect returns an aspect that configures network name resolution on
 // the host.
-func NewResolveAspect() SystemAspect {
-	return &resolveAspect{}
+func NewResolveAspect(daemonManager daemon.DaemonManager) SystemAspect {
+	return &resolveAspect{
+		daemonManager: daemonManager,
+	}
 }
 
-type resolveAspect struct{}
+type resolveAspect struct {
+	daemonManager daemon.DaemonManager
+}
 
 func (a *resolveAspect) Name() string {
 	return "resolve"
@@ -79,9 +83,5 @@ func (a *resolveAspect) generateSystemdResolvedConfig(options api.NetworkOptions
 }
 
 func (a *resolveAspect) reloadSystemdResolved() error {
-	manager, err := daemon.NewDaemonManager()
-	if err != nil {
-		return err
-	}
-	return manager.RestartDaemon("systemd-resolved")
+	return a.daemonManager.RestartDaemon("systemd-resolved")
 }
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -76,6 +77,24 @@ func (r Resources) GetMilliNumCores() (int, error) {
 	return allLogicalCoresCount * 1000, err
 }
 
+// GetNUMANodes returns the sysfs directories of the NUMA nodes, sorted by
+// node id, or none when the node topology is not available.
+func (r Resources) GetNUMANodes() ([]string, error) {
+	nodesDirs, err := r.fs.Glob(filepath.Join(nodeDir, "node*[0-9]"))
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(nodesDirs, func(a, b string) int {
+		return nodeID(a) - nodeID(b)
+	})
+	return nodesDirs, nil
+}
+
+func nodeID(nodeDir string) int {
+	id, _ := strconv.Atoi(strings.TrimPrefix(filepath.Base(nodeDir), "node"))
+	return id
+}
+
 func (r Resources) getCPUCount() (int, error) {
 	cpusPaths, err := r.getCPUsPaths(cpusPath)
 	if err != nil {
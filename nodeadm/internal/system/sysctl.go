@@ -0,0 +1,188 @@
+package system
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/util"
+)
+
+const (
+	// sorts after the drop-ins of the AMI, so that these take precedence.
+	sysctlDropInPath = "/etc/sysctl.d/99-nodeadm.conf"
+	procSysPath      = "/proc/sys"
+)
+
+// kubeletProtectedKernelDefaults are the kernel parameters that the kubelet
+// requires when `protectKernelDefaults` is enabled, in which case it fails to
+// start rather than changing them.
+// see: https://github.com/kubernetes/kubernetes/blob/master/pkg/kubelet/cm/container_manager_linux.go
+var kubeletProtectedKernelDefaults = map[string]string{
+	"vm.overcommit_memory":      "1",
+	"vm.panic_on_oom":           "0",
+	"kernel.panic":              "10",
+	"kernel.panic_on_oops":      "1",
+	"kernel.keys.root_maxkeys":  "1000000",
+	"kernel.keys.root_maxbytes": "25000000",
+}
+
+// NewSysctlDropInAspect writes the sysctls of the NodeConfig to a drop-in, so
+// that they persist across reboots. The drop-in is removed when there are no
+// sysctls, so that removed sysctls are not applied on the next boot.
+func NewSysctlDropInAspect() SystemAspect {
+	return &sysctlDropInAspect{
+		dropInPath: sysctlDropInPath,
+	}
+}
+
+type sysctlDropInAspect struct {
+	dropInPath string
+}
+
+func (a *sysctlDropInAspect) Name() string {
+	return "sysctl-drop-in"
+}
+
+func (a *sysctlDropInAspect) Setup(cfg *api.NodeConfig) error {
+	sysctls := cfg.Spec.Instance.Sysctls
+	if len(sysctls) == 0 {
+		return removeDropIn(a.dropInPath)
+	}
+	var dropIn bytes.Buffer
+	dropIn.WriteString("# Generated by nodeadm from spec.instance.sysctls\n")
+	for _, name := range slices.Sorted(maps.Keys(sysctls)) {
+		fmt.Fprintf(&dropIn, "%s = %s\n", name, sysctls[name])
+	}
+	zap.L().Info("Writing sysctl drop-in..", zap.String("path", a.dropInPath))
+	return util.WriteFileWithDir(a.dropInPath, dropIn.Bytes(), 0644)
+}
+
+// removeDropIn removes a drop-in that nodeadm wrote for settings that are no
+// longer configured.
+func removeDropIn(dropInPath string) error {
+	err := os.Remove(util.RootedPath(dropInPath))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	zap.L().Info("Removed drop-in without settings", zap.String("path", dropInPath))
+	return nil
+}
+
+// NewSysctlAspect applies the sysctls of the NodeConfig to the running kernel,
+// which the drop-in written by the sysctl drop-in aspect only does on boot.
+func NewSysctlAspect() SystemAspect {
+	return &sysctlAspect{
+		procSysPath: procSysPath,
+	}
+}
+
+type sysctlAspect struct {
+	procSysPath string
+}
+
+func (a *sysctlAspect) Name() string {
+	return "sysctl"
+}
+
+func (a *sysctlAspect) Setup(cfg *api.NodeConfig) error {
+	sysctls := cfg.Spec.Instance.Sysctls
+	// each parameter is written separately, so that every one the running
+	// kernel rejects is reported.
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(sysctls)) {
+		if err := a.write(name, sysctls[name]); err != nil {
+			errs = append(errs, fmt.Errorf("kernel rejected sysctl %s=%s: %w", name, sysctls[name], err))
+			continue
+		}
+		zap.L().Info("Applied sysctl", zap.String("name", name), zap.String("value", sysctls[name]))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	if protectKernelDefaults(cfg) {
+		return a.checkProtectedKernelDefaults()
+	}
+	return nil
+}
+
+// checkProtectedKernelDefaults returns an error when the kernel parameters
+// that the kubelet protects do not have the values it requires, since the
+// kubelet would otherwise fail to start.
+func (a *sysctlAspect) checkProtectedKernelDefaults() error {
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(kubeletProtectedKernelDefaults)) {
+		expected := kubeletProtectedKernelDefaults[name]
+		actual, err := a.read(name)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				zap.L().Warn("Kernel parameter protected by kubelet does not exist", zap.String("name", name))
+				continue
+			}
+			return err
+		}
+		if actual != expected {
+			errs = append(errs, fmt.Errorf("sysctl %s is %s, but kubelet with protectKernelDefaults requires %s", name, actual, expected))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (a *sysctlAspect) read(name string) (string, error) {
+	data, err := os.ReadFile(a.path(name))
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(strings.Fields(string(data)), " "), nil
+}
+
+func (a *sysctlAspect) write(name, value string) error {
+	file, err := os.OpenFile(a.path(name), os.O_WRONLY|os.O_TRUNC, 0)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.WriteString(value)
+	return err
+}
+
+// path returns the path of a kernel parameter under /proc/sys, where the dots
+// that separate the parts of its name are slashes, and any slashes in the
+// parts, like in interface names, are dots.
+func (a *sysctlAspect) path(name string) string {
+	if separator := strings.IndexAny(name, "./"); separator >= 0 && name[separator] == '/' {
+		// the name already uses slashes as separators.
+		return filepath.Join(a.procSysPath, name)
+	}
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = strings.ReplaceAll(part, "/", ".")
+	}
+	return filepath.Join(append([]string{a.procSysPath}, parts...)...)
+}
+
+// protectKernelDefaults returns whether the kubelet is configured with
+// `protectKernelDefaults`, which nodeadm enables unless the kubelet config of
+// the NodeConfig disables it.
+func protectKernelDefaults(cfg *api.NodeConfig) bool {
+	raw, ok := cfg.Spec.Kubelet.Config["protectKernelDefaults"]
+	if !ok {
+		return true
+	}
+	var enabled bool
+	if err := json.Unmarshal(raw.Raw, &enabled); err != nil {
+		return true
+	}
+	return enabled
+}
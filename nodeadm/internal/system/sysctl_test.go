@@ -0,0 +1,114 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/api"
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/util"
+)
+
+func newTestSysctlAspect(t *testing.T, params map[string]string) *sysctlAspect {
+	root := t.TempDir()
+	aspect := &sysctlAspect{
+		procSysPath: filepath.Join(root, "proc", "sys"),
+	}
+	for name, value := range params {
+		path := aspect.path(name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, os.WriteFile(path, []byte(value+"\n"), 0644))
+	}
+	return aspect
+}
+
+func TestSysctlAspect(t *testing.T) {
+	t.Run("ApplySysctls", func(t *testing.T) {
+		params := map[string]string{
+			"net.core.somaxconn":               "4096",
+			"net.ipv4.conf.eth0/100.rp_filter": "1",
+		}
+		for name, value := range kubeletProtectedKernelDefaults {
+			params[name] = value
+		}
+		aspect := newTestSysctlAspect(t, params)
+		cfg := &api.NodeConfig{Spec: api.NodeConfigSpec{Instance: api.InstanceOptions{
+			Sysctls: map[string]string{
+				"net.core.somaxconn":               "65535",
+				"net.ipv4.conf.eth0/100.rp_filter": "0",
+			},
+		}}}
+		assert.NoError(t, aspect.Setup(cfg))
+
+		value, err := aspect.read("net.core.somaxconn")
+		assert.NoError(t, err)
+		assert.Equal(t, "65535", value)
+		rpFilter, err := os.ReadFile(filepath.Join(aspect.procSysPath, "net", "ipv4", "conf", "eth0.100", "rp_filter"))
+		assert.NoError(t, err)
+		assert.Equal(t, "0", string(rpFilter))
+	})
+
+	t.Run("RejectedSysctls", func(t *testing.T) {
+		aspect := newTestSysctlAspect(t, kubeletProtectedKernelDefaults)
+		cfg := &api.NodeConfig{Spec: api.NodeConfigSpec{Instance: api.InstanceOptions{
+			Sysctls: map[string]string{
+				"net.netfilter.nf_conntrack_max": "1048576",
+				"vm.unknown":                     "1",
+			},
+		}}}
+		err := aspect.Setup(cfg)
+		assert.ErrorContains(t, err, "net.netfilter.nf_conntrack_max=1048576")
+		assert.ErrorContains(t, err, "vm.unknown=1")
+	})
+
+	t.Run("ProtectedKernelDefaults", func(t *testing.T) {
+		params := map[string]string{}
+		for name, value := range kubeletProtectedKernelDefaults {
+			params[name] = value
+		}
+		params["kernel.panic"] = "0"
+		aspect := newTestSysctlAspect(t, params)
+		err := aspect.Setup(&api.NodeConfig{})
+		assert.ErrorContains(t, err, "sysctl kernel.panic is 0, but kubelet with protectKernelDefaults requires 10")
+
+		// a protected kernel default can be set through the sysctls.
+		cfg := &api.NodeConfig{Spec: api.NodeConfigSpec{Instance: api.InstanceOptions{
+			Sysctls: map[string]string{"kernel.panic": "10"},
+		}}}
+		assert.NoError(t, aspect.Setup(cfg))
+
+		// and is not checked when the kubelet does not protect it.
+		cfg.Spec.Instance.Sysctls["kernel.panic"] = "0"
+		cfg.Spec.Kubelet.Config = api.InlineDocument{
+			"protectKernelDefaults": runtime.RawExtension{Raw: []byte("false")},
+		}
+		assert.NoError(t, aspect.Setup(cfg))
+	})
+}
+
+func TestSysctlDropInAspect(t *testing.T) {
+	util.SetRootDir(t.TempDir())
+	t.Cleanup(func() { util.SetRootDir("/") })
+	aspect := NewSysctlDropInAspect()
+
+	cfg := &api.NodeConfig{Spec: api.NodeConfigSpec{Instance: api.InstanceOptions{
+		Sysctls: map[string]string{
+			"net.core.somaxconn":               "65535",
+			"net.ipv4.conf.eth0/100.rp_filter": "0",
+		},
+	}}}
+	assert.NoError(t, aspect.Setup(cfg))
+	dropIn, err := os.ReadFile(util.RootedPath(sysctlDropInPath))
+	assert.NoError(t, err)
+	assert.Equal(t, "# Generated by nodeadm from spec.instance.sysctls\nnet.core.somaxconn = 65535\nnet.ipv4.conf.eth0/100.rp_filter = 0\n", string(dropIn))
+
+	// the drop-in is removed once the sysctls are, and nothing is written
+	// when there never were any.
+	assert.NoError(t, aspect.Setup(&api.NodeConfig{}))
+	assert.NoFileExists(t, util.RootedPath(sysctlDropInPath))
+	assert.NoError(t, aspect.Setup(&api.NodeConfig{}))
+	assert.NoFileExists(t, util.RootedPath(sysctlDropInPath))
+}
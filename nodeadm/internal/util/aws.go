@@ -0,0 +1,40 @@
+package util
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/config"
+
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/aws/imds"
+)
+
+// LoadAWSConfig loads the configuration for AWS SDK clients used by nodeadm.
+// The region defaults to the region of the instance, and requests are sent
+// through any proxy that is set in the environment at the time of the request.
+func LoadAWSConfig(ctx context.Context, optFns ...func(*config.LoadOptions) error) (aws.Config, error) {
+	opts := []func(*config.LoadOptions) error{
+		config.WithEC2IMDSRegion(func(o *config.UseEC2IMDSRegion) {
+			o.Client = imds.New(true /* treat 404's as retryable to make credential chain more resilient */)
+		}),
+		config.WithHTTPClient(awshttp.NewBuildableClient().WithTransportOptions(func(tr *http.Transport) {
+			tr.Proxy = imds.ProxyFromCurrentEnvironment
+		})),
+	}
+	awsConfig, err := config.LoadDefaultConfig(ctx, append(opts, optFns...)...)
+	if err != nil {
+		return aws.Config{}, err
+	}
+	if awsConfig.RetryMaxAttempts == 0 {
+		// use a very generous retry policy to accomodate delays in network readiness
+		// we only specify the max attempts if it is unset by the user
+		// so it's possible to override with the AWS_MAX_ATTEMPTS environment variable.
+		// NOTE that this is the number of attempts that will be made in a blocking fashion
+		// i.e. an SDK client.ExampleAPICall() will not return until these attempts are exhausted
+		// we'll give up after approximately 10 minutes
+		awsConfig.RetryMaxAttempts = 30
+	}
+	return awsConfig, nil
+}
@@ -12,12 +12,83 @@ import (
 	"github.com/aws/smithy-go/ptr"
 )
 
+// InstanceInfoSchemaVersion is the version of the instance info written by
+// tools/instance-info, in the InstanceInfoHeader on the first line. Instance
+// info without a header is version 1, which only has the instance type, the
+// default ENIs and the IPv4 addresses per interface.
+const InstanceInfoSchemaVersion = 2
+
+// InstanceInfoHeader is the first line of versioned instance info.
+type InstanceInfoHeader struct {
+	SchemaVersion int `json:"schemaVersion"`
+}
+
 type InstanceInfo struct {
 	InstanceType              string `json:"instanceType"`
 	DefaultMaxENIs            int32  `json:"defaultMaxENIs"`
 	Ipv4AddressesPerInterface int32  `json:"ipv4AddressesPerInterface"`
+	// MaxENIs is the maximum number of network interfaces across all network
+	// cards. Zero when unknown.
+	MaxENIs       int32 `json:"maxENIs,omitempty"`
+	IPv6Supported bool  `json:"ipv6Supported,omitempty"`
+	// Hypervisor is either nitro or xen, and empty for bare metal instances.
+	Hypervisor    string            `json:"hypervisor,omitempty"`
+	VCPUs         int32             `json:"vcpus,omitempty"`
+	MemoryMiB     int64             `json:"memoryMiB,omitempty"`
+	GPUs          []AcceleratorInfo `json:"gpus,omitempty"`
+	NeuronDevices []AcceleratorInfo `json:"neuronDevices,omitempty"`
+	EFASupported  bool              `json:"efaSupported,omitempty"`
+	// MaxEFAInterfaces is the maximum number of EFA interfaces, when EFA is
+	// supported.
+	MaxEFAInterfaces int32 `json:"maxEFAInterfaces,omitempty"`
+	// TrunkingSupported is whether the instance type supports the trunk
+	// interfaces of security groups for pods. EC2 does not describe it, so it
+	// is only known from the limits of the VPC resource controller that
+	// tools/instance-info caches, and nil otherwise.
+	TrunkingSupported *bool             `json:"trunkingSupported,omitempty"`
+	NetworkCards      []NetworkCardInfo `json:"networkCards,omitempty"`
+	// Partial is whether only the fields of schema version 1 are known, as the
+	// instance info was cached without a header. The other fields are zero.
+	Partial bool `json:"-"`
+}
+
+// AcceleratorInfo describes the GPUs or Neuron devices of one kind on an
+// instance type.
+type AcceleratorInfo struct {
+	Name         string `json:"name"`
+	Manufacturer string `json:"manufacturer,omitempty"`
+	Count        int32  `json:"count"`
+	MemoryMiB    int32  `json:"memoryMiB,omitempty"`
+}
+
+type NetworkCardInfo struct {
+	Index   int32 `json:"index"`
+	MaxENIs int32 `json:"maxENIs"`
 }
 
+// GPUCount returns the number of GPUs of all kinds.
+func (i InstanceInfo) GPUCount() int32 {
+	return countAccelerators(i.GPUs)
+}
+
+// NeuronDeviceCount returns the number of Neuron devices of all kinds.
+func (i InstanceInfo) NeuronDeviceCount() int32 {
+	return countAccelerators(i.NeuronDevices)
+}
+
+func countAccelerators(accelerators []AcceleratorInfo) int32 {
+	var count int32
+	for _, accelerator := range accelerators {
+		count += accelerator.Count
+	}
+	return count
+}
+
+const (
+	HypervisorNitro = string(types.InstanceTypeHypervisorNitro)
+	HypervisorXen   = string(types.InstanceTypeHypervisorXen)
+)
+
 type EC2API interface {
 	DescribeInstanceTypes(ctx context.Context, params *ec2.DescribeInstanceTypesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstanceTypesOutput, error)
 	DescribeRegions(ctx context.Context) ([]string, error)
@@ -46,11 +117,56 @@ func getInstanceInfoFromDescribeResponse(ec2Info types.InstanceTypeInfo) (Instan
 	if aws.ToInt32(defaultMaxENIs) <= 0 {
 		return InstanceInfo{}, fmt.Errorf("found a non-positive value for the maximum number of interfaces supported on the network card index %d for instance type %s: %d", aws.ToInt32(ec2Info.NetworkInfo.DefaultNetworkCardIndex), instanceType, aws.ToInt32(defaultMaxENIs))
 	}
-	return InstanceInfo{
+	instanceInfo := InstanceInfo{
 		InstanceType:              instanceType,
 		DefaultMaxENIs:            aws.ToInt32(defaultMaxENIs),
 		Ipv4AddressesPerInterface: ptr.ToInt32(ec2Info.NetworkInfo.Ipv4AddressesPerInterface),
-	}, nil
+		MaxENIs:                   ptr.ToInt32(ec2Info.NetworkInfo.MaximumNetworkInterfaces),
+		IPv6Supported:             ptr.ToBool(ec2Info.NetworkInfo.Ipv6Supported),
+		Hypervisor:                string(ec2Info.Hypervisor),
+		EFASupported:              ptr.ToBool(ec2Info.NetworkInfo.EfaSupported),
+	}
+	if ec2Info.VCpuInfo != nil {
+		instanceInfo.VCPUs = ptr.ToInt32(ec2Info.VCpuInfo.DefaultVCpus)
+	}
+	if ec2Info.MemoryInfo != nil {
+		instanceInfo.MemoryMiB = ptr.ToInt64(ec2Info.MemoryInfo.SizeInMiB)
+	}
+	if ec2Info.GpuInfo != nil {
+		for _, gpu := range ec2Info.GpuInfo.Gpus {
+			accelerator := AcceleratorInfo{
+				Name:         ptr.ToString(gpu.Name),
+				Manufacturer: ptr.ToString(gpu.Manufacturer),
+				Count:        ptr.ToInt32(gpu.Count),
+			}
+			if gpu.MemoryInfo != nil {
+				accelerator.MemoryMiB = ptr.ToInt32(gpu.MemoryInfo.SizeInMiB)
+			}
+			instanceInfo.GPUs = append(instanceInfo.GPUs, accelerator)
+		}
+	}
+	if ec2Info.NeuronInfo != nil {
+		for _, device := range ec2Info.NeuronInfo.NeuronDevices {
+			accelerator := AcceleratorInfo{
+				Name:  ptr.ToString(device.Name),
+				Count: ptr.ToInt32(device.Count),
+			}
+			if device.MemoryInfo != nil {
+				accelerator.MemoryMiB = ptr.ToInt32(device.MemoryInfo.SizeInMiB)
+			}
+			instanceInfo.NeuronDevices = append(instanceInfo.NeuronDevices, accelerator)
+		}
+	}
+	if ec2Info.NetworkInfo.EfaInfo != nil {
+		instanceInfo.MaxEFAInterfaces = ptr.ToInt32(ec2Info.NetworkInfo.EfaInfo.MaximumEfaInterfaces)
+	}
+	for _, networkCard := range ec2Info.NetworkInfo.NetworkCards {
+		instanceInfo.NetworkCards = append(instanceInfo.NetworkCards, NetworkCardInfo{
+			Index:   ptr.ToInt32(networkCard.NetworkCardIndex),
+			MaxENIs: ptr.ToInt32(networkCard.MaximumNetworkInterfaces),
+		})
+	}
+	return instanceInfo, nil
 }
 
 func GetInstanceInfo(ctx context.Context, ec2API EC2API, instanceType string) (InstanceInfo, error) {
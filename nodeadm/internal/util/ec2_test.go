@@ -41,6 +41,7 @@ func TestGetInstanceInfo(t *testing.T) {
 				InstanceType:              "t3.medium",
 				DefaultMaxENIs:            3,
 				Ipv4AddressesPerInterface: 6,
+				NetworkCards:              []ec2util.NetworkCardInfo{{Index: 0, MaxENIs: 3}},
 			},
 			mockResponse: ec2.DescribeInstanceTypesOutput{
 				InstanceTypes: []types.InstanceTypeInfo{
@@ -68,6 +69,7 @@ func TestGetInstanceInfo(t *testing.T) {
 				InstanceType:              "t3.medium",
 				DefaultMaxENIs:            2,
 				Ipv4AddressesPerInterface: 6,
+				NetworkCards:              []ec2util.NetworkCardInfo{{Index: 1, MaxENIs: 2}, {Index: 0, MaxENIs: 3}},
 			},
 			mockResponse: ec2.DescribeInstanceTypesOutput{
 				InstanceTypes: []types.InstanceTypeInfo{
@@ -93,6 +95,48 @@ func TestGetInstanceInfo(t *testing.T) {
 			mockError:     nil,
 			expectedError: nil,
 		},
+		{
+			instanceType: "inf2.xlarge",
+			expectedResult: ec2util.InstanceInfo{
+				InstanceType:              "inf2.xlarge",
+				DefaultMaxENIs:            4,
+				Ipv4AddressesPerInterface: 15,
+				Hypervisor:                ec2util.HypervisorNitro,
+				NeuronDevices: []ec2util.AcceleratorInfo{
+					{Name: "Inferentia2", Count: 1, MemoryMiB: 32768},
+				},
+				NetworkCards: []ec2util.NetworkCardInfo{{Index: 0, MaxENIs: 4}},
+			},
+			mockResponse: ec2.DescribeInstanceTypesOutput{
+				InstanceTypes: []types.InstanceTypeInfo{
+					{
+						InstanceType: "inf2.xlarge",
+						Hypervisor:   types.InstanceTypeHypervisorNitro,
+						NeuronInfo: &types.NeuronInfo{
+							NeuronDevices: []types.NeuronDeviceInfo{
+								{
+									Name:       aws.String("Inferentia2"),
+									Count:      aws.Int32(1),
+									MemoryInfo: &types.NeuronDeviceMemoryInfo{SizeInMiB: aws.Int32(32768)},
+								},
+							},
+						},
+						NetworkInfo: &types.NetworkInfo{
+							DefaultNetworkCardIndex: aws.Int32(0),
+							NetworkCards: []types.NetworkCardInfo{
+								{
+									NetworkCardIndex:         aws.Int32(0),
+									MaximumNetworkInterfaces: aws.Int32(4),
+								},
+							},
+							Ipv4AddressesPerInterface: aws.Int32(15),
+						},
+					},
+				},
+			},
+			mockError:     nil,
+			expectedError: nil,
+		},
 		{
 			instanceType: "t3.medium",
 			mockResponse: ec2.DescribeInstanceTypesOutput{
@@ -141,6 +185,65 @@ func TestGetInstanceInfo(t *testing.T) {
 			mockError:     nil,
 			expectedError: fmt.Errorf("found a non-positive value for the maximum number of interfaces supported on the network card index 0 for instance type t3.medium: 0"),
 		},
+		{
+			instanceType: "p4d.24xlarge",
+			expectedResult: ec2util.InstanceInfo{
+				InstanceType:              "p4d.24xlarge",
+				DefaultMaxENIs:            15,
+				Ipv4AddressesPerInterface: 50,
+				MaxENIs:                   60,
+				IPv6Supported:             true,
+				Hypervisor:                ec2util.HypervisorNitro,
+				VCPUs:                     96,
+				MemoryMiB:                 1179648,
+				GPUs: []ec2util.AcceleratorInfo{
+					{Name: "A100", Manufacturer: "NVIDIA", Count: 8, MemoryMiB: 40960},
+				},
+				EFASupported:     true,
+				MaxEFAInterfaces: 4,
+				NetworkCards:     []ec2util.NetworkCardInfo{{Index: 0, MaxENIs: 15}, {Index: 1, MaxENIs: 15}},
+			},
+			mockResponse: ec2.DescribeInstanceTypesOutput{
+				InstanceTypes: []types.InstanceTypeInfo{
+					{
+						InstanceType: "p4d.24xlarge",
+						Hypervisor:   types.InstanceTypeHypervisorNitro,
+						VCpuInfo:     &types.VCpuInfo{DefaultVCpus: aws.Int32(96)},
+						MemoryInfo:   &types.MemoryInfo{SizeInMiB: aws.Int64(1179648)},
+						GpuInfo: &types.GpuInfo{
+							Gpus: []types.GpuDeviceInfo{
+								{
+									Name:         aws.String("A100"),
+									Manufacturer: aws.String("NVIDIA"),
+									Count:        aws.Int32(8),
+									MemoryInfo:   &types.GpuDeviceMemoryInfo{SizeInMiB: aws.Int32(40960)},
+								},
+							},
+						},
+						NetworkInfo: &types.NetworkInfo{
+							EfaSupported:            aws.Bool(true),
+							EfaInfo:                 &types.EfaInfo{MaximumEfaInterfaces: aws.Int32(4)},
+							DefaultNetworkCardIndex: aws.Int32(0),
+							NetworkCards: []types.NetworkCardInfo{
+								{
+									NetworkCardIndex:         aws.Int32(0),
+									MaximumNetworkInterfaces: aws.Int32(15),
+								},
+								{
+									NetworkCardIndex:         aws.Int32(1),
+									MaximumNetworkInterfaces: aws.Int32(15),
+								},
+							},
+							MaximumNetworkInterfaces:  aws.Int32(60),
+							Ipv4AddressesPerInterface: aws.Int32(50),
+							Ipv6Supported:             aws.Bool(true),
+						},
+					},
+				},
+			},
+			mockError:     nil,
+			expectedError: nil,
+		},
 		{
 			instanceType:   "t3.medium",
 			expectedResult: ec2util.InstanceInfo{},
@@ -7,9 +7,29 @@ import (
 	"path"
 )
 
+// rootDir is the directory beneath which files written by nodeadm are placed.
+var rootDir = "/"
+
+// SetRootDir redirects every file written with WriteFileWithDir or
+// AppendFileWithDir beneath the given directory, which allows rendering the
+// files nodeadm would write without modifying the host.
+func SetRootDir(dir string) {
+	rootDir = dir
+}
+
+// RootedPath returns the location that the given path will be written to,
+// relative to the directory set with SetRootDir.
+func RootedPath(filePath string) string {
+	if rootDir == "/" {
+		return filePath
+	}
+	return path.Join(rootDir, filePath)
+}
+
 // Wraps os.WriteFile to automatically create parent directories such that the
 // caller does not need to ensure the existence of the file's directory
 func WriteFileWithDir(filePath string, data []byte, perm fs.FileMode) error {
+	filePath = RootedPath(filePath)
 	// folders should have the executable bit in unix systems
 	if err := os.MkdirAll(path.Dir(filePath), perm|0111); err != nil {
 		return err
@@ -17,6 +37,36 @@ func WriteFileWithDir(filePath string, data []byte, perm fs.FileMode) error {
 	return os.WriteFile(filePath, data, perm)
 }
 
+// AppendFileWithDir appends data to an existing file, which is never created.
+// Beneath a directory set with SetRootDir, the file is first copied from the
+// host, so that the rendered file has the contents the host file would have
+// after the append.
+func AppendFileWithDir(filePath string, data []byte, perm fs.FileMode) error {
+	rootedPath := RootedPath(filePath)
+	if rootedPath != filePath {
+		if _, err := os.Stat(rootedPath); errors.Is(err, os.ErrNotExist) {
+			// #nosec G304 // callers only append to fixed paths
+			hostData, err := os.ReadFile(filePath)
+			if err != nil {
+				return err
+			}
+			if err := WriteFileWithDir(filePath, hostData, perm); err != nil {
+				return err
+			}
+		} else if err != nil {
+			return err
+		}
+	}
+	// #nosec G304 // callers only append to fixed paths
+	f, err := os.OpenFile(rootedPath, os.O_APPEND|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(data)
+	return err
+}
+
 // IsFilePathExists checks whether specific file path exists
 func IsFilePathExists(filePath string) (bool, error) {
 	_, err := os.Stat(filePath)
@@ -0,0 +1,50 @@
+package util_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteFileWithDirRootDir(t *testing.T) {
+	rootDir := t.TempDir()
+	util.SetRootDir(rootDir)
+	t.Cleanup(func() { util.SetRootDir("/") })
+
+	assert.Equal(t, filepath.Join(rootDir, "/etc/eks/file"), util.RootedPath("/etc/eks/file"))
+
+	assert.NoError(t, util.WriteFileWithDir("/etc/eks/file", []byte("a"), 0644))
+	assert.NoError(t, util.AppendFileWithDir("/etc/eks/file", []byte("b"), 0644))
+
+	data, err := os.ReadFile(filepath.Join(rootDir, "etc", "eks", "file"))
+	assert.NoError(t, err)
+	assert.Equal(t, "ab", string(data))
+}
+
+func TestAppendFileWithDirRootDir(t *testing.T) {
+	hostFile := filepath.Join(t.TempDir(), "hosts")
+	assert.NoError(t, os.WriteFile(hostFile, []byte("127.0.0.1\tlocalhost\n"), 0644))
+	rootDir := t.TempDir()
+	util.SetRootDir(rootDir)
+	t.Cleanup(func() { util.SetRootDir("/") })
+
+	assert.NoError(t, util.AppendFileWithDir(hostFile, []byte("10.0.0.1\tapi\n"), 0644))
+	assert.NoError(t, util.AppendFileWithDir(hostFile, []byte("10.0.0.2\tapi\n"), 0644))
+
+	data, err := os.ReadFile(util.RootedPath(hostFile))
+	assert.NoError(t, err)
+	assert.Equal(t, "127.0.0.1\tlocalhost\n10.0.0.1\tapi\n10.0.0.2\tapi\n", string(data))
+	data, err = os.ReadFile(hostFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "127.0.0.1\tlocalhost\n", string(data), "the host file must not be modified")
+
+	assert.ErrorIs(t, util.AppendFileWithDir(filepath.Join(t.TempDir(), "missing"), []byte("a"), 0644), os.ErrNotExist)
+}
+
+func TestRootedPathDefault(t *testing.T) {
+	assert.Equal(t, "/etc/eks/file", util.RootedPath("/etc/eks/file"))
+	assert.Equal(t, "relative/file", util.RootedPath("relative/file"))
+}
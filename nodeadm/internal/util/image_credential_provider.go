@@ -0,0 +1,48 @@
+package util
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// #nosec G101 //constant path, not credential
+	imageCredentialProviderRoot = "/etc/eks/image-credential-provider"
+	// #nosec G101 //constant path, not credential
+	ecrCredentialProviderBinPathEnvironmentName = "ECR_CREDENTIAL_PROVIDER_BIN_PATH"
+)
+
+// ImageCredentialProviderConfigPath is the path of the credential provider
+// config of kubelet, which nodeadm also uses to pull images itself.
+var ImageCredentialProviderConfigPath = path.Join(imageCredentialProviderRoot, "config.json")
+
+// ECRCredentialProviderBinPath returns the path of the ECR credential provider
+// binary, which can be overridden with the ECR_CREDENTIAL_PROVIDER_BIN_PATH
+// environment variable.
+func ECRCredentialProviderBinPath() string {
+	if binPath, set := os.LookupEnv(ecrCredentialProviderBinPathEnvironmentName); set {
+		zap.L().Info("picked up image credential provider binary path from environment", zap.String("bin-path", binPath))
+		return binPath
+	}
+	return path.Join(imageCredentialProviderRoot, "ecr-credential-provider")
+}
+
+// MatchesImageHost reports whether host matches the host of a match image of
+// the credential provider config, where each dot-separated part of the
+// pattern is a glob.
+func MatchesImageHost(matchImage string, host string) bool {
+	patternParts, hostParts := strings.Split(matchImage, "."), strings.Split(host, ".")
+	if len(patternParts) != len(hostParts) {
+		return false
+	}
+	for i := range patternParts {
+		if ok, err := filepath.Match(patternParts[i], hostParts[i]); err != nil || !ok {
+			return false
+		}
+	}
+	return true
+}
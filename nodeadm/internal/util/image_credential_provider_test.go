@@ -0,0 +1,24 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchesImageHost(t *testing.T) {
+	var tests = []struct {
+		matchImage string
+		host       string
+		expected   bool
+	}{
+		{matchImage: "*.dkr.ecr.*.amazonaws.com", host: "111122223333.dkr.ecr.us-west-2.amazonaws.com", expected: true},
+		{matchImage: "*.dkr.ecr.*.amazonaws.com", host: "111122223333.dkr.ecr.us-west-2.amazonaws.com.cn", expected: false},
+		{matchImage: "*.dkr.ecr.*.amazonaws.com", host: "dkr.ecr.us-west-2.amazonaws.com", expected: false},
+		{matchImage: "public.ecr.aws", host: "public.ecr.aws", expected: true},
+		{matchImage: "public.ecr.aws", host: "mirror.example.com", expected: false},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, MatchesImageHost(test.matchImage, test.host), "%s matching %s", test.matchImage, test.host)
+	}
+}
@@ -21,3 +21,15 @@ if nodeadm config check --config-source file://config-bad.yaml; then
   cat config-bad.yaml
   exit 1
 fi
+
+if ! nodeadm config check --config-source file://config-containerd-unknown-key.yaml; then
+  echo "should have succeeded with an unknown containerd key outside of strict mode:"
+  cat config-containerd-unknown-key.yaml
+  exit 1
+fi
+
+if nodeadm config check --strict --config-source file://config-containerd-unknown-key.yaml; then
+  echo "should not have succeeded with an unknown containerd key in strict mode:"
+  cat config-containerd-unknown-key.yaml
+  exit 1
+fi
@@ -0,0 +1,30 @@
+#!/usr/bin/env bash
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+source /helpers.sh
+
+mock::aws
+mock::kubelet 1.35.0
+wait::dbus-ready
+
+nodeadm config diff --base-config-source file://base.yaml --config-source file://target.yaml > diff-sources.txt
+assert::file-contains diff-sources.txt '^+++ b/NodeConfig$'
+assert::file-contains diff-sources.txt '^+++ b/etc/kubernetes/kubelet/config.json$'
+assert::file-contains diff-sources.txt '^+ *"maxPods": 42'
+assert::file-contains diff-sources.txt '^+++ b/etc/eks/kubelet/environment$'
+assert::file-not-contains diff-sources.txt 'containerd/config.toml'
+
+# the cache written by init can be used as the base configuration.
+nodeadm init --skip run --config-source file://base.yaml --config-cache /run/eks/nodeadm/config.json
+nodeadm config diff --config-cache /run/eks/nodeadm/config.json --config-source file://target.yaml > diff-cache.txt
+assert::files-equal diff-sources.txt diff-cache.txt
+
+nodeadm config diff --config-cache /run/eks/nodeadm/config.json --config-source file://base.yaml > diff-none.txt
+if [ -s diff-none.txt ]; then
+  echo "equivalent configurations should not produce a diff:"
+  cat diff-none.txt
+  exit 1
+fi
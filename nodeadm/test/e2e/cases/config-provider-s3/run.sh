@@ -0,0 +1,27 @@
+#!/usr/bin/env bash
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+source /helpers.sh
+
+mock::aws
+wait::dbus-ready
+mock::kubelet 1.31.0
+
+aws s3 mb s3://nodeadm-config
+aws s3 cp cluster.yaml s3://nodeadm-config/cluster.yaml
+aws s3 cp kubelet.yaml s3://nodeadm-config/fragments/10-kubelet.yaml
+
+# an empty prefix should not fail the overall chain
+nodeadm init --skip run --config-source s3://nodeadm-config/cluster.yaml --config-source s3://nodeadm-config/empty/
+
+nodeadm init --skip run --config-source s3://nodeadm-config/cluster.yaml --config-source s3://nodeadm-config/fragments/
+assert::file-contains /etc/eks/kubelet/environment '--v=5'
+
+# a missing object is a fatal error
+if nodeadm init --skip run --config-source s3://nodeadm-config/missing.yaml; then
+  echo "a missing S3 object should fail the chain"
+  exit 1
+fi
@@ -0,0 +1,36 @@
+#!/usr/bin/env bash
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+source /helpers.sh
+
+mock::aws
+wait::dbus-ready
+mock::kubelet 1.31.0
+
+aws ssm put-parameter --name /nodeadm/cluster --type String --value file://cluster.yaml
+aws ssm put-parameter --name /nodeadm/fragments/10-kubelet --type SecureString --value file://kubelet.yaml
+aws secretsmanager create-secret --name nodeadm/cluster --secret-string file://cluster.yaml
+aws secretsmanager create-secret --name nodeadm/fragments/10-kubelet --secret-string file://kubelet.yaml
+
+# an empty path or prefix should not fail the overall chain
+nodeadm init --skip run --config-source ssm:///nodeadm/cluster --config-source ssm:///nodeadm/empty/ --config-source secretsmanager://nodeadm/empty/
+
+nodeadm init --skip run --config-source ssm:///nodeadm/cluster --config-source ssm:///nodeadm/fragments/
+assert::file-contains /etc/eks/kubelet/environment '--v=5'
+
+rm /etc/eks/kubelet/environment
+nodeadm init --skip run --config-source secretsmanager://nodeadm/cluster --config-source secretsmanager://nodeadm/fragments/
+assert::file-contains /etc/eks/kubelet/environment '--v=5'
+
+# a missing parameter or secret is a fatal error
+if nodeadm init --skip run --config-source ssm:///nodeadm/missing; then
+  echo "a missing SSM parameter should fail the chain"
+  exit 1
+fi
+if nodeadm init --skip run --config-source secretsmanager://nodeadm/missing; then
+  echo "a missing secret should fail the chain"
+  exit 1
+fi
@@ -0,0 +1,14 @@
+#!/usr/bin/env bash
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+source /helpers.sh
+
+mock::aws
+mock::kubelet 1.31.0
+wait::dbus-ready
+
+nodeadm init --skip run --config-source file://config.yaml
+assert::file-contains /etc/containerd/config.toml '^sandbox_image = "registry.example.com/eks/pause:3.10"$'
@@ -0,0 +1,22 @@
+#!/usr/bin/env bash
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+source /helpers.sh
+
+mock::aws
+mock::kubelet 1.27.0
+wait::dbus-ready
+
+touch /usr/bin/nvidia-container-runtime
+
+nodeadm init --skip run --config-source file://config.yaml
+assert::file-contains /etc/containerd/config.toml '^default_runtime_name = "runc-debug"$'
+assert::file-contains /etc/containerd/config.toml '^\[plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runc\]$'
+assert::file-contains /etc/containerd/config.toml '^\[plugins."io.containerd.grpc.v1.cri".containerd.runtimes.nvidia\]$'
+assert::file-contains /etc/containerd/config.toml '^runtime_type = "io.containerd.runsc.v1"$'
+assert::file-contains /etc/containerd/config.toml "^TypeUrl = 'io.containerd.runsc.v1.options'$"
+assert::file-contains /etc/containerd/config.toml '^runtime_path = "/opt/kata/bin/containerd-shim-kata-v2"$'
+assert::file-contains /etc/containerd/config.toml "^BinaryName = '/usr/local/bin/runc'$"
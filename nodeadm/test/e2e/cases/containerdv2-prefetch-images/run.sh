@@ -0,0 +1,19 @@
+#!/usr/bin/env bash
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+source /helpers.sh
+
+mock::aws
+mock::kubelet 1.32.0
+wait::dbus-ready
+
+nodeadm init --skip run --config-source file://config.yaml
+assert::file-contains /etc/containerd/config.toml '^aws-load-balancer-controller = "public.ecr.aws/eks/aws-load-balancer-controller:v2.7.1"$'
+assert::file-contains /etc/containerd/config.toml '^nginx = "docker.io/library/nginx:latest"$'
+if grep -q 'pause:3.10' /etc/containerd/config.toml; then
+  echo "image that is not pinned was added to the pinned images"
+  exit 1
+fi
@@ -4,12 +4,32 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"net/http"
+	"strconv"
 
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/smithy-go/ptr"
 
 	"github.com/awslabs/amazon-eks-ami/nodeadm/internal/util"
 )
 
+// vpcLimitsURL is the source of the limits of each instance type that the VPC
+// resource controller uses, which is the only source of the instance types
+// that support trunk interfaces for security groups for pods. It is pinned to
+// a release, so that the generated instance info only changes when the release
+// is bumped, and instance types added after the release are left unknown.
+const vpcLimitsURL = "https://raw.githubusercontent.com/aws/amazon-vpc-resource-controller-k8s/" + vpcResourceControllerRelease + "/pkg/aws/vpc/limits.go"
+
+const vpcResourceControllerRelease = "v1.5.0"
+
+// vpcLimitsVar is the variable of the VPC limits source with the limits of each
+// instance type.
+const vpcLimitsVar = "Limits"
+
 func main() {
 	ctx := context.Background()
 	cfg, err := config.LoadDefaultConfig(ctx)
@@ -20,7 +40,19 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
+	trunkingCompatibility, err := getTrunkingCompatibility(ctx)
+	if err != nil {
+		panic(err)
+	}
+	headerBytes, err := json.Marshal(util.InstanceInfoHeader{SchemaVersion: util.InstanceInfoSchemaVersion})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(headerBytes))
 	for _, instanceTypeInfo := range instanceTypeInfos {
+		if compatible, ok := trunkingCompatibility[instanceTypeInfo.InstanceType]; ok {
+			instanceTypeInfo.TrunkingSupported = ptr.Bool(compatible)
+		}
 		infoBytes, err := json.Marshal(instanceTypeInfo)
 		if err != nil {
 			panic(err)
@@ -28,3 +60,103 @@ func main() {
 		fmt.Println(string(infoBytes))
 	}
 }
+
+func getTrunkingCompatibility(ctx context.Context) (map[string]bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, vpcLimitsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get %s: %s", vpcLimitsURL, resp.Status)
+	}
+	src, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return parseTrunkingCompatibility(src)
+}
+
+// parseTrunkingCompatibility returns the IsTrunkingCompatible field of each
+// instance type in the Go source of the VPC limits, where the Limits variable
+// is a map literal of the instance type to its limits. It fails when the map
+// is missing or has no IsTrunkingCompatible fields, as the source has changed.
+func parseTrunkingCompatibility(src []byte) (map[string]bool, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "limits.go", src, 0)
+	if err != nil {
+		return nil, err
+	}
+	limits := findVarValue(file, vpcLimitsVar)
+	if limits == nil {
+		return nil, fmt.Errorf("map literal of variable %s not found in %s", vpcLimitsVar, vpcLimitsURL)
+	}
+	trunkingCompatibility := map[string]bool{}
+	for _, element := range limits.Elts {
+		entry, ok := element.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := entry.Key.(*ast.BasicLit)
+		if !ok || key.Kind != token.STRING {
+			continue
+		}
+		instanceLimits, ok := entry.Value.(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+		for _, element := range instanceLimits.Elts {
+			field, ok := element.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			if name, ok := field.Key.(*ast.Ident); !ok || name.Name != "IsTrunkingCompatible" {
+				continue
+			}
+			value, ok := field.Value.(*ast.Ident)
+			if !ok || (value.Name != "true" && value.Name != "false") {
+				continue
+			}
+			instanceType, err := strconv.Unquote(key.Value)
+			if err != nil {
+				continue
+			}
+			trunkingCompatibility[instanceType] = value.Name == "true"
+		}
+	}
+	if len(trunkingCompatibility) == 0 {
+		return nil, fmt.Errorf("no instance types with IsTrunkingCompatible found in variable %s of %s", vpcLimitsVar, vpcLimitsURL)
+	}
+	return trunkingCompatibility, nil
+}
+
+// findVarValue returns the map literal that the package-level variable name
+// of file is set to, or nil if there is none.
+func findVarValue(file *ast.File, name string) *ast.CompositeLit {
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			valueSpec, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			for i, ident := range valueSpec.Names {
+				if ident.Name != name || i >= len(valueSpec.Values) {
+					continue
+				}
+				if value, ok := valueSpec.Values[i].(*ast.CompositeLit); ok {
+					if _, ok := value.Type.(*ast.MapType); ok {
+						return value
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTrunkingCompatibility(t *testing.T) {
+	src := `package vpc
+
+var Limits = map[string]*VPCLimits{
+	"m5.large": {
+		Interface:            3,
+		IPv4PerInterface:     10,
+		IsTrunkingCompatible: true,
+		BranchInterface:      9,
+		NetworkCards: []NetworkCard{
+			{
+				MaximumNetworkInterfaces: 3,
+				NetworkCardIndex:         0,
+			},
+		},
+		HypervisorType: "nitro",
+	},
+	"t3.medium": {
+		Interface:            3,
+		IPv4PerInterface:     6,
+		IsTrunkingCompatible: false,
+		HypervisorType:       "nitro",
+	},
+}
+`
+	trunkingCompatibility, err := parseTrunkingCompatibility([]byte(src))
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string]bool{"m5.large": true, "t3.medium": false}, trunkingCompatibility)
+	}
+
+	_, err = parseTrunkingCompatibility([]byte("package vpc\n"))
+	assert.ErrorContains(t, err, "map literal of variable Limits not found")
+
+	// the limits of other variables are not used
+	_, err = parseTrunkingCompatibility([]byte(`package vpc
+
+var OtherLimits = map[string]*VPCLimits{
+	"m5.large": {IsTrunkingCompatible: true},
+}
+`))
+	assert.ErrorContains(t, err, "map literal of variable Limits not found")
+
+	_, err = parseTrunkingCompatibility([]byte(`package vpc
+
+var Limits = map[string]*VPCLimits{
+	"m5.large": {Interface: 3},
+}
+`))
+	assert.ErrorContains(t, err, "no instance types with IsTrunkingCompatible found")
+}